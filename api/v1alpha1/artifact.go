@@ -47,6 +47,14 @@ const (
 	// layer type to Timoni module vendored CUE schemas.
 	TimoniModVendorContentType = "module/vendor"
 
+	// TimoniValuesContentType is the value of ContentTypeAnnotation for setting the
+	// layer type to module values files.
+	TimoniValuesContentType = "values"
+
+	// TimoniPolicyContentType is the value of ContentTypeAnnotation for setting the
+	// layer type to policy pack files.
+	TimoniPolicyContentType = "policy"
+
 	// CueModGenContentType is the value of ContentTypeAnnotation for setting the
 	// content to CUE generated schemas.
 	CueModGenContentType = "cue.mod/gen"
@@ -79,6 +87,11 @@ const (
 	// for listing the default container images referenced by the module,
 	// as a comma-separated sorted list of image references.
 	ImagesAnnotation = "sh.timoni.images"
+
+	// ConfigSchemaAnnotation is the annotation key used on module artifacts
+	// for the JSON Schema of the module's #Config, which allows validating
+	// values files without CUE.
+	ConfigSchemaAnnotation = "sh.timoni.config.schema"
 )
 
 // ArtifactReference contains the information necessary to locate
@@ -16,7 +16,11 @@ limitations under the License.
 
 package v1alpha1
 
-import "cuelang.org/go/cue"
+import (
+	"strings"
+
+	"cuelang.org/go/cue"
+)
 
 const (
 	// BundleAPIVersionSelector is the CUE path for the Timoni's bundle API version.
@@ -45,8 +49,32 @@ const (
 
 	// BundleNameLabelKey is the Kubernetes label key for tracking Timoni's bundle by name.
 	BundleNameLabelKey = "bundle.timoni.sh/name"
+
+	// BundleDigestLabelKey is the Kubernetes label key for tracking the
+	// digest of the OCI artifact a Timoni's bundle was applied from.
+	BundleDigestLabelKey = "bundle.timoni.sh/digest"
+
+	// BundleDigestAnnotationKey is the Kubernetes annotation key holding the
+	// full digest of the OCI artifact a Timoni's bundle was applied from.
+	BundleDigestAnnotationKey = "bundle.timoni.sh/digest"
 )
 
+// BundleDigestLabelValue returns the label value for the given artifact
+// digest. The value holds only the hex-encoded part of the digest, without
+// the '<sha-type>:' prefix, truncated to 63 characters, the maximum length
+// of Kubernetes label values. The label is meant for selecting instances,
+// the full digest is stored in the BundleDigestAnnotationKey annotation.
+func BundleDigestLabelValue(digest string) string {
+	_, hex, found := strings.Cut(digest, ":")
+	if !found {
+		hex = digest
+	}
+	if len(hex) > 63 {
+		hex = hex[:63]
+	}
+	return hex
+}
+
 // Bundle holds the information about the bundle name and the list of instances.
 // +k8s:deepcopy-gen=false
 type Bundle struct {
@@ -77,4 +105,84 @@ type BundleInstance struct {
 
 	// Values hold the user-supplied configuration of this instance.
 	Values cue.Value `json:"values,omitempty"`
+
+	// ArtifactDigest is the digest of the OCI artifact
+	// the bundle was loaded from, if any.
+	ArtifactDigest string `json:"artifactDigest,omitempty"`
+}
+
+const (
+	// BundleRunPending is the status of an instance not yet applied in a bundle run.
+	BundleRunPending = "Pending"
+
+	// BundleRunApplied is the status of an instance applied successfully in a bundle run.
+	BundleRunApplied = "Applied"
+
+	// BundleRunFailed is the status of an instance that failed to apply in a bundle run.
+	BundleRunFailed = "Failed"
+)
+
+// BundleRun holds the progress of the last apply of a bundle on a cluster.
+// +k8s:deepcopy-gen=false
+type BundleRun struct {
+	// Bundle is the name of the bundle.
+	Bundle string `json:"bundle"`
+
+	// Namespace is the namespace where the run record is stored.
+	Namespace string `json:"namespace"`
+
+	// Digest is the digest of the OCI artifact the bundle was loaded from,
+	// or of the bundle computed value when applied from local files.
+	Digest string `json:"digest"`
+
+	// StartTime is the time when the run started in RFC3339 format.
+	StartTime string `json:"startTime"`
+
+	// LastTransitionTime is the time when the run record
+	// was last updated in RFC3339 format.
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+
+	// Instances holds the outcome of each instance in apply order.
+	Instances []BundleRunInstance `json:"instances"`
+}
+
+// BundleRunInstance holds the outcome of an instance in a bundle run.
+// +k8s:deepcopy-gen=false
+type BundleRunInstance struct {
+	// Name is the name of the instance.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the instance.
+	Namespace string `json:"namespace"`
+
+	// Digest is the digest of the instance rendered objects.
+	Digest string `json:"digest,omitempty"`
+
+	// Status is one of Pending, Applied or Failed.
+	Status string `json:"status"`
+
+	// Error is the apply error of a failed instance.
+	Error string `json:"error,omitempty"`
+}
+
+// GetInstance returns the outcome of the given instance, or nil if
+// the instance is not part of the run.
+func (r *BundleRun) GetInstance(name, namespace string) *BundleRunInstance {
+	for i := range r.Instances {
+		if r.Instances[i].Name == name && r.Instances[i].Namespace == namespace {
+			return &r.Instances[i]
+		}
+	}
+	return nil
+}
+
+// CountInstances returns the number of instances with the given status.
+func (r *BundleRun) CountInstances(status string) int {
+	n := 0
+	for _, instance := range r.Instances {
+		if instance.Status == status {
+			n++
+		}
+	}
+	return n
 }
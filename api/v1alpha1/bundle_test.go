@@ -0,0 +1,37 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBundleDigestLabel(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	value := BundleDigestLabelValue(digest)
+	if len(value) != 63 {
+		t.Fatalf("expected a 63 characters label value, got %d", len(value))
+	}
+	if !strings.HasPrefix(digest, "sha256:"+value) {
+		t.Errorf("expected %s to be the beginning of %s", value, digest)
+	}
+	if got := BundleDigestLabelValue("ab12"); got != "ab12" {
+		t.Errorf("expected the digest without prefix to be kept, got %s", got)
+	}
+}
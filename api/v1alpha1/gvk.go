@@ -27,10 +27,17 @@ var (
 	// InstanceKind is the kind name of the Instance type.
 	InstanceKind = "Instance"
 
+	// RuntimeSnapshotKind is the kind name of the RuntimeSnapshot type.
+	RuntimeSnapshotKind = "RuntimeSnapshot"
+
 	// InstanceStorageType is the name of the Kubernetes
 	// Secret type used to store the instance metadata and inventory.
 	InstanceStorageType = "timoni.sh/instance"
 
+	// BundleRunStorageType is the name of the Kubernetes
+	// Secret type used to store the progress of a bundle apply.
+	BundleRunStorageType = "timoni.sh/bundle-run"
+
 	// DeleteInProgressAnnotation marks the instance storage as being deleted.
 	// It is set while a delete waits for the resources to be removed, so the
 	// inventory survives a timeout and the delete can be retried.
@@ -34,8 +34,18 @@ const (
 	// HealthChecksSelector is the CUE path for the Timoni's custom health checks.
 	HealthChecksSelector Selector = "timoni.healthChecks"
 
+	// RequirementsSelector is the CUE path for the Timoni's module requirements.
+	RequirementsSelector Selector = "timoni.requirements"
+
+	// MigrationsSelector is the CUE path for the Timoni's module values migrations.
+	MigrationsSelector Selector = "timoni.migrations"
+
 	// ValuesSelector is the CUE path for the Timoni's module values.
 	ValuesSelector Selector = "values"
+
+	// SensitiveAttribute is the argument of the @timoni() attribute that marks
+	// the #Config fields holding sensitive values, e.g. 'password: string @timoni(sensitive)'.
+	SensitiveAttribute = "sensitive"
 )
 
 // Instance holds the information about the module, values
@@ -48,6 +58,8 @@ type Instance struct {
 	Module ModuleReference `json:"module"`
 
 	// Values hold the user-supplied configuration of this instance.
+	// The values of the fields marked as sensitive are stored
+	// redacted or encrypted with age.
 	Values string `json:"values"`
 
 	// LastTransitionTime is the timestamp (UTC RFC3339) of the last inventory change.
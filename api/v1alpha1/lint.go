@@ -0,0 +1,35 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "fmt"
+
+const (
+	// LintSeverityError is the severity of the lint rules
+	// whose findings fail the vet run.
+	LintSeverityError = "error"
+
+	// LintSeverityWarning is the severity of the lint rules
+	// whose findings are only reported.
+	LintSeverityWarning = "warning"
+)
+
+var (
+	// LintIgnoreAnnotation is the annotation that holds the comma-separated
+	// list of lint rule IDs which are not checked for a Kubernetes resource.
+	LintIgnoreAnnotation = fmt.Sprintf("lint.%s/ignore", GroupVersion.Group)
+)
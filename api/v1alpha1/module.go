@@ -38,6 +38,40 @@ type ModuleReference struct {
 
 	// Annotations of the OCI artifact.
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Requirements declared by the module.
+	// +optional
+	Requirements *ModuleRequirements `json:"requirements,omitempty"`
+}
+
+// ModuleRequirements holds the Kubernetes and Timoni versions
+// supported by a module and the API groups it depends on.
+type ModuleRequirements struct {
+	// KubeVersion is the range of supported Kubernetes versions.
+	// +optional
+	KubeVersion *VersionRange `json:"kubeVersion,omitempty"`
+
+	// TimoniVersion is the range of supported Timoni CLI versions.
+	// +optional
+	TimoniVersion *VersionRange `json:"timoniVersion,omitempty"`
+
+	// APIGroups is the list of API groups, in the format '<group>'
+	// or '<group>/<version>', that must be served by the cluster.
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+}
+
+// VersionRange holds the minimum and maximum semver versions.
+// A maximum version without a patch number includes all the
+// patch releases of that minor version.
+type VersionRange struct {
+	// Min is the minimum version e.g. '1.28.0'.
+	// +optional
+	Min string `json:"min,omitempty"`
+
+	// Max is the maximum version e.g. '1.32'.
+	// +optional
+	Max string `json:"max,omitempty"`
 }
 
 // ImageReference contains the information necessary to locate
@@ -0,0 +1,33 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// PolicyRulesSelector is the CUE path for the rules of a policy pack.
+	PolicyRulesSelector Selector = "rules"
+
+	// PolicySeverityDeny is the severity of the rules whose
+	// violations fail the run.
+	PolicySeverityDeny = "deny"
+
+	// PolicySeverityWarn is the severity of the rules whose
+	// violations are only reported.
+	PolicySeverityWarn = "warn"
+
+	// PolicyMatchAny matches every API group or kind.
+	PolicyMatchAny = "*"
+)
@@ -45,6 +45,22 @@ const (
 
 	// RuntimeValuesSelector is the CUE path for the Timoni's runtime values.
 	RuntimeValuesSelector Selector = "runtime.values"
+
+	// RuntimeProviderKubernetes is the name of the provider
+	// that fetches the runtime values from Kubernetes resources.
+	RuntimeProviderKubernetes string = "k8s"
+
+	// RuntimeListAll is the name used in Kubernetes queries for listing all the resources.
+	RuntimeListAll string = "*"
+
+	// RuntimeLabelSelectorPrefix is the prefix of the label selectors in Kubernetes queries.
+	RuntimeLabelSelectorPrefix string = "selector="
+
+	// RuntimeFieldSelectorPrefix is the prefix of the field selectors in Kubernetes queries.
+	RuntimeFieldSelectorPrefix string = "fields="
+
+	// RuntimeSelectorDelimiter separates the label and field selectors in Kubernetes queries.
+	RuntimeSelectorDelimiter string = ";"
 )
 
 // RuntimeAttribute holds the runtime var name and type.
@@ -160,18 +176,64 @@ func (r *Runtime) SelectClusters(name, group string) []RuntimeCluster {
 }
 
 // RuntimeResourceRef holds the data needed to query the fields
-// of a Kubernetes resource using CUE expressions.
+// of a Kubernetes resource, or of the data returned by another
+// runtime value provider, using CUE expressions.
 type RuntimeResourceRef struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
+	// Provider is the name of the provider that fetches the data.
+	Provider string `json:"provider"`
+
+	// List is set when the query matches a list of Kubernetes resources,
+	// filtered by the label and field selectors, instead of a named resource.
+	List          bool   `json:"list,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// Source is the provider specific part of the query,
+	// empty for Kubernetes resources.
+	Source string `json:"source,omitempty"`
+
 	Expressions map[string]string `json:"selector"`
 	Optional    bool              `json:"optional"`
+
+	// Secret is a flag for hiding the extracted values from the output.
+	Secret bool `json:"secret"`
+}
+
+// String returns the query of the reference.
+func (r *RuntimeResourceRef) String() string {
+	if r.Provider != RuntimeProviderKubernetes {
+		return r.Provider + RuntimeDelimiter + r.Source
+	}
+	parts := []string{r.Provider, r.APIVersion, r.Kind}
+	if r.Namespace != "" {
+		parts = append(parts, r.Namespace)
+	}
+	if !r.List {
+		return strings.Join(append(parts, r.Name), RuntimeDelimiter)
+	}
+
+	var selectors []string
+	if r.LabelSelector != "" {
+		selectors = append(selectors, RuntimeLabelSelectorPrefix+r.LabelSelector)
+	}
+	if r.FieldSelector != "" {
+		selectors = append(selectors, RuntimeFieldSelectorPrefix+r.FieldSelector)
+	}
+	if len(selectors) == 0 {
+		selectors = append(selectors, RuntimeListAll)
+	}
+	return strings.Join(append(parts, strings.Join(selectors, RuntimeSelectorDelimiter)), RuntimeDelimiter)
 }
 
-// RuntimeValue holds the query information for in-cluster values.
+// RuntimeValue holds the query information for runtime values.
 type RuntimeValue struct {
-	// Query in the format 'k8s:<apiVersion>:<kind>:<namespace>:<name>'.
+	// Query in the format '<provider>:<source>', e.g.
+	// 'k8s:<apiVersion>:<kind>:<namespace>:<name>'. For Kubernetes
+	// queries, the name can be '*' or a list of label and field
+	// selectors in the format 'selector=<labels>;fields=<fields>'.
 	Query string `json:"query"`
 
 	// For is a map with key values in the format '<name>: <CUE expression>'.
@@ -179,16 +241,34 @@ type RuntimeValue struct {
 
 	// Optional is a flag for ignoring not found resources.
 	Optional bool `json:"optional"`
+
+	// Secret is a flag for hiding the values from the output.
+	Secret bool `json:"secret"`
 }
 
 // ToResourceRef converts the RuntimeValue to a RuntimeResourceRef by parsing the query data.
+// Queries for providers other than k8s are kept as they are, the provider
+// is responsible for parsing the source.
 func (rv *RuntimeValue) ToResourceRef() (*RuntimeResourceRef, error) {
-	parts := strings.Split(rv.Query, RuntimeDelimiter)
+	provider, source, ok := strings.Cut(rv.Query, RuntimeDelimiter)
+	if !ok || provider == "" || source == "" {
+		return nil, fmt.Errorf("faild to parse '%s': query must be in the format <provider>:<source>", rv.Query)
+	}
 
-	if parts[0] != "k8s" {
-		return nil, fmt.Errorf("faild to parse '%s': query must start with k8s", rv.Query)
+	if provider != RuntimeProviderKubernetes {
+		ref := RuntimeResourceRef{
+			Provider:    provider,
+			Source:      source,
+			Expressions: map[string]string{},
+			Optional:    rv.Optional,
+			Secret:      rv.Secret,
+		}
+		maps.Copy(ref.Expressions, rv.For)
+		return &ref, nil
 	}
 
+	parts := strings.Split(rv.Query, RuntimeDelimiter)
+
 	if len(parts) < 4 {
 		return nil, fmt.Errorf("faild to parse '%s': invalid number of parts", rv.Query)
 	}
@@ -199,18 +279,105 @@ func (rv *RuntimeValue) ToResourceRef() (*RuntimeResourceRef, error) {
 			Kind:       parts[2],
 		},
 		ObjectMeta:  metav1.ObjectMeta{},
+		Provider:    RuntimeProviderKubernetes,
 		Expressions: map[string]string{},
 		Optional:    rv.Optional,
+		Secret:      rv.Secret,
 	}
 
+	if len(parts) > 5 {
+		return nil, fmt.Errorf("faild to parse '%s': invalid number of parts", rv.Query)
+	}
+
+	name := parts[len(parts)-1]
 	if len(parts) == 5 {
 		ref.Namespace = parts[3]
-		ref.Name = parts[4]
-	} else {
-		ref.Name = parts[3]
+	}
+
+	switch {
+	case name == RuntimeListAll:
+		ref.List = true
+	case strings.HasPrefix(name, RuntimeLabelSelectorPrefix) || strings.HasPrefix(name, RuntimeFieldSelectorPrefix):
+		ref.List = true
+		for _, selector := range strings.Split(name, RuntimeSelectorDelimiter) {
+			switch {
+			case strings.HasPrefix(selector, RuntimeLabelSelectorPrefix):
+				ref.LabelSelector = strings.TrimPrefix(selector, RuntimeLabelSelectorPrefix)
+			case strings.HasPrefix(selector, RuntimeFieldSelectorPrefix):
+				ref.FieldSelector = strings.TrimPrefix(selector, RuntimeFieldSelectorPrefix)
+			default:
+				return nil, fmt.Errorf("faild to parse '%s': invalid selector '%s'", rv.Query, selector)
+			}
+		}
+	default:
+		ref.Name = name
 	}
 
 	maps.Copy(ref.Expressions, rv.For)
 
 	return &ref, nil
 }
+
+// RuntimeSnapshot holds the values resolved by a Runtime for each cluster,
+// used to build bundles without access to the clusters.
+// +k8s:deepcopy-gen=false
+type RuntimeSnapshot struct {
+	// APIVersion is the version of the snapshot format.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is always RuntimeSnapshot.
+	Kind string `json:"kind"`
+
+	// Name of the runtime.
+	Name string `json:"name"`
+
+	// CreatedAt is the time when the values were read in RFC3339 format.
+	CreatedAt string `json:"createdAt"`
+
+	// Clusters holds the values resolved for each cluster.
+	Clusters []RuntimeSnapshotCluster `json:"clusters"`
+}
+
+// RuntimeSnapshotCluster holds the values resolved for a cluster.
+// +k8s:deepcopy-gen=false
+type RuntimeSnapshotCluster struct {
+	// Name of the cluster.
+	Name string `json:"name"`
+
+	// Group name of the cluster.
+	Group string `json:"group"`
+
+	// Values holds the runtime values.
+	Values map[string]string `json:"values,omitempty"`
+
+	// Secrets holds the values of the queries marked as secret
+	// and of the queries selecting Kubernetes Secrets.
+	Secrets map[string]string `json:"secrets,omitempty"`
+
+	// Encrypted is set when the secret values are age encrypted.
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+// Runtime returns a Runtime with the snapshot clusters,
+// for selecting clusters by name and group.
+func (s *RuntimeSnapshot) Runtime() *Runtime {
+	rt := &Runtime{
+		Name: s.Name,
+		Refs: []RuntimeResourceRef{},
+	}
+	for _, c := range s.Clusters {
+		rt.Clusters = append(rt.Clusters, RuntimeCluster{Name: c.Name, Group: c.Group})
+	}
+	return rt
+}
+
+// GetCluster returns the values of the given cluster,
+// or nil if the cluster is not part of the snapshot.
+func (s *RuntimeSnapshot) GetCluster(name string) *RuntimeSnapshotCluster {
+	for i := range s.Clusters {
+		if s.Clusters[i].Name == name {
+			return &s.Clusters[i]
+		}
+	}
+	return nil
+}
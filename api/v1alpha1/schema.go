@@ -44,6 +44,12 @@ var (
 	// The health check definitions referenced by #Timoni are inlined along
 	// with it so that modules need no vendored schema update to use them.
 	InstanceSchema = mustInlineSchema("timoni: #Timoni", "timoni.cue", "healthcheck.cue")
+
+	// PolicySchema defines the v1alpha1 CUE schema for Timoni's policy packs.
+	PolicySchema = mustInlineSchema("rules: [string]: #Rule", "policy.cue")
+
+	// TestCaseSchema defines the v1alpha1 CUE schema for Timoni's module test cases.
+	TestCaseSchema = mustInlineSchema("", "test.cue")
 )
 
 // mustInlineSchema reads the embedded core schema files and returns their
@@ -37,6 +37,8 @@ func TestSchemas(t *testing.T) {
 		{"bundle", BundleSchema, "#Bundle", "bundle: #Bundle"},
 		{"runtime", RuntimeSchema, "#Runtime", ""},
 		{"instance", InstanceSchema, "#Timoni", "timoni: #Timoni"},
+		{"policy", PolicySchema, "#Rule", "rules: [string]: #Rule"},
+		{"test case", TestCaseSchema, "#TestCase", ""},
 	}
 
 	ctx := cuecontext.New()
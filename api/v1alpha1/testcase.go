@@ -0,0 +1,54 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// TestsDir is the module directory holding the test cases.
+	TestsDir = "tests"
+
+	// TestSnapshotsDir is the directory under TestsDir holding the
+	// rendered objects of each test case.
+	TestSnapshotsDir = "__snapshots__"
+
+	// TestValuesSelector is the CUE path for the test case values.
+	TestValuesSelector Selector = "values"
+
+	// TestApplySelector is the CUE path for the rendered objects of a test case.
+	TestApplySelector Selector = "apply"
+
+	// TestAssertSelector is the CUE path for the test case assertions.
+	TestAssertSelector Selector = "assert"
+
+	// TestExpectErrorSelector is the CUE path for the expected build error.
+	TestExpectErrorSelector Selector = "expectError"
+
+	// TestHealthChecksSelector is the CUE path for the expected health check results.
+	TestHealthChecksSelector Selector = "healthChecks"
+
+	// TestDescriptionSelector is the CUE path for the test case description.
+	TestDescriptionSelector Selector = "description"
+)
+
+// TestHealthCheck holds the expected health check result of an object.
+// +k8s:deepcopy-gen=false
+type TestHealthCheck struct {
+	// Object is the Kubernetes object evaluated by the health check.
+	Object map[string]any `json:"object"`
+
+	// Status is the expected health check result.
+	Status string `json:"status"`
+}
@@ -90,6 +90,11 @@ func (in *ModuleReference) DeepCopyInto(out *ModuleReference) {
 			(*out)[key] = val
 		}
 	}
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = new(ModuleRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleReference.
@@ -102,6 +107,36 @@ func (in *ModuleReference) DeepCopy() *ModuleReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleRequirements) DeepCopyInto(out *ModuleRequirements) {
+	*out = *in
+	if in.KubeVersion != nil {
+		in, out := &in.KubeVersion, &out.KubeVersion
+		*out = new(VersionRange)
+		**out = **in
+	}
+	if in.TimoniVersion != nil {
+		in, out := &in.TimoniVersion, &out.TimoniVersion
+		*out = new(VersionRange)
+		**out = **in
+	}
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleRequirements.
+func (in *ModuleRequirements) DeepCopy() *ModuleRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceInventory) DeepCopyInto(out *ResourceInventory) {
 	*out = *in
@@ -239,3 +274,18 @@ func (in *RuntimeValue) DeepCopy() *RuntimeValue {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionRange) DeepCopyInto(out *VersionRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionRange.
+func (in *VersionRange) DeepCopy() *VersionRange {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionRange)
+	in.DeepCopyInto(out)
+	return out
+}
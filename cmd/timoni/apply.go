@@ -49,6 +49,7 @@ The apply command performs the following steps:
 - If the registry credentials are specified with '--creds', these take priority over the docker ones.
 - Creates the specified '--namespace' if it doesn't exist.
 - Merges all the values supplied with '--values' on top of the default values found in the module.
+- Merges the values overrides supplied with '--set', '--set-string' and '--set-file' last.
 - Builds the module by passing the instance name, namespace and values.
 - Labels the resulting Kubernetes resources with the instance name and namespace.
 - Applies the Kubernetes resources on the cluster.
@@ -84,6 +85,25 @@ The apply command performs the following steps:
   timoni apply -n apps app oci://docker.io/org/module \
   --values ./values-1.yaml \
   --values ./values-2.json
+
+  # Install or upgrade an instance with values overrides set on the command line
+  timoni apply -n apps app oci://docker.io/org/module \
+  --values ./values-1.cue \
+  --set replicas=2 \
+  --set-string image.tag=1.0.0 \
+  --set-file config.motd=./motd.txt
+
+  # Install or upgrade an instance with values from a signed OCI artifact and an HTTPS URL
+  timoni apply -n apps app oci://docker.io/org/module \
+  --values https://example.com/values/common.yaml \
+  --values oci://docker.io/org/values:production \
+  --verify-values=cosign \
+  --values-cosign-key=/path/to/cosign.pub
+
+  # Install or upgrade an instance with values from a SOPS encrypted file
+  timoni apply -n apps app oci://docker.io/org/module \
+  --values ./secrets.enc.yaml \
+  --sops-age-key-file ./age.txt
 `,
 	RunE: runApplyCmd,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -105,12 +125,15 @@ type applyFlags struct {
 	pkg                flags.Package
 	digest             flags.Digest
 	valuesFiles        []string
+	valuesSet          valuesSetFlags
 	dryrun             bool
 	diff               bool
 	wait               bool
 	force              bool
 	overwriteOwnership bool
+	ageRecipients      []string
 	creds              flags.Credentials
+	policies           []string
 }
 
 var applyArgs applyFlags
@@ -120,7 +143,9 @@ func init() {
 	applyCmd.Flags().VarP(&applyArgs.pkg, applyArgs.pkg.Type(), applyArgs.pkg.Shorthand(), applyArgs.pkg.Description())
 	applyCmd.Flags().VarP(&applyArgs.digest, applyArgs.digest.Type(), applyArgs.digest.Shorthand(), applyArgs.digest.Description())
 	applyCmd.Flags().StringSliceVarP(&applyArgs.valuesFiles, "values", "f", nil,
-		"The local path to values files (cue, yaml or json format).")
+		"The path to values files (cue, yaml or json format), an HTTPS URL or an OCI artifact URL.")
+	applyArgs.valuesSet.addFlags(applyCmd)
+	addValuesVerifyFlags(applyCmd)
 	applyCmd.Flags().BoolVar(&applyArgs.force, "force", false,
 		"Recreate immutable Kubernetes resources.")
 	applyCmd.Flags().BoolVar(&applyArgs.overwriteOwnership, "overwrite-ownership", false,
@@ -131,7 +156,10 @@ func init() {
 		"Perform a server-side apply dry run and prints the diff.")
 	applyCmd.Flags().BoolVar(&applyArgs.wait, "wait", true,
 		"Wait for the applied Kubernetes objects to become ready.")
+	applyCmd.Flags().StringSliceVar(&applyArgs.ageRecipients, "age-recipient", nil,
+		"The age public keys used to encrypt the values marked as sensitive before storage, the sensitive values are redacted if not specified.")
 	applyCmd.Flags().Var(&applyArgs.creds, applyArgs.creds.Type(), applyArgs.creds.Description())
+	addPolicyFlag(applyCmd, &applyArgs.policies)
 	rootCmd.AddCommand(applyCmd)
 }
 
@@ -214,17 +242,46 @@ func runApplyCmd(cmd *cobra.Command, args []string) error {
 
 	log.Info(fmt.Sprintf("using module %s version %s", mod.Name, mod.Version))
 
-	if len(applyArgs.valuesFiles) > 0 {
-		valuesCue, err := convertToCue(cmd, applyArgs.valuesFiles)
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	stored, migrations, err := instanceValuesMigrations(ctx, log, kubeconfigArgs, builder,
+		applyArgs.name, *kubeconfigArgs.Namespace, mod.Version)
+	if err != nil {
+		return err
+	}
+
+	decrypter := newDecrypter()
+	valuesFiles, err := resolveRemoteValues(cmd, applyArgs.valuesFiles, tmpDir, applyArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if len(valuesFiles) > 0 || applyArgs.valuesSet.isSet() {
+		valuesCue, err := convertToCue(cmd, valuesFiles, decrypter)
 		if err != nil {
 			return err
 		}
-		err = builder.OverlayValuesFile(valuesCue)
+		setCue, err := applyArgs.valuesSet.toCue()
+		if err != nil {
+			return err
+		}
+		valuesCue = append(valuesCue, setCue...)
+		if len(migrations) > 0 {
+			err = overlayMigratedValues(builder, valuesCue, migrations)
+		} else {
+			err = builder.OverlayValuesFile(valuesCue)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
+	if applyArgs.diff && len(migrations) > 0 {
+		if err := printValuesMigrationDiff(cmd.OutOrStdout(), tmpDir, builder, stored, migrations, decrypter.Redact); err != nil {
+			return err
+		}
+	}
+
 	kubeVersion, err := runtime.ServerVersion(kubeconfigArgs)
 	if err != nil {
 		return err
@@ -232,13 +289,34 @@ func runApplyCmd(cmd *cobra.Command, args []string) error {
 
 	builder.SetVersionInfo(mod.Version, kubeVersion)
 
+	apiVersions, err := runtime.ServerAPIVersions(kubeconfigArgs)
+	if err != nil {
+		return err
+	}
+
+	builder.SetAPIVersions(apiVersions)
+
 	buildResult, err := builder.Build()
 	if err != nil {
 		return describeErr(f.GetModuleRoot(), "build failed", err)
 	}
 
-	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
-	defer cancel()
+	mod.Requirements, err = builder.GetRequirements(buildResult)
+	if err != nil {
+		return err
+	}
+
+	if err := engine.CheckRequirements(mod.Requirements, kubeVersion, VERSION, apiVersions); err != nil {
+		return fmt.Errorf("module requirements not met: %w", err)
+	}
+
+	policies, err := loadPolicies(cmd, applyArgs.policies, tmpDir, applyArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if err := enforcePolicies(log, policies, builder, buildResult); err != nil {
+		return err
+	}
 
 	instance := &apiv1.BundleInstance{
 		Name:      applyArgs.name,
@@ -253,11 +331,13 @@ func runApplyCmd(cmd *cobra.Command, args []string) error {
 			Wait:               applyArgs.wait,
 			Force:              applyArgs.force,
 			OverwriteOwnership: applyArgs.overwriteOwnership,
+			AgeRecipients:      applyArgs.ageRecipients,
 		},
 		&reconciler.InteractiveOptions{
 			DryRun:        applyArgs.dryrun,
 			Diff:          applyArgs.diff,
 			DiffOutput:    cmd.OutOrStdout(),
+			Redact:        decrypter.Redact,
 			ProgressStart: logger.StartSpinner,
 		},
 		rootArgs.timeout,
@@ -427,3 +427,35 @@ func TestApply_GlobalResources(t *testing.T) {
 		t.Log("\n", output)
 	})
 }
+
+func TestApply_StorageError(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	modPath := "testdata/module"
+	name := rnd("my-instance")
+	namespace := rnd("my-namespace")
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace},
+	}
+	g.Expect(envTestClient.Create(ctx, ns)).To(Succeed())
+
+	// An instance storage without data must fail the apply,
+	// instead of being treated as a new instance.
+	storage := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%s", apiv1.FieldManager, name),
+			Namespace: namespace,
+		},
+	}
+	g.Expect(envTestClient.Create(ctx, storage)).To(Succeed())
+
+	_, err := executeCommand(fmt.Sprintf(
+		"apply -n %s %s %s -p main",
+		namespace,
+		name,
+		modPath,
+	))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("instance data not found"))
+}
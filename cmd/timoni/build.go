@@ -41,6 +41,7 @@ import (
 	"github.com/stefanprodan/timoni/internal/engine/fetcher"
 	"github.com/stefanprodan/timoni/internal/flags"
 	"github.com/stefanprodan/timoni/internal/mask"
+	"github.com/stefanprodan/timoni/internal/sops"
 )
 
 var buildCmd = &cobra.Command{
@@ -55,6 +56,17 @@ var buildCmd = &cobra.Command{
   timoni build app ./path/to/module \
   --values ./values-1.cue \
   --values ./values-2.cue
+
+  # Build an instance with values overrides set on the command line
+  timoni build app ./path/to/module \
+  --values ./values-1.cue \
+  --set replicas=2 \
+  --set-string image.tag=1.0.0
+
+  # Build an instance for a cluster that serves the Prometheus Operator APIs
+  timoni build app ./path/to/module \
+  --api-versions monitoring.coreos.com/v1 \
+  --api-versions monitoring.coreos.com/v1/ServiceMonitor
 `,
 	RunE: runBuildCmd,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -76,9 +88,12 @@ type buildFlags struct {
 	pkg         flags.Package
 	digest      flags.Digest
 	valuesFiles []string
+	valuesSet   valuesSetFlags
 	output      string
 	maskSecrets bool
+	apiVersions []string
 	creds       flags.Credentials
+	policies    []string
 }
 
 var buildArgs buildFlags
@@ -88,12 +103,17 @@ func init() {
 	buildCmd.Flags().VarP(&buildArgs.pkg, buildArgs.pkg.Type(), buildArgs.pkg.Shorthand(), buildArgs.pkg.Description())
 	buildCmd.Flags().VarP(&buildArgs.digest, buildArgs.digest.Type(), buildArgs.digest.Shorthand(), buildArgs.digest.Description())
 	buildCmd.Flags().StringSliceVarP(&buildArgs.valuesFiles, "values", "f", nil,
-		"The local path to values files (cue, yaml or json format).")
+		"The path to values files (cue, yaml or json format), an HTTPS URL or an OCI artifact URL.")
+	buildArgs.valuesSet.addFlags(buildCmd)
+	addValuesVerifyFlags(buildCmd)
 	buildCmd.Flags().StringVarP(&buildArgs.output, "output", "o", "yaml",
 		"The format in which the Kubernetes objects should be printed, can be 'yaml' or 'json'.")
 	buildCmd.Flags().BoolVar(&buildArgs.maskSecrets, "mask-secrets", false,
 		"Hide the values of Kubernetes Secrets in the printed objects.")
+	buildCmd.Flags().StringSliceVar(&buildArgs.apiVersions, "api-versions", nil,
+		"The API versions exposed to the module as served by the cluster, in the format '<group>/<version>' or '<group>/<version>/<kind>'.")
 	buildCmd.Flags().Var(&buildArgs.creds, buildArgs.creds.Type(), buildArgs.creds.Description())
+	addPolicyFlag(buildCmd, &buildArgs.policies)
 
 	rootCmd.AddCommand(buildCmd)
 }
@@ -167,22 +187,52 @@ func runBuildCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(buildArgs.valuesFiles) > 0 {
-		valuesCue, err := convertToCue(cmd, buildArgs.valuesFiles)
+	valuesFiles, err := resolveRemoteValues(cmd, buildArgs.valuesFiles, tmpDir, buildArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if len(valuesFiles) > 0 || buildArgs.valuesSet.isSet() {
+		valuesCue, err := convertToCue(cmd, valuesFiles, newDecrypter())
+		if err != nil {
+			return err
+		}
+		setCue, err := buildArgs.valuesSet.toCue()
 		if err != nil {
 			return err
 		}
+		valuesCue = append(valuesCue, setCue...)
 		err = builder.OverlayValuesFile(valuesCue)
 		if err != nil {
 			return err
 		}
 	}
 
+	builder.SetAPIVersions(buildArgs.apiVersions)
+
 	buildResult, err := builder.Build()
 	if err != nil {
 		return describeErr(f.GetModuleRoot(), "build failed", err)
 	}
 
+	requirements, err := builder.GetRequirements(buildResult)
+	if err != nil {
+		return err
+	}
+
+	// The Kubernetes version can only be checked against a cluster, the API
+	// groups are checked against the API versions given with --api-versions.
+	if err := engine.CheckRequirements(requirements, "", VERSION, buildArgs.apiVersions); err != nil {
+		return fmt.Errorf("module requirements not met: %w", err)
+	}
+
+	policies, err := loadPolicies(cmd, buildArgs.policies, tmpDir, buildArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if err := enforcePolicies(LoggerFrom(cmd.Context()), policies, builder, buildResult); err != nil {
+		return err
+	}
+
 	apiVer, err := builder.GetAPIVersion(buildResult)
 	if err != nil {
 		return err
@@ -243,7 +293,9 @@ func runBuildCmd(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func convertToCue(cmd *cobra.Command, paths []string) ([][]byte, error) {
+// convertToCue reads the values files and converts them to CUE. The YAML
+// and JSON files encrypted with SOPS are decrypted in memory.
+func convertToCue(cmd *cobra.Command, paths []string, decrypter *sops.Decrypter) ([][]byte, error) {
 	valuesCue := make([][]byte, len(paths))
 	for i, path := range paths {
 		var (
@@ -267,6 +319,15 @@ func convertToCue(cmd *cobra.Command, paths []string) ([][]byte, error) {
 			return nil, fmt.Errorf("could not read values file at %s: %w", path, err)
 		}
 
+		if (ext == ".json" || ext == ".yaml" || ext == ".yml") && sops.IsEncrypted(bs) {
+			bs, err = decrypter.Decrypt(bs)
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt values file at %s: %w", path, err)
+			}
+			// the decrypted document is always YAML
+			ext = ".yaml"
+		}
+
 		var node ast.Node
 
 		switch ext {
@@ -295,3 +356,71 @@ func convertToCue(cmd *cobra.Command, paths []string) ([][]byte, error) {
 	}
 	return valuesCue, nil
 }
+
+// valuesSetFlags holds the values overrides set on the command line.
+type valuesSetFlags struct {
+	values  []string
+	strings []string
+	files   []string
+}
+
+func (f *valuesSetFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.values, "set", nil,
+		"Set a value in the format 'path.to.field=value', the booleans, numbers, null and JSON objects or arrays keep their type (can be specified multiple times).")
+	cmd.Flags().StringArrayVar(&f.strings, "set-string", nil,
+		"Set a string value in the format 'path.to.field=value' (can be specified multiple times).")
+	cmd.Flags().StringArrayVar(&f.files, "set-file", nil,
+		"Set a string value to the content of a file in the format 'path.to.field=filepath' (can be specified multiple times).")
+}
+
+func (f *valuesSetFlags) isSet() bool {
+	return len(f.values) > 0 || len(f.strings) > 0 || len(f.files) > 0
+}
+
+// toCue converts the values overrides to CUE overlays, one for each field,
+// in the order --set, --set-string and --set-file. The overlays are meant
+// to be merged after the values files, so the last override of a field wins.
+func (f *valuesSetFlags) toCue() ([][]byte, error) {
+	var overlays [][]byte
+	add := func(s string, toExpr func(string) (ast.Expr, error)) error {
+		path, raw, err := engine.ParseSetValue(s)
+		if err != nil {
+			return err
+		}
+		value, err := toExpr(raw)
+		if err != nil {
+			return err
+		}
+		overlay, err := engine.SetValueOverlay(path, value)
+		if err != nil {
+			return err
+		}
+		overlays = append(overlays, overlay)
+		return nil
+	}
+
+	for _, s := range f.values {
+		if err := add(s, engine.TypedValueExpr); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range f.strings {
+		if err := add(s, func(raw string) (ast.Expr, error) {
+			return ast.NewString(raw), nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range f.files {
+		if err := add(s, func(raw string) (ast.Expr, error) {
+			data, err := os.ReadFile(raw)
+			if err != nil {
+				return nil, fmt.Errorf("could not read values file at %s: %w", raw, err)
+			}
+			return ast.NewString(string(data)), nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return overlays, nil
+}
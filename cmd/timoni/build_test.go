@@ -19,6 +19,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -97,6 +99,92 @@ func TestBuild(t *testing.T) {
 		}
 	})
 
+	t.Run("builds module with values set on the command line", func(t *testing.T) {
+		g := NewWithT(t)
+		name := rnd("my-instance")
+		namespace := rnd("my-namespace")
+		output, err := executeCommand(fmt.Sprintf(
+			"build -n %s %s %s -f %s --set domain=example.org --set-string metadata.annotations.scope=1 -p main -o yaml",
+			namespace,
+			name,
+			modPath,
+			modPath+"-values/example.com.cue",
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(ContainSubstring("tcp://example.org"))
+
+		objects, err := ssautil.ReadObjects(strings.NewReader(output))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, o := range objects {
+			g.Expect(o.GetAnnotations()).To(HaveKeyWithValue("scope", "1"))
+		}
+	})
+
+	t.Run("builds module with values from an OCI artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		name := rnd("my-instance")
+		namespace := rnd("my-namespace")
+		aURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-values"))
+
+		valuesDir := t.TempDir()
+		data, err := os.ReadFile(modPath + "-values/example.com.cue")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(os.WriteFile(filepath.Join(valuesDir, "values.cue"), data, 0600)).To(Succeed())
+
+		_, err = executeCommand(fmt.Sprintf(
+			"artifact push oci://%s -f %s -t latest --content-type=values",
+			aURL,
+			valuesDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		output, err := executeCommand(fmt.Sprintf(
+			"build -n %s %s %s -f oci://%s:latest -p main -o yaml",
+			namespace,
+			name,
+			modPath,
+			aURL,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("tcp://example.com"))
+	})
+
+	t.Run("fails to verify values without an artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"build my-instance %s -f %s --verify-values=cosign -p main",
+			modPath,
+			modPath+"-values/example.com.cue",
+		))
+		g.Expect(err).To(MatchError(ContainSubstring("--verify-values requires a values artifact")))
+	})
+
+	t.Run("fails for artifact without values content type", func(t *testing.T) {
+		g := NewWithT(t)
+		aURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-values"))
+
+		valuesDir := t.TempDir()
+		data, err := os.ReadFile(modPath + "-values/example.com.cue")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(os.WriteFile(filepath.Join(valuesDir, "values.cue"), data, 0600)).To(Succeed())
+
+		_, err = executeCommand(fmt.Sprintf(
+			"artifact push oci://%s -f %s -t latest --content-type=generic",
+			aURL,
+			valuesDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = executeCommand(fmt.Sprintf(
+			"build my-instance %s -f oci://%s:latest -p main",
+			modPath,
+			aURL,
+		))
+		g.Expect(err).To(MatchError(ContainSubstring("content type 'values'")))
+	})
+
 	t.Run("builds module with YAML and JSON values", func(t *testing.T) {
 		g := NewWithT(t)
 		name := rnd("my-instance")
@@ -123,6 +211,22 @@ func TestBuild(t *testing.T) {
 		}
 	})
 
+	t.Run("builds module with SOPS encrypted values", func(t *testing.T) {
+		g := NewWithT(t)
+		name := rnd("my-instance")
+		namespace := rnd("my-namespace")
+		output, err := executeCommand(fmt.Sprintf(
+			"build -n %s %s %s -f %s --sops-age-key-file %s -p main -o yaml",
+			namespace,
+			name,
+			modPath,
+			modPath+"-values/example.com.enc.yaml",
+			modPath+"-values/age.txt",
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("tcp://sops.example.com"))
+	})
+
 	t.Run("builds module with merged values", func(t *testing.T) {
 		g := NewWithT(t)
 		name := rnd("my-instance")
@@ -17,7 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/runtime"
 )
 
 type bundleFlags struct {
@@ -26,6 +31,9 @@ type bundleFlags struct {
 	runtimeCluster      string
 	runtimeClusterGroup string
 	workdir             string
+	runtimeSnapshot     string
+	runtimeFromArtifact bool
+	localProviders      localProviders
 }
 
 var bundleArgs bundleFlags
@@ -46,5 +54,36 @@ func init() {
 		"Filter runtime clusters by group.")
 	bundleCmd.PersistentFlags().StringVar(&bundleArgs.workdir, "workdir", "",
 		"The local path to the CUE module root (the directory containing cue.mod), used to resolve imports in the bundle and runtime definitions. Defaults to the current directory.")
+	bundleArgs.localProviders.addFlags(bundleCmd.PersistentFlags())
 	rootCmd.AddCommand(bundleCmd)
 }
+
+// addRuntimeSnapshotFlag registers the --runtime-snapshot flag, for the
+// commands that can build a bundle without access to the clusters.
+func addRuntimeSnapshotFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&bundleArgs.runtimeSnapshot, "runtime-snapshot", "",
+		"The local path to a snapshot file written by 'timoni runtime build --output', used instead of querying the clusters.")
+}
+
+// loadRuntimeSnapshot reads the snapshot set with --runtime-snapshot,
+// or returns nil if the flag is not set.
+func loadRuntimeSnapshot() (*apiv1.RuntimeSnapshot, error) {
+	if bundleArgs.runtimeSnapshot == "" {
+		return nil, nil
+	}
+	if len(bundleArgs.runtimeFiles) > 0 && !bundleArgs.runtimeFromArtifact {
+		return nil, errors.New("--runtime-snapshot can't be used together with --runtime")
+	}
+	return runtime.ReadSnapshot(bundleArgs.runtimeSnapshot)
+}
+
+// bundleLocalProviders returns the local providers enabled for the
+// bundle runtime. These are never enabled for the runtime definitions
+// loaded from an artifact, as they could read environment variables
+// and local files, or run arbitrary commands.
+func bundleLocalProviders() localProviders {
+	if bundleArgs.runtimeFromArtifact {
+		return localProviders{}
+	}
+	return bundleArgs.localProviders
+}
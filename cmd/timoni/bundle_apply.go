@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -24,11 +25,15 @@ import (
 	"maps"
 	"os"
 	"path"
+	"strings"
+	"sync"
 	"time"
 
+	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 	"github.com/stefanprodan/timoni/internal/engine"
@@ -37,6 +42,7 @@ import (
 	"github.com/stefanprodan/timoni/internal/logger"
 	"github.com/stefanprodan/timoni/internal/reconciler"
 	"github.com/stefanprodan/timoni/internal/runtime"
+	"github.com/stefanprodan/timoni/internal/sops"
 )
 
 var bundleApplyCmd = &cobra.Command{
@@ -58,20 +64,45 @@ var bundleApplyCmd = &cobra.Command{
 
   # Pass secret values from stdin
   cat ./bundle_secrets.cue | timoni bundle apply -f ./bundle.cue -f -
+
+  # Verify and apply a bundle from a container registry, using the
+  # runtime definitions included in the artifact
+  timoni bundle apply -f oci://ghcr.io/org/bundles/app:1.0.0 \
+  --verify=cosign \
+  --cosign-key=/path/to/cosign.pub
+
+  # Update the runtime clusters group by group, two clusters at a time
+  timoni bundle apply -f bundle.cue -r runtime.cue \
+  --rollout-strategy group \
+  --max-unavailable-clusters 2
+
+  # Resume an interrupted apply, skipping the instances already applied
+  timoni bundle apply -f bundle.cue --resume
+
+  # Apply only the frontend and the backend instances of a bundle
+  timoni bundle apply -f bundle.cue \
+  --instance frontend \
+  --instance 'backend-*'
 `,
 	Args: cobra.NoArgs,
 	RunE: runBundleApplyCmd,
 }
 
 type bundleApplyFlags struct {
-	pkg                flags.Package
-	files              []string
-	dryrun             bool
-	diff               bool
-	wait               bool
-	force              bool
-	overwriteOwnership bool
-	creds              flags.Credentials
+	pkg                    flags.Package
+	files                  []string
+	dryrun                 bool
+	diff                   bool
+	wait                   bool
+	force                  bool
+	overwriteOwnership     bool
+	creds                  flags.Credentials
+	rolloutStrategy        string
+	maxUnavailableClusters int
+	haltOnFailure          bool
+	resume                 bool
+	ageRecipients          []string
+	policies               []string
 }
 
 var bundleApplyArgs bundleApplyFlags
@@ -79,7 +110,7 @@ var bundleApplyArgs bundleApplyFlags
 func init() {
 	bundleApplyCmd.Flags().VarP(&bundleApplyArgs.pkg, bundleApplyArgs.pkg.Type(), bundleApplyArgs.pkg.Shorthand(), bundleApplyArgs.pkg.Description())
 	bundleApplyCmd.Flags().StringSliceVarP(&bundleApplyArgs.files, "file", "f", nil,
-		"The local path to bundle.cue files.")
+		"The local path to bundle.cue files or the OCI URL of a bundle artifact.")
 	bundleApplyCmd.Flags().BoolVar(&bundleApplyArgs.force, "force", false,
 		"Recreate immutable Kubernetes resources.")
 	bundleApplyCmd.Flags().BoolVar(&bundleApplyArgs.overwriteOwnership, "overwrite-ownership", false,
@@ -91,6 +122,19 @@ func init() {
 	bundleApplyCmd.Flags().BoolVar(&bundleApplyArgs.wait, "wait", true,
 		"Wait for the applied Kubernetes objects to become ready.")
 	bundleApplyCmd.Flags().Var(&bundleApplyArgs.creds, bundleApplyArgs.creds.Type(), bundleApplyArgs.creds.Description())
+	addPolicyFlag(bundleApplyCmd, &bundleApplyArgs.policies)
+	bundleApplyCmd.Flags().StringVar(&bundleApplyArgs.rolloutStrategy, "rollout-strategy", rolloutSerial,
+		fmt.Sprintf("The order in which the runtime clusters are updated, can be one of: %s.", strings.Join(rolloutStrategies, ", ")))
+	bundleApplyCmd.Flags().IntVar(&bundleApplyArgs.maxUnavailableClusters, "max-unavailable-clusters", 0,
+		"The maximum number of clusters updated at the same time within a rollout stage, defaults to no limit.")
+	bundleApplyCmd.Flags().BoolVar(&bundleApplyArgs.haltOnFailure, "halt-on-failure", true,
+		"Stop the rollout on the remaining clusters after a cluster fails.")
+	bundleApplyCmd.Flags().BoolVar(&bundleApplyArgs.resume, "resume", false,
+		"Skip the instances applied successfully in the previous run, if their rendered objects are unchanged.")
+	bundleApplyCmd.Flags().StringSliceVar(&bundleApplyArgs.ageRecipients, "age-recipient", nil,
+		"The age public keys used to encrypt the values marked as sensitive before storage, the sensitive values are redacted if not specified.")
+	addBundleVerifyFlags(bundleApplyCmd)
+	addBundleSelectFlags(bundleApplyCmd)
 	bundleCmd.AddCommand(bundleApplyCmd)
 }
 
@@ -100,6 +144,12 @@ func runBundleApplyCmd(cmd *cobra.Command, _ []string) error {
 	if len(files) == 0 {
 		return errors.New("no bundle provided with -f")
 	}
+	if bundleApplyArgs.maxUnavailableClusters < 0 {
+		return errors.New("--max-unavailable-clusters must not be negative")
+	}
+	if err := bundleSelectArgs.validate(); err != nil {
+		return err
+	}
 	var stdinFile string
 	for i, file := range files {
 		if file == "-" {
@@ -121,6 +171,11 @@ func runBundleApplyCmd(cmd *cobra.Command, _ []string) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
+	files, artifactDigest, err := resolveBundleArtifact(cmd, files, tmpDir, bundleApplyArgs.creds.String())
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 	defer cancel()
 
@@ -132,6 +187,8 @@ func runBundleApplyCmd(cmd *cobra.Command, _ []string) error {
 	cuectx := cuecontext.New()
 	bm := engine.NewBundleBuilder(cuectx, files)
 	bm.SetWorkdir(workdir)
+	decrypter := newDecrypter()
+	bm.SetDecrypter(decrypter)
 
 	runtimeValues := make(map[string]string)
 
@@ -149,104 +206,321 @@ func runBundleApplyCmd(cmd *cobra.Command, _ []string) error {
 		return errors.New("no cluster found")
 	}
 
+	stages, err := rolloutStages(bundleApplyArgs.rolloutStrategy, clusters)
+	if err != nil {
+		return err
+	}
+
 	ctxPull, cancel := context.WithTimeout(ctx, rootArgs.timeout)
 	defer cancel()
 
-	moduleCache := make(map[moduleCacheKey]*fetchedModule)
+	policies, err := loadPolicies(cmd, bundleApplyArgs.policies, tmpDir, bundleApplyArgs.creds.String())
+	if err != nil {
+		return err
+	}
+
+	run := &bundleApplyRun{
+		start:         start,
+		bm:            bm,
+		refs:          rt.Refs,
+		runtimeValues: runtimeValues,
+		decrypter:     decrypter,
+		tmpDir:        tmpDir,
+		digest:        artifactDigest,
+		ctxPull:       ctxPull,
+		moduleCache:   make(map[moduleCacheKey]*fetchedModule),
+		policies:      policies,
+		concurrent:    rolloutConcurrency(stages, bundleApplyArgs.maxUnavailableClusters) > 1,
+		out:           cmd.OutOrStdout(),
+	}
 
-	for _, cluster := range clusters {
-		kubeconfigArgs.Context = &cluster.KubeContext
+	if policies != nil {
+		if err := run.checkPolicies(ctx, clusters); err != nil {
+			return err
+		}
+	}
+
+	results := rolloutClusters(ctx, stages,
+		bundleApplyArgs.maxUnavailableClusters,
+		bundleApplyArgs.haltOnFailure,
+		run.applyCluster)
+
+	if len(clusters) > 1 || !clusters[0].IsDefault() {
+		logRolloutSummary(LoggerFrom(cmd.Context()), results)
+	}
+
+	return rolloutErr(results)
+}
+
+// bundleApplyRun holds the state shared between the clusters
+// targeted by a bundle apply.
+type bundleApplyRun struct {
+	start         time.Time
+	bm            *engine.BundleBuilder
+	refs          []apiv1.RuntimeResourceRef
+	runtimeValues map[string]string
+	decrypter     *sops.Decrypter
+	tmpDir        string
+	digest        string
+	ctxPull       context.Context
+	moduleCache   map[moduleCacheKey]*fetchedModule
+	policies      *engine.PolicySet
+
+	// plans holds the clusters planned before the rollout,
+	// indexed by cluster name. It is not modified by the rollout.
+	plans map[string]*clusterPlan
+
+	// concurrent is set when more than one cluster
+	// can be updated at the same time.
+	concurrent bool
+
+	// mu serialises the access to the bundle builder, whose CUE context
+	// is not safe for concurrent use, and to the module cache.
+	mu sync.Mutex
+
+	// outMu serialises the writes to out.
+	outMu sync.Mutex
+	out   io.Writer
+}
+
+// clusterPlan holds the bundle built with the runtime values of a cluster,
+// along with what is needed to build and apply its selected instances.
+type clusterPlan struct {
+	kubeconfig  *genericclioptions.ConfigFlags
+	bundle      *apiv1.Bundle
+	digest      string
+	selected    []*apiv1.BundleInstance
+	modDirs     map[string]string
+	kubeVersion string
+	apiVersions []string
+}
+
+// planCluster builds the bundle with the runtime values of the given
+// cluster and fetches the modules of the selected instances.
+func (r *bundleApplyRun) planCluster(ctx context.Context, cluster apiv1.RuntimeCluster) (*clusterPlan, error) {
+	kubeconfig := kubeconfigForContext(cluster.KubeContext)
+
+	clusterValues := make(map[string]string)
 
-		clusterValues := make(map[string]string)
+	// add values from env
+	maps.Copy(clusterValues, r.runtimeValues)
 
-		// add values from env
-		maps.Copy(clusterValues, runtimeValues)
+	// add values from cluster
+	rm, err := runtime.NewResourceManager(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	reader := newResourceReader(rm, r.decrypter, bundleLocalProviders())
+	rv, err := reader.Read(ctx, r.refs)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(clusterValues, rv)
+
+	// add cluster info
+	maps.Copy(clusterValues, cluster.NameGroupValues())
+
+	bundle, err := r.buildBundle(cluster.Name, clusterValues)
+	if err != nil {
+		return nil, err
+	}
 
-		// add values from cluster
-		rm, err := runtime.NewResourceManager(kubeconfigArgs)
+	// The digest is computed before the instances
+	// are annotated with the cluster and build info.
+	digest := r.digest
+	if digest == "" {
+		digest, err = bundleDigest(bundle)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		reader := runtime.NewResourceReader(rm)
-		rv, err := reader.Read(ctx, rt.Refs)
+	}
+
+	selected, err := selectBundleInstances(bundle.Instances)
+	if err != nil {
+		return nil, err
+	}
+
+	modDirs, err := r.fetchModules(selected)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeVersion, err := runtime.ServerVersion(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersions, err := runtime.ServerAPIVersions(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range selected {
+		instance.Cluster = cluster.Name
+		instance.ArtifactDigest = r.digest
+	}
+
+	return &clusterPlan{
+		kubeconfig:  kubeconfig,
+		bundle:      bundle,
+		digest:      digest,
+		selected:    selected,
+		modDirs:     modDirs,
+		kubeVersion: kubeVersion,
+		apiVersions: apiVersions,
+	}, nil
+}
+
+// checkPolicies plans every cluster and checks the instances against the
+// policies before the rollout starts, so that a denied object doesn't leave
+// some of the clusters applied. The plans are reused by applyCluster.
+func (r *bundleApplyRun) checkPolicies(ctx context.Context, clusters []apiv1.RuntimeCluster) error {
+	r.plans = make(map[string]*clusterPlan, len(clusters))
+	for _, cluster := range clusters {
+		plan, err := r.planCluster(ctx, cluster)
 		if err != nil {
 			return err
 		}
-		maps.Copy(clusterValues, rv)
-
-		// add cluster info
-		maps.Copy(clusterValues, cluster.NameGroupValues())
 
-		// init the in-memory cluster workspace
-		workspace := cluster.Name
-		if err := bm.InitWorkspace(workspace, clusterValues); err != nil {
-			return describeErr(bm.WorkspaceDir(workspace), "failed to parse bundle", err)
+		log := loggerBundle(ctx, plan.bundle.Name, cluster.Name)
+		if err := checkBundlePolicies(logr.NewContext(ctx, log), plan.kubeconfig, plan.selected,
+			plan.kubeVersion, plan.apiVersions, r.tmpDir, plan.modDirs, r.policies); err != nil {
+			if cluster.IsDefault() {
+				return err
+			}
+			return fmt.Errorf("%s: %w", cluster.Name, err)
 		}
+		r.plans[cluster.Name] = plan
+	}
+	return nil
+}
 
-		v, err := bm.Build(workspace)
+// applyCluster applies the instances of the bundle built with the
+// runtime values of the given cluster on that cluster.
+func (r *bundleApplyRun) applyCluster(ctx context.Context, cluster apiv1.RuntimeCluster) error {
+	plan, ok := r.plans[cluster.Name]
+	if !ok {
+		var err error
+		plan, err = r.planCluster(ctx, cluster)
 		if err != nil {
-			return describeErr(bm.WorkspaceDir(workspace), "failed to build bundle", err)
+			return err
 		}
+	}
+	kubeconfig := plan.kubeconfig
+	bundle := plan.bundle
+
+	log := loggerBundle(ctx, bundle.Name, cluster.Name)
+
+	recorder, err := newBundleRunRecorder(ctx, kubeconfig, bundle, plan.digest,
+		bundleApplyArgs.resume, bundleApplyArgs.dryrun || bundleApplyArgs.diff)
+	if err != nil {
+		return err
+	}
+	bundle.Instances = plan.selected
 
-		bundle, err := bm.GetBundle(v)
+	if !bundleApplyArgs.overwriteOwnership {
+		err = bundleInstancesOwnershipConflicts(ctx, kubeconfig, bundle.Instances)
 		if err != nil {
-			return err
+			return annotateInstanceOwnershipConflictErr(err)
 		}
+	}
 
-		log := loggerBundle(cmd.Context(), bundle.Name, cluster.Name)
+	startMsg := fmt.Sprintf("applying %v instance(s)", len(bundle.Instances))
+	if !cluster.IsDefault() {
+		startMsg = fmt.Sprintf("%s on %s", startMsg, logger.ColorizeSubject(cluster.Group))
+	}
 
-		if !bundleApplyArgs.overwriteOwnership {
-			err = bundleInstancesOwnershipConflicts(cmd.Context(), bundle.Instances)
-			if err != nil {
-				return annotateInstanceOwnershipConflictErr(err)
-			}
-		}
+	if bundleApplyArgs.dryrun || bundleApplyArgs.diff {
+		log.Info(fmt.Sprintf("%s %s", startMsg, logger.ColorizeDryRun("(server dry run)")))
+	} else {
+		log.Info(startMsg)
+	}
 
-		modDirs := make(map[string]string)
-		for _, instance := range bundle.Instances {
-			spin := logger.StartSpinner(fmt.Sprintf("pulling %s", instance.Module.Repository))
-			modDir, pullErr := fetchBundleInstanceModule(ctxPull, instance, tmpDir, bundleApplyArgs.creds.String(), moduleCache)
-			spin.Stop()
-			if pullErr != nil {
-				return pullErr
-			}
-			modDirs[instance.Name] = modDir
-		}
+	// Buffer the diff of concurrent clusters, so that
+	// the output of a cluster is printed in one piece.
+	diffOutput := r.out
+	var diffBuf bytes.Buffer
+	if r.concurrent {
+		diffOutput = &diffBuf
+		defer func() {
+			r.outMu.Lock()
+			defer r.outMu.Unlock()
+			_, _ = diffBuf.WriteTo(r.out)
+		}()
+	}
 
-		kubeVersion, err := runtime.ServerVersion(kubeconfigArgs)
+	for _, instance := range bundle.Instances {
+		resumeDigest := recorder.resumeDigest(instance)
+		digest, err := applyBundleInstance(logr.NewContext(ctx, log), kubeconfig, instance, plan.kubeVersion, plan.apiVersions, r.tmpDir, plan.modDirs[instance.Name], diffOutput, r.decrypter.Redact, r.startProgress, resumeDigest)
+		if recErr := recorder.update(ctx, instance, digest, err); recErr != nil && err == nil {
+			err = recErr
+		}
 		if err != nil {
 			return err
 		}
+	}
 
-		startMsg := fmt.Sprintf("applying %v instance(s)", len(bundle.Instances))
-		if !cluster.IsDefault() {
-			startMsg = fmt.Sprintf("%s on %s", startMsg, logger.ColorizeSubject(cluster.Group))
-		}
+	elapsed := time.Since(r.start)
+	if bundleApplyArgs.dryrun || bundleApplyArgs.diff {
+		log.Info(fmt.Sprintf("applied successfully %s",
+			logger.ColorizeDryRun("(server dry run)")))
+	} else {
+		log.Info(fmt.Sprintf("applied successfully in %s", elapsed.Round(time.Second)))
+	}
+	return nil
+}
 
-		if bundleApplyArgs.dryrun || bundleApplyArgs.diff {
-			log.Info(fmt.Sprintf("%s %s", startMsg, logger.ColorizeDryRun("(server dry run)")))
-		} else {
-			log.Info(startMsg)
-		}
+// buildBundle evaluates the bundle in the workspace of the given cluster.
+func (r *bundleApplyRun) buildBundle(workspace string, values map[string]string) (*apiv1.Bundle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-		for _, instance := range bundle.Instances {
-			instance.Cluster = cluster.Name
-			if err := applyBundleInstance(logr.NewContext(ctx, log), instance, kubeVersion, tmpDir, modDirs[instance.Name], cmd.OutOrStdout()); err != nil {
-				return err
-			}
-		}
+	// init the in-memory cluster workspace
+	if err := r.bm.InitWorkspace(workspace, values); err != nil {
+		return nil, describeErr(r.bm.WorkspaceDir(workspace), "failed to parse bundle", err)
+	}
+
+	v, err := r.bm.Build(workspace)
+	if err != nil {
+		return nil, describeErr(r.bm.WorkspaceDir(workspace), "failed to build bundle", err)
+	}
+
+	return r.bm.GetBundle(v)
+}
 
-		elapsed := time.Since(start)
-		if bundleApplyArgs.dryrun || bundleApplyArgs.diff {
-			log.Info(fmt.Sprintf("applied successfully %s",
-				logger.ColorizeDryRun("(server dry run)")))
-		} else {
-			log.Info(fmt.Sprintf("applied successfully in %s", elapsed.Round(time.Second)))
+// fetchModules fetches the modules of the given instances and
+// returns the module root directories indexed by instance name.
+func (r *bundleApplyRun) fetchModules(instances []*apiv1.BundleInstance) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modDirs := make(map[string]string)
+	for _, instance := range instances {
+		spin := r.startProgress(fmt.Sprintf("pulling %s", instance.Module.Repository))
+		modDir, pullErr := fetchBundleInstanceModule(r.ctxPull, instance, r.tmpDir, bundleApplyArgs.creds.String(), r.moduleCache)
+		spin.Stop()
+		if pullErr != nil {
+			return nil, pullErr
 		}
+		modDirs[instance.Name] = modDir
 	}
-	return nil
+	return modDirs, nil
+}
+
+// startProgress starts a spinner with the given message, unless the clusters
+// are updated concurrently and the spinners would overwrite each other.
+func (r *bundleApplyRun) startProgress(msg string) interface{ Stop() } {
+	if r.concurrent {
+		return &noopProgressStopper{}
+	}
+	return logger.StartSpinner(msg)
 }
 
+// noopProgressStopper is returned in place of a spinner when progress is not shown.
+type noopProgressStopper struct{}
+
+func (*noopProgressStopper) Stop() {}
+
 // fetchedModule holds the local root directory and resolved reference of a
 // module fetched during a bundle run.
 type fetchedModule struct {
@@ -317,67 +591,186 @@ func fetchBundleInstanceModule(ctx context.Context, instance *apiv1.BundleInstan
 // instances a bundle contains. The module directory is shared between the
 // instances referencing the same module version and is never modified; the
 // instance schema and values are injected as in-memory overlays.
-func applyBundleInstance(ctx context.Context, instance *apiv1.BundleInstance, kubeVersion string, rootDir string, modDir string, diffOutput io.Writer) error {
+// When the digest of the rendered objects matches the resume digest, the
+// instance is skipped. It returns the digest of the rendered objects.
+func applyBundleInstance(ctx context.Context,
+	kubeconfig *genericclioptions.ConfigFlags,
+	instance *apiv1.BundleInstance,
+	kubeVersion string,
+	apiVersions []string,
+	rootDir string,
+	modDir string,
+	diffOutput io.Writer,
+	redact func([]byte) []byte,
+	progressStart func(string) interface{ Stop() },
+	resumeDigest string) (string, error) {
 	log := loggerBundleInstance(ctx, instance.Bundle, instance.Cluster, instance.Name, true)
 
-	builder := engine.NewModuleBuilder(
-		nil,
-		instance.Name,
-		instance.Namespace,
-		modDir,
-		bundleApplyArgs.pkg.String(),
-	)
-
-	if err := builder.OverlaySchemaFile(); err != nil {
-		return err
-	}
-
-	modName, err := builder.GetModuleName()
+	builder, buildResult, err := buildBundleInstance(ctx, log, kubeconfig, instance,
+		kubeVersion, apiVersions, rootDir, modDir, diffOutput, redact)
 	if err != nil {
-		return err
+		return "", err
 	}
-	instance.Module.Name = modName
 
-	log.Info(fmt.Sprintf("applying module %s version %s",
-		logger.ColorizeSubject(instance.Module.Name), logger.ColorizeSubject(instance.Module.Version)))
-	err = builder.OverlayValuesFileWithDefaults(instance.Values)
+	digest, err := renderedDigest(builder, buildResult)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	builder.SetVersionInfo(instance.Module.Version, kubeVersion)
-
-	buildResult, err := builder.Build()
-	if err != nil {
-		return describeErr(modDir, "build failed for "+instance.Name, err)
+	if resumeDigest != "" && resumeDigest == digest {
+		log.Info(fmt.Sprintf("skipping module %s version %s %s",
+			logger.ColorizeSubject(instance.Module.Name), logger.ColorizeSubject(instance.Module.Version),
+			logger.ColorizeDryRun("(applied in the previous run)")))
+		return digest, nil
 	}
 
+	log.Info(fmt.Sprintf("applying module %s version %s",
+		logger.ColorizeSubject(instance.Module.Name), logger.ColorizeSubject(instance.Module.Version)))
+
 	r := reconciler.NewInteractiveReconciler(log,
 		&reconciler.CommonOptions{
 			Dir:                rootDir,
 			Wait:               bundleApplyArgs.wait,
 			Force:              bundleApplyArgs.force,
 			OverwriteOwnership: bundleApplyArgs.overwriteOwnership,
+			AgeRecipients:      bundleApplyArgs.ageRecipients,
 		},
 		&reconciler.InteractiveOptions{
 			DryRun:        bundleApplyArgs.dryrun,
 			Diff:          bundleApplyArgs.diff,
 			DiffOutput:    diffOutput,
-			ProgressStart: logger.StartSpinner,
+			Redact:        redact,
+			ProgressStart: progressStart,
 		},
 		rootArgs.timeout,
 	)
 
-	if err := r.Init(ctx, builder, buildResult, instance, kubeconfigArgs); err != nil {
-		return annotateInstanceOwnershipConflictErr(err)
+	if err := r.Init(ctx, builder, buildResult, instance, kubeconfig); err != nil {
+		return digest, annotateInstanceOwnershipConflictErr(err)
 	}
 
-	return r.ApplyInstance(ctx, log,
+	return digest, r.ApplyInstance(ctx, log,
 		builder,
 		buildResult,
 	)
 }
 
+// buildBundleInstance builds the instance with the values migrated for the
+// installed module version, and checks the module requirements. The values
+// migration diff is printed to diffOutput when --diff is set, unless
+// diffOutput is nil.
+func buildBundleInstance(ctx context.Context,
+	log logr.Logger,
+	kubeconfig *genericclioptions.ConfigFlags,
+	instance *apiv1.BundleInstance,
+	kubeVersion string,
+	apiVersions []string,
+	rootDir string,
+	modDir string,
+	diffOutput io.Writer,
+	redact func([]byte) []byte) (*engine.ModuleBuilder, cue.Value, error) {
+	var buildResult cue.Value
+	builder := engine.NewModuleBuilder(
+		nil,
+		instance.Name,
+		instance.Namespace,
+		modDir,
+		bundleApplyArgs.pkg.String(),
+	)
+
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return nil, buildResult, err
+	}
+
+	modName, err := builder.GetModuleName()
+	if err != nil {
+		return nil, buildResult, err
+	}
+	instance.Module.Name = modName
+
+	stored, migrations, err := instanceValuesMigrations(ctx, log, kubeconfig, builder,
+		instance.Name, instance.Namespace, instance.Module.Version)
+	if err != nil {
+		return nil, buildResult, fmt.Errorf("%s: %w", instance.Name, err)
+	}
+
+	values := instance.Values
+	if len(migrations) > 0 && values.Exists() {
+		values, err = builder.MigrateValues(values, migrations)
+		if err != nil {
+			return nil, buildResult, fmt.Errorf("migrating values failed for %s: %w", instance.Name, err)
+		}
+	}
+
+	if diffOutput != nil && bundleApplyArgs.diff && len(migrations) > 0 {
+		if err := printValuesMigrationDiff(diffOutput, rootDir, builder, stored, migrations, redact); err != nil {
+			return nil, buildResult, err
+		}
+	}
+
+	err = builder.OverlayValuesFileWithDefaults(values)
+	if err != nil {
+		return nil, buildResult, err
+	}
+
+	builder.SetVersionInfo(instance.Module.Version, kubeVersion)
+	builder.SetAPIVersions(apiVersions)
+
+	buildResult, err = builder.Build()
+	if err != nil {
+		return nil, buildResult, describeErr(modDir, "build failed for "+instance.Name, err)
+	}
+
+	instance.Module.Requirements, err = builder.GetRequirements(buildResult)
+	if err != nil {
+		return nil, buildResult, err
+	}
+
+	if err := engine.CheckRequirements(instance.Module.Requirements, kubeVersion, VERSION, apiVersions); err != nil {
+		return nil, buildResult, fmt.Errorf("module requirements not met for %s: %w", instance.Name, err)
+	}
+
+	return builder, buildResult, nil
+}
+
+// checkBundlePolicies builds every instance of a cluster and checks the
+// rendered objects against the policies, before any instance is applied.
+// The instances are built one at a time and the build results discarded,
+// to keep the memory usage constant. It is a no-op when policies is nil.
+func checkBundlePolicies(ctx context.Context,
+	kubeconfig *genericclioptions.ConfigFlags,
+	instances []*apiv1.BundleInstance,
+	kubeVersion string,
+	apiVersions []string,
+	rootDir string,
+	modDirs map[string]string,
+	policies *engine.PolicySet) error {
+	if policies == nil {
+		return nil
+	}
+
+	var denied []string
+	for _, instance := range instances {
+		log := loggerBundleInstance(ctx, instance.Bundle, instance.Cluster, instance.Name, true)
+		builder, buildResult, err := buildBundleInstance(ctx, log, kubeconfig, instance,
+			kubeVersion, apiVersions, rootDir, modDirs[instance.Name], nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := enforcePolicies(log, policies, builder, buildResult); err != nil {
+			if !errors.Is(err, errPolicyDenied) {
+				return err
+			}
+			denied = append(denied, instance.Name)
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("policy check failed for %s, no instance was applied", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
 func annotateInstanceOwnershipConflictErr(err error) error {
 	if errors.Is(err, &reconciler.InstanceOwnershipConflictErr{}) {
 		return fmt.Errorf("%s %s", err, "Apply with \"--overwrite-ownership\" to gain instance ownership.")
@@ -411,9 +804,9 @@ func saveReaderToFile(reader io.Reader) (string, error) {
 	return tmpPath, nil
 }
 
-func bundleInstancesOwnershipConflicts(parent context.Context, bundleInstances []*apiv1.BundleInstance) error {
+func bundleInstancesOwnershipConflicts(parent context.Context, kubeconfig *genericclioptions.ConfigFlags, bundleInstances []*apiv1.BundleInstance) error {
 	var conflicts reconciler.InstanceOwnershipConflictErr
-	rm, err := runtime.NewResourceManager(kubeconfigArgs)
+	rm, err := runtime.NewResourceManager(kubeconfig)
 	if err != nil {
 		return err
 	}
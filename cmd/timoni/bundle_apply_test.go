@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -921,3 +922,343 @@ runtime: {
 		g.Expect(output).To(ContainSubstring(version))
 	}
 }
+
+func Test_BundleApply_Runtime_PolicyDenied(t *testing.T) {
+	g := NewWithT(t)
+
+	bundleName := rnd("my-bundle")
+	modPath := "testdata/module"
+	namespace := rnd("my-namespace")
+	modURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-mod"))
+	modVer := "1.0.0"
+
+	_, err := executeCommand(fmt.Sprintf("mod push %s oci://%s -v %s --resolve-symlinks", modPath, modURL, modVer))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The server ConfigMap, denied by the tls policy,
+	// is rendered only for the production cluster.
+	bundleData := fmt.Sprintf(`
+bundle: {
+	_cluster: string @timoni(runtime:string:TIMONI_CLUSTER_NAME)
+
+	apiVersion: "v1alpha1"
+	name: "%[1]s"
+	instances: {
+		"\(_cluster)-app": {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+			values: server: enabled: _cluster == "production"
+		}
+	}
+}
+`, bundleName, modURL, modVer, namespace)
+
+	runtimeCue := `
+runtime: {
+	apiVersion: "v1alpha1"
+	name:       "fleet-test"
+	clusters: {
+		"staging": {
+			group:       "staging"
+			kubeContext: "envtest"
+		}
+		"production": {
+			group:       "production"
+			kubeContext: "envtest"
+		}
+	}
+}
+`
+
+	runtimePath := filepath.Join(t.TempDir(), "runtime.cue")
+	g.Expect(os.WriteFile(runtimePath, []byte(runtimeCue), 0644)).ToNot(HaveOccurred())
+
+	_, err = executeCommandWithIn(
+		fmt.Sprintf("bundle apply -f- -r %s -p main --policy testdata/policy/tls", runtimePath),
+		strings.NewReader(bundleData))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("production: policy check failed for production-app"))
+
+	// No cluster is applied, including the ones rolled out before the denied one.
+	for _, name := range []string{"staging-app", "production-app"} {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-client",
+				Namespace: namespace,
+			},
+		}
+		err = envTestClient.Get(context.Background(), client.ObjectKeyFromObject(cm), cm)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+}
+
+func Test_BundleApply_FromArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	bundleName := "my-bundle"
+	modPath := "testdata/module"
+	namespace := rnd("my-namespace")
+	modName := rnd("my-mod")
+	modURL := fmt.Sprintf("%s/%s", dockerRegistry, modName)
+	modVer := "1.0.0"
+	bundleURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-bundle"))
+
+	_, err := executeCommand(fmt.Sprintf(
+		"mod push %s oci://%s -v %s --resolve-symlinks",
+		modPath,
+		modURL,
+		modVer,
+	))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleData := fmt.Sprintf(`
+bundle: {
+	apiVersion: "v1alpha1"
+	name: "%[1]s"
+	instances: {
+		frontend: {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+		}
+	}
+}
+`, bundleName, modURL, modVer, namespace)
+
+	valuesData := `
+bundle:
+  instances:
+    frontend:
+      values:
+        server:
+          enabled: false
+`
+
+	bundleDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(bundleDir, "bundle.cue"), []byte(bundleData), 0644)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(bundleDir, "values.yaml"), []byte(valuesData), 0644)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(bundleDir, "README.md"), []byte("# bundle"), 0644)).To(Succeed())
+
+	_, err = executeCommand(fmt.Sprintf("artifact push oci://%s -f %s -t 1.0.0", bundleURL, bundleDir))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleDigest, err := crane.Digest(fmt.Sprintf("%s:1.0.0", bundleURL))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	t.Run("creates instances from the bundle artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"bundle apply -f oci://%s:1.0.0 -p main --wait",
+			bundleURL,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		t.Log("\n", output)
+
+		clientCM := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "frontend-client",
+				Namespace: namespace,
+			},
+		}
+		err = envTestClient.Get(context.Background(), client.ObjectKeyFromObject(clientCM), clientCM)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		serverCM := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "frontend-server",
+				Namespace: namespace,
+			},
+		}
+		err = envTestClient.Get(context.Background(), client.ObjectKeyFromObject(serverCM), serverCM)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("records the artifact digest on the instance", func(t *testing.T) {
+		g := NewWithT(t)
+		storage := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "timoni.frontend",
+				Namespace: namespace,
+			},
+		}
+		err := envTestClient.Get(context.Background(), client.ObjectKeyFromObject(storage), storage)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(storage.GetLabels()).To(HaveKeyWithValue(apiv1.BundleNameLabelKey, bundleName))
+		g.Expect(storage.GetLabels()).To(HaveKeyWithValue(apiv1.BundleDigestLabelKey, apiv1.BundleDigestLabelValue(bundleDigest)))
+		g.Expect(storage.GetAnnotations()).To(HaveKeyWithValue(apiv1.BundleDigestAnnotationKey, bundleDigest))
+	})
+
+	t.Run("builds the bundle artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"bundle build -f oci://%s:1.0.0 -p main",
+			bundleURL,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("name: frontend-client"))
+		g.Expect(output).ToNot(ContainSubstring("name: frontend-server"))
+	})
+
+	t.Run("fails to verify without a signature", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"bundle vet -f oci://%s:1.0.0 --verify=cosign --cosign-key=cosign.pub",
+			bundleURL,
+		))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("deletes the instances of the bundle artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"bundle delete -f oci://%s:1.0.0 --wait",
+			bundleURL,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		clientCM := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "frontend-client",
+				Namespace: namespace,
+			},
+		}
+		err = envTestClient.Get(context.Background(), client.ObjectKeyFromObject(clientCM), clientCM)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func Test_BundleApply_Resume(t *testing.T) {
+	g := NewWithT(t)
+
+	bundleName := rnd("my-bundle")
+	modPath := "testdata/module"
+	namespace := rnd("my-namespace")
+	modName := rnd("my-mod")
+	modURL := fmt.Sprintf("%s/%s", dockerRegistry, modName)
+	modVer := "1.0.0"
+
+	_, err := executeCommand(fmt.Sprintf(
+		"mod push %s oci://%s -v %s --resolve-symlinks",
+		modPath,
+		modURL,
+		modVer,
+	))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleTmpl := `
+bundle: {
+	apiVersion: "v1alpha1"
+	name: "%[1]s"
+	instances: {
+		frontend: {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+			values: server: enabled: false
+		}
+		backend: {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+			values: client: enabled: false
+			values: domain: %[5]s
+		}
+	}
+}
+`
+
+	getRun := func(g *WithT) *apiv1.BundleRun {
+		secrets := &corev1.SecretList{}
+		err := envTestClient.List(context.Background(), secrets,
+			client.MatchingLabels{apiv1.BundleNameLabelKey: bundleName})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(secrets.Items).To(HaveLen(1))
+		g.Expect(string(secrets.Items[0].Type)).To(Equal(apiv1.BundleRunStorageType))
+
+		var run apiv1.BundleRun
+		g.Expect(json.Unmarshal(secrets.Items[0].Data["run"], &run)).To(Succeed())
+		return &run
+	}
+
+	t.Run("records the failed instance", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The backend build fails as the domain must be a string.
+		bundleData := fmt.Sprintf(bundleTmpl, bundleName, modURL, modVer, namespace, "1")
+		_, err := executeCommandWithIn("bundle apply -f - -p main --wait", strings.NewReader(bundleData))
+		g.Expect(err).To(HaveOccurred())
+
+		run := getRun(g)
+		g.Expect(run.Digest).To(HavePrefix("sha256:"))
+		g.Expect(run.GetInstance("frontend", namespace).Status).To(Equal(apiv1.BundleRunApplied))
+		g.Expect(run.GetInstance("frontend", namespace).Digest).To(HavePrefix("sha256:"))
+		g.Expect(run.GetInstance("backend", namespace).Status).To(Equal(apiv1.BundleRunFailed))
+		g.Expect(run.GetInstance("backend", namespace).Error).ToNot(BeEmpty())
+
+		output, err := executeCommand(fmt.Sprintf("bundle status %s", bundleName))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("applied 1/2 instance(s)"))
+		g.Expect(output).To(ContainSubstring("instance backend failed"))
+	})
+
+	t.Run("keeps the applied instances when a resumed run is interrupted", func(t *testing.T) {
+		g := NewWithT(t)
+		frontendDigest := getRun(g).GetInstance("frontend", namespace).Digest
+
+		// The run stops before applying any instance,
+		// as the backend is owned by another instance.
+		_, err := executeCommand(fmt.Sprintf("apply -n %s backend %s -p main", namespace, modPath))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		bundleData := fmt.Sprintf(bundleTmpl, bundleName, modURL, modVer, namespace, `"example.com"`)
+		_, err = executeCommandWithIn("bundle apply -f - -p main --wait --resume", strings.NewReader(bundleData))
+		g.Expect(err).To(HaveOccurred())
+
+		run := getRun(g)
+		g.Expect(run.GetInstance("frontend", namespace).Status).To(Equal(apiv1.BundleRunApplied))
+		g.Expect(run.GetInstance("frontend", namespace).Digest).To(Equal(frontendDigest))
+		g.Expect(run.GetInstance("backend", namespace).Status).To(Equal(apiv1.BundleRunFailed))
+
+		_, err = executeCommand(fmt.Sprintf("delete -n %s backend --wait", namespace))
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("skips the applied instances on resume", func(t *testing.T) {
+		g := NewWithT(t)
+
+		bundleData := fmt.Sprintf(bundleTmpl, bundleName, modURL, modVer, namespace, `"example.com"`)
+		output, err := executeCommandWithIn("bundle apply -f - -p main --wait --resume", strings.NewReader(bundleData))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(MatchRegexp(`frontend.*skipping module`))
+		g.Expect(output).To(MatchRegexp(`backend.*applying module`))
+
+		run := getRun(g)
+		g.Expect(run.CountInstances(apiv1.BundleRunApplied)).To(Equal(2))
+
+		output, err = executeCommand(fmt.Sprintf("bundle status %s", bundleName))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("applied 2/2 instance(s)"))
+	})
+
+	t.Run("deletes the run record", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := executeCommand(fmt.Sprintf("bundle delete %s --wait", bundleName))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		secrets := &corev1.SecretList{}
+		err = envTestClient.List(context.Background(), secrets,
+			client.MatchingLabels{apiv1.BundleNameLabelKey: bundleName})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(secrets.Items).To(BeEmpty())
+	})
+}
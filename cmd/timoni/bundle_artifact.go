@@ -0,0 +1,209 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/oci"
+)
+
+// bundleVerifyFlags holds the flags for verifying
+// the signature of bundle artifacts.
+type bundleVerifyFlags struct {
+	verify                      string
+	cosignKey                   string
+	certificateIdentity         string
+	certificateIdentityRegexp   string
+	certificateOidcIssuer       string
+	certificateOidcIssuerRegexp string
+}
+
+var bundleVerifyArgs bundleVerifyFlags
+
+// addBundleVerifyFlags registers the signature verification flags
+// of bundle artifacts on the given command.
+func addBundleVerifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&bundleVerifyArgs.verify, "verify", "",
+		"Verifies the signed bundle artifact with the specified provider.")
+	cmd.Flags().StringVar(&bundleVerifyArgs.cosignKey, "cosign-key", "",
+		"The Cosign public key for verifying the bundle artifact.")
+	cmd.Flags().StringVar(&bundleVerifyArgs.certificateIdentity, "certificate-identity", "",
+		"The identity expected in a valid Fulcio certificate for verifying the Cosign signature.")
+	cmd.Flags().StringVar(&bundleVerifyArgs.certificateIdentityRegexp, "certificate-identity-regexp", "",
+		"A regular expression alternative to --certificate-identity for verifying the Cosign signature.")
+	cmd.Flags().StringVar(&bundleVerifyArgs.certificateOidcIssuer, "certificate-oidc-issuer", "",
+		"The OIDC issuer expected in a valid Fulcio certificate for verifying the Cosign signature.")
+	cmd.Flags().StringVar(&bundleVerifyArgs.certificateOidcIssuerRegexp, "certificate-oidc-issuer-regexp", "",
+		"A regular expression alternative to --certificate-oidc-issuer for verifying the Cosign signature.")
+}
+
+// isBundleArtifact returns true if the given bundle file is an OCI artifact URL.
+func isBundleArtifact(file string) bool {
+	return strings.HasPrefix(file, apiv1.ArtifactPrefix)
+}
+
+// resolveBundleArtifact replaces the OCI URL found in the bundle files, if
+// any, with the bundle definitions extracted from the artifact. The runtime
+// definitions found in the artifact are used when no runtime files are
+// specified with --runtime, and the artifact content is used as the CUE
+// module root when it contains a cue.mod directory and no --workdir is
+// specified. The bundle files referenced by HTTPS URLs are downloaded
+// to the temporary directory. It returns the bundle files and the artifact
+// digest, which is empty when no artifact is referenced.
+func resolveBundleArtifact(cmd *cobra.Command, files []string, tmpDir, creds string) ([]string, string, error) {
+	files = slices.Clone(files)
+	for i, file := range files {
+		if isRemoteFile(file) {
+			local, err := downloadRemoteFile(cmd, file, filepath.Join(tmpDir, "remote"), i)
+			if err != nil {
+				return nil, "", err
+			}
+			files[i] = local
+		}
+	}
+
+	index := -1
+	for i, file := range files {
+		if isBundleArtifact(file) {
+			if index != -1 {
+				return nil, "", errors.New("only one bundle artifact can be specified with -f")
+			}
+			index = i
+		}
+	}
+	if index == -1 {
+		// The verification flags are looked up with Changed, as the
+		// commands that don't load bundle artifacts don't define them.
+		for _, name := range []string{"cosign-key", "certificate-identity", "certificate-identity-regexp",
+			"certificate-oidc-issuer", "certificate-oidc-issuer-regexp"} {
+			if bundleVerifyArgs.verify == "" && cmd.Flags().Changed(name) {
+				return nil, "", fmt.Errorf("--%s requires --verify", name)
+			}
+		}
+		if bundleVerifyArgs.verify != "" {
+			return nil, "", errors.New("--verify requires a bundle artifact to be specified with -f")
+		}
+		return files, "", nil
+	}
+
+	dstDir := filepath.Join(tmpDir, "bundle")
+	if err := os.MkdirAll(dstDir, os.ModePerm); err != nil {
+		return nil, "", err
+	}
+
+	digest, err := pullBundleArtifact(cmd, files[index], dstDir, creds)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := engine.ScanBundleArtifact(dstDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid bundle artifact %s: %w", files[index], err)
+	}
+
+	// The runtime definitions of the artifact are not used
+	// when the values are read from a snapshot.
+	if len(bundleArgs.runtimeFiles) == 0 && bundleArgs.runtimeSnapshot == "" && len(content.Runtimes) > 0 {
+		bundleArgs.runtimeFiles = content.Runtimes
+		bundleArgs.runtimeFromArtifact = true
+		if bundleArgs.localProviders.enabled() {
+			log := LoggerFrom(cmd.Context())
+			log.Info(logger.ColorizeWarning("the env, file and exec runtime providers are disabled for runtimes loaded from artifacts"))
+		}
+	}
+	if bundleArgs.workdir == "" && content.ModuleRoot {
+		bundleArgs.workdir = dstDir
+	}
+
+	result := make([]string, 0, len(files)+len(content.Bundles)-1)
+	result = append(result, files[:index]...)
+	result = append(result, content.Bundles...)
+	result = append(result, files[index+1:]...)
+	return result, digest, nil
+}
+
+// pullBundleArtifact resolves the artifact URL to a digest, verifies the
+// artifact signature if requested, and extracts the artifact content to
+// the destination directory. It returns the artifact digest.
+func pullBundleArtifact(cmd *cobra.Command, ociURL, dstDir, creds string) (string, error) {
+	log := LoggerFrom(cmd.Context())
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	if err := validateVerificationFlags(cmd, bundleVerifyArgs.verify); err != nil {
+		return "", err
+	}
+	if bundleVerifyArgs.verify != "" {
+		if err := oci.ValidateVerificationProvider(bundleVerifyArgs.verify); err != nil {
+			return "", err
+		}
+	}
+	if _, err := oci.ParseArtifactURL(ociURL); err != nil {
+		return "", err
+	}
+
+	opts := oci.Options(ctx, creds, rootArgs.registryInsecure)
+
+	// Pull the artifact by digest, so that the signature that is
+	// verified covers the content that is extracted and recorded.
+	digestURL, err := oci.ResolveDigestURL(ociURL, opts)
+	if err != nil {
+		return "", err
+	}
+
+	if bundleVerifyArgs.verify != "" {
+		err = oci.VerifyArtifact(ctx, log,
+			bundleVerifyArgs.verify,
+			digestURL,
+			bundleVerifyArgs.cosignKey,
+			bundleVerifyArgs.certificateIdentity,
+			bundleVerifyArgs.certificateIdentityRegexp,
+			bundleVerifyArgs.certificateOidcIssuer,
+			bundleVerifyArgs.certificateOidcIssuerRegexp,
+			rootArgs.registryInsecure,
+			creds)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("pulling %s", ociURL))
+	err = oci.PullArtifact(digestURL, dstDir, apiv1.AnyContentType, opts)
+	spin.Stop()
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := oci.ParseDigest(digestURL)
+	if err != nil {
+		return "", err
+	}
+
+	return ref.DigestStr(), nil
+}
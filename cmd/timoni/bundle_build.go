@@ -54,9 +54,20 @@ var bundleBuildCmd = &cobra.Command{
   # Pass secret values from stdin
   cat ./bundle_secrets.cue | timoni bundle build -f ./bundle.cue -f -
 
+  # Build the instances of a bundle stored in a container registry
+  timoni bundle build -f oci://ghcr.io/org/bundles/app:1.0.0
+
   # Write the manifests as a directory tree, one directory per instance
   # and one file per resource, named like 'kustomize build -o <dir>'
   timoni bundle build -f bundle.cue --output-dir ./manifests
+
+  # Build all instances from a bundle except the test ones
+  timoni bundle build -f bundle.cue --exclude-instance '*-test'
+
+  # Build a bundle for a cluster with the values from a runtime snapshot
+  timoni bundle build -f bundle.cue \
+  --runtime-snapshot snapshot.json \
+  --runtime-cluster production
 `,
 	Args: cobra.NoArgs,
 	RunE: runBundleBuildCmd,
@@ -76,7 +87,7 @@ var bundleBuildArgs bundleBuildFlags
 func init() {
 	bundleBuildCmd.Flags().VarP(&bundleBuildArgs.pkg, bundleBuildArgs.pkg.Type(), bundleBuildArgs.pkg.Shorthand(), bundleBuildArgs.pkg.Description())
 	bundleBuildCmd.Flags().StringSliceVarP(&bundleBuildArgs.files, "file", "f", nil,
-		"The local path to bundle.cue files.")
+		"The local path to bundle.cue files or the OCI URL of a bundle artifact.")
 	bundleBuildCmd.Flags().Var(&bundleBuildArgs.creds, bundleBuildArgs.creds.Type(), bundleBuildArgs.creds.Description())
 	bundleBuildCmd.Flags().StringVar(&bundleBuildArgs.outputDir, "output-dir", "",
 		"The path to a directory where the manifests are written as a tree, one directory per instance and one file per resource.")
@@ -84,6 +95,9 @@ func init() {
 		"The number of instances to build concurrently, defaults to the number of CPU cores capped at 8.")
 	bundleBuildCmd.Flags().BoolVar(&bundleBuildArgs.maskSecrets, "mask-secrets", false,
 		"Hide the values of Kubernetes Secrets in the printed objects, ignored with --output-dir.")
+	addBundleVerifyFlags(bundleBuildCmd)
+	addBundleSelectFlags(bundleBuildCmd)
+	addRuntimeSnapshotFlag(bundleBuildCmd)
 	bundleCmd.AddCommand(bundleBuildCmd)
 }
 
@@ -92,6 +106,9 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 	if len(files) == 0 {
 		return errors.New("no bundle provided with -f")
 	}
+	if err := bundleSelectArgs.validate(); err != nil {
+		return err
+	}
 	var stdinFile string
 	for i, file := range files {
 		if file == "-" {
@@ -113,6 +130,11 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
+	files, _, err = resolveBundleArtifact(cmd, files, tmpDir, bundleBuildArgs.creds.String())
+	if err != nil {
+		return err
+	}
+
 	workdir, err := resolveWorkdir(bundleArgs.workdir)
 	if err != nil {
 		return err
@@ -121,6 +143,8 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 	ctx := cuecontext.New()
 	bm := engine.NewBundleBuilder(ctx, files)
 	bm.SetWorkdir(workdir)
+	decrypter := newDecrypter()
+	bm.SetDecrypter(decrypter)
 
 	workspace := apiv1.RuntimeDefaultName
 	runtimeValues := make(map[string]string)
@@ -129,6 +153,32 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 		maps.Copy(runtimeValues, engine.GetEnv())
 	}
 
+	snapshot, err := loadRuntimeSnapshot()
+	if err != nil {
+		return err
+	}
+
+	if snapshot != nil {
+		clusters := snapshot.Runtime().SelectClusters(bundleArgs.runtimeCluster, bundleArgs.runtimeClusterGroup)
+		if len(clusters) > 1 {
+			return errors.New("you must select a cluster with --runtime-cluster")
+		}
+		if len(clusters) == 0 {
+			return errors.New("no cluster found")
+		}
+
+		cluster := clusters[0]
+		workspace = cluster.Name
+
+		rv, err := runtime.SnapshotValues(snapshot.GetCluster(cluster.Name), decrypter)
+		if err != nil {
+			return err
+		}
+
+		maps.Copy(runtimeValues, rv)
+		maps.Copy(runtimeValues, cluster.NameGroupValues())
+	}
+
 	if len(bundleArgs.runtimeFiles) > 0 {
 		kctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 		defer cancel()
@@ -155,7 +205,7 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 			return err
 		}
 
-		reader := runtime.NewResourceReader(rm)
+		reader := newResourceReader(rm, decrypter, bundleLocalProviders())
 		rv, err := reader.Read(kctx, rt.Refs)
 		if err != nil {
 			return err
@@ -179,6 +229,11 @@ func runBundleBuildCmd(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	bundle.Instances, err = selectBundleInstances(bundle.Instances)
+	if err != nil {
+		return err
+	}
+
 	ctxPull, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 	defer cancel()
 
@@ -291,6 +291,208 @@ runtime: {
 	})
 }
 
+func Test_BundleBuild_Runtime_LocalProviders(t *testing.T) {
+	g := NewWithT(t)
+
+	bundleName := rnd("my-bundle")
+	modPath := "testdata/module"
+	namespace := rnd("my-namespace")
+	modName := rnd("my-mod")
+	modURL := fmt.Sprintf("%s/%s", dockerRegistry, modName)
+	modVer := "1.0.0"
+	bundleURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-bundle"))
+
+	_, err := executeCommand(fmt.Sprintf(
+		"mod push %s oci://%s -v %s --resolve-symlinks",
+		modPath,
+		modURL,
+		modVer,
+	))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleData := fmt.Sprintf(`
+bundle: {
+	_account: string @timoni(runtime:string:ACCOUNT)
+
+	apiVersion: "v1alpha1"
+	name: "%[1]s"
+	instances: {
+		"\(_account)-app": {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+		}
+	}
+}
+`, bundleName, modURL, modVer, namespace)
+
+	runtimeCue := `
+runtime: {
+	apiVersion: "v1alpha1"
+	name:       "local"
+	values: [
+		{
+			query: "exec:echo '{\"account\": \"acme\"}'"
+			for: {
+				"ACCOUNT": "obj.account"
+			}
+		},
+	]
+}
+`
+
+	bundleDir := t.TempDir()
+	bundlePath := filepath.Join(bundleDir, "bundle.cue")
+	runtimePath := filepath.Join(bundleDir, "runtime.cue")
+	g.Expect(os.WriteFile(bundlePath, []byte(bundleData), 0644)).To(Succeed())
+	g.Expect(os.WriteFile(runtimePath, []byte(runtimeCue), 0644)).To(Succeed())
+
+	t.Run("fails for disabled exec provider", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf("bundle build -f %s -r %s -p main", bundlePath, runtimePath))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("provider 'exec' is not enabled"))
+	})
+
+	t.Run("builds with exec provider enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf("bundle build -f %s -r %s -p main --allow-exec", bundlePath, runtimePath))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("acme-app"))
+	})
+
+	t.Run("reads env vars only with env provider enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("TIMONI_TEST_ACCOUNT", "envcorp")
+		envRuntimePath := filepath.Join(t.TempDir(), "runtime.cue")
+		g.Expect(os.WriteFile(envRuntimePath, []byte(`
+runtime: {
+	apiVersion: "v1alpha1"
+	name:       "local"
+	values: [{
+		query: "env:TIMONI_TEST_ACCOUNT"
+		for: "ACCOUNT": "obj"
+	}]
+}
+`), 0644)).To(Succeed())
+
+		_, err := executeCommand(fmt.Sprintf("bundle build -f %s -r %s -p main", bundlePath, envRuntimePath))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("provider 'env' is not enabled"))
+
+		output, err := executeCommand(fmt.Sprintf("bundle build -f %s -r %s -p main --allow-env", bundlePath, envRuntimePath))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("envcorp-app"))
+	})
+
+	t.Run("fails for exec provider in artifact runtime", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf("artifact push oci://%s -f %s -t 1.0.0", bundleURL, bundleDir))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = executeCommand(fmt.Sprintf("bundle build -f oci://%s:1.0.0 -p main --allow-exec", bundleURL))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("provider 'exec' is not enabled"))
+	})
+}
+
+func Test_BundleBuild_RuntimeSnapshot_FromArtifact(t *testing.T) {
+	g := NewWithT(t)
+
+	bundleName := rnd("my-bundle")
+	modPath := "testdata/module"
+	namespace := rnd("my-namespace")
+	modName := rnd("my-mod")
+	modURL := fmt.Sprintf("%s/%s", dockerRegistry, modName)
+	modVer := "1.0.0"
+	bundleURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("my-bundle"))
+
+	_, err := executeCommand(fmt.Sprintf(
+		"mod push %s oci://%s -v %s --resolve-symlinks",
+		modPath,
+		modURL,
+		modVer,
+	))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleData := fmt.Sprintf(`
+bundle: {
+	_cluster: string @timoni(runtime:string:TIMONI_CLUSTER_NAME)
+
+	apiVersion: "v1alpha1"
+	name: "%[1]s"
+	instances: {
+		"\(_cluster)-app": {
+			module: {
+				url:     "oci://%[2]s"
+				version: "%[3]s"
+			}
+			namespace: "%[4]s"
+		}
+	}
+}
+`, bundleName, modURL, modVer, namespace)
+
+	runtimeCue := `
+runtime: {
+	apiVersion: "v1alpha1"
+	name:       "fleet-test"
+	clusters: {
+		"staging": {
+			group:       "staging"
+			kubeContext: "envtest"
+		}
+	}
+	values: []
+}
+`
+
+	snapshotData := `{
+  "apiVersion": "timoni.sh/v1alpha1",
+  "kind": "RuntimeSnapshot",
+  "name": "fleet-test",
+  "createdAt": "2026-01-01T00:00:00Z",
+  "clusters": [{"name": "production", "group": "production"}]
+}
+`
+
+	bundleDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(bundleDir, "bundle.cue"), []byte(bundleData), 0644)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(bundleDir, "runtime.cue"), []byte(runtimeCue), 0644)).To(Succeed())
+
+	_, err = executeCommand(fmt.Sprintf("artifact push oci://%s -f %s -t 1.0.0", bundleURL, bundleDir))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	g.Expect(os.WriteFile(snapshotPath, []byte(snapshotData), 0644)).To(Succeed())
+
+	t.Run("builds with the snapshot instead of the artifact runtime", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"bundle build -f oci://%s:1.0.0 -p main --runtime-snapshot %s",
+			bundleURL,
+			snapshotPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("production-app"))
+		g.Expect(output).ToNot(ContainSubstring("staging-app"))
+	})
+
+	t.Run("fails for snapshot with runtime flag", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"bundle build -f oci://%s:1.0.0 -p main --runtime-snapshot %s -r %s",
+			bundleURL,
+			snapshotPath,
+			filepath.Join(bundleDir, "runtime.cue"),
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("can't be used together with --runtime"))
+	})
+}
+
 func getObjectByName(objs []*unstructured.Unstructured, name string) (*unstructured.Unstructured, error) {
 	for _, obj := range objs {
 		if obj.GetName() == name {
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 
@@ -29,6 +31,7 @@ import (
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/flags"
 	"github.com/stefanprodan/timoni/internal/logger"
 	"github.com/stefanprodan/timoni/internal/runtime"
 )
@@ -57,6 +60,12 @@ If a delete times out, the instance record is kept so you can retry it.
 
   # Do a dry-run uninstall and print the changes
   timoni bundle delete my-app --dry-run
+
+  # Uninstall all instances in a bundle stored in a container registry
+  timoni bundle delete -f oci://ghcr.io/org/bundles/app:1.0.0
+
+  # Uninstall the instances of a bundle, except the database
+  timoni bundle delete my-app --exclude-instance database
 `,
 	RunE: runBundleDelCmd,
 }
@@ -66,6 +75,7 @@ type bundleDelFlags struct {
 	wait     bool
 	dryrun   bool
 	name     string
+	creds    flags.Credentials
 }
 
 var bundleDelArgs bundleDelFlags
@@ -76,12 +86,15 @@ func init() {
 	bundleDelCmd.Flags().BoolVar(&bundleDelArgs.dryrun, "dry-run", false,
 		"Perform a server-side delete dry run.")
 	bundleDelCmd.Flags().StringVarP(&bundleDelArgs.filename, "file", "f", "",
-		"The local path to bundle.cue file.")
+		"The local path to bundle.cue file or the OCI URL of a bundle artifact.")
 	bundleDelCmd.Flags().StringVar(&bundleDelArgs.name, "name", "",
 		"Name of the bundle to delete.")
 	if err := bundleDelCmd.Flags().MarkDeprecated("name", "use 'timoni bundle delete <name>'"); err != nil {
 		panic(err)
 	}
+	bundleDelCmd.Flags().Var(&bundleDelArgs.creds, bundleDelArgs.creds.Type(), bundleDelArgs.creds.Description())
+	addBundleVerifyFlags(bundleDelCmd)
+	addBundleSelectFlags(bundleDelCmd)
 	bundleCmd.AddCommand(bundleDelCmd)
 }
 
@@ -89,8 +102,27 @@ func runBundleDelCmd(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 && bundleDelArgs.filename == "" && bundleDelArgs.name == "" {
 		return errors.New("bundle name is required")
 	}
+	if err := bundleSelectArgs.validate(); err != nil {
+		return err
+	}
 
 	switch {
+	case isBundleArtifact(bundleDelArgs.filename):
+		tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		files, _, err := resolveBundleArtifact(cmd, []string{bundleDelArgs.filename}, tmpDir, bundleDelArgs.creds.String())
+		if err != nil {
+			return err
+		}
+		name, err := extractBundleName(files)
+		if err != nil {
+			return err
+		}
+		bundleDelArgs.name = name
 	case bundleDelArgs.filename != "":
 		cuectx := cuecontext.New()
 		name, err := engine.ExtractStringFromFile(cuectx, bundleDelArgs.filename, apiv1.BundleName.String())
@@ -128,6 +160,7 @@ func runBundleDelCmd(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		instances = selectStoredInstances(instances)
 
 		log := loggerBundle(ctx, bundleDelArgs.name, cluster.Name)
 
@@ -149,6 +182,13 @@ func runBundleDelCmd(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		}
+
+		// Drop the run record once all the instances are gone.
+		if !bundleSelectArgs.isSet() && !bundleDelArgs.dryrun {
+			if err := sm.DeleteBundleRun(ctx, bundleDelArgs.name); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -187,3 +227,18 @@ func deleteBundleInstance(ctx context.Context, instance *apiv1.BundleInstance, w
 
 	return deleteInstanceObjects(ctx, log, sm, iStorage, inst, objects, wait)
 }
+
+// extractBundleName returns the bundle name from the first
+// CUE file of the given bundle files that sets it.
+func extractBundleName(files []string) (string, error) {
+	cuectx := cuecontext.New()
+	for _, file := range files {
+		if filepath.Ext(file) != ".cue" {
+			continue
+		}
+		if name, err := engine.ExtractStringFromFile(cuectx, file, apiv1.BundleName.String()); err == nil {
+			return name, nil
+		}
+	}
+	return "", errors.New("bundle name not found in artifact")
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/logger"
+)
+
+const (
+	// rolloutSerial applies the bundle on one cluster at a time,
+	// in the order defined by the runtime.
+	rolloutSerial = "serial"
+
+	// rolloutParallel applies the bundle on all clusters at once.
+	rolloutParallel = "parallel"
+
+	// rolloutGroup applies the bundle group by group, in the order the
+	// groups are first defined in the runtime, with the clusters of a
+	// group being updated in parallel.
+	rolloutGroup = "group"
+
+	// rolloutCanary applies the bundle on the first cluster and,
+	// if it succeeds, on the remaining clusters in parallel.
+	rolloutCanary = "canary"
+)
+
+// rolloutStrategies is the list of supported rollout strategies.
+var rolloutStrategies = []string{rolloutSerial, rolloutParallel, rolloutGroup, rolloutCanary}
+
+// clusterRolloutResult holds the outcome of a bundle rollout on a cluster.
+type clusterRolloutResult struct {
+	cluster  apiv1.RuntimeCluster
+	err      error
+	skipped  bool
+	duration time.Duration
+}
+
+// rolloutStages splits the clusters into the ordered stages of the given
+// strategy. The clusters of a stage are updated concurrently, and a stage
+// starts only after the previous one has finished.
+func rolloutStages(strategy string, clusters []apiv1.RuntimeCluster) ([][]apiv1.RuntimeCluster, error) {
+	var stages [][]apiv1.RuntimeCluster
+	switch strategy {
+	case rolloutSerial:
+		for _, cluster := range clusters {
+			stages = append(stages, []apiv1.RuntimeCluster{cluster})
+		}
+	case rolloutParallel:
+		stages = append(stages, clusters)
+	case rolloutGroup:
+		index := make(map[string]int)
+		for _, cluster := range clusters {
+			i, ok := index[cluster.Group]
+			if !ok {
+				i = len(stages)
+				index[cluster.Group] = i
+				stages = append(stages, nil)
+			}
+			stages[i] = append(stages[i], cluster)
+		}
+	case rolloutCanary:
+		if len(clusters) > 0 {
+			stages = append(stages, clusters[:1])
+		}
+		if len(clusters) > 1 {
+			stages = append(stages, clusters[1:])
+		}
+	default:
+		return nil, fmt.Errorf("unknown rollout strategy '%s', must be one of: %s",
+			strategy, strings.Join(rolloutStrategies, ", "))
+	}
+	return stages, nil
+}
+
+// rolloutConcurrency returns the maximum number of clusters that are
+// updated at the same time, given the stages and the unavailability limit.
+func rolloutConcurrency(stages [][]apiv1.RuntimeCluster, maxUnavailable int) int {
+	result := 0
+	for _, stage := range stages {
+		n := len(stage)
+		if maxUnavailable > 0 {
+			n = min(n, maxUnavailable)
+		}
+		result = max(result, n)
+	}
+	return result
+}
+
+// rolloutClusters runs the apply function for every cluster, stage by
+// stage, with at most maxUnavailable clusters being updated at the same
+// time (zero means no limit). When haltOnFailure is set, a failure
+// prevents any further cluster from being started; the clusters already
+// in progress are allowed to finish. The results are returned in the
+// order of the stages.
+func rolloutClusters(ctx context.Context,
+	stages [][]apiv1.RuntimeCluster,
+	maxUnavailable int,
+	haltOnFailure bool,
+	applyFn func(context.Context, apiv1.RuntimeCluster) error) []clusterRolloutResult {
+	var results []clusterRolloutResult
+	var failed atomic.Bool
+
+	for _, stage := range stages {
+		stageResults := make([]clusterRolloutResult, len(stage))
+		limit := len(stage)
+		if maxUnavailable > 0 {
+			limit = min(limit, maxUnavailable)
+		}
+		sem := make(chan struct{}, max(limit, 1))
+
+		var wg sync.WaitGroup
+		for i, cluster := range stage {
+			stageResults[i].cluster = cluster
+			sem <- struct{}{}
+			if (haltOnFailure && failed.Load()) || ctx.Err() != nil {
+				<-sem
+				stageResults[i].skipped = true
+				continue
+			}
+
+			wg.Go(func() {
+				defer func() { <-sem }()
+				start := time.Now()
+				err := applyFn(ctx, cluster)
+				stageResults[i].err = err
+				stageResults[i].duration = time.Since(start)
+				if err != nil {
+					failed.Store(true)
+				}
+			})
+		}
+		wg.Wait()
+
+		results = append(results, stageResults...)
+	}
+
+	return results
+}
+
+// logRolloutSummary prints the outcome of the rollout on each cluster.
+func logRolloutSummary(log logr.Logger, results []clusterRolloutResult) {
+	for _, res := range results {
+		clusterLog := log.WithValues("caller", logger.ColorizeCluster(res.cluster.Name))
+		switch {
+		case res.skipped:
+			clusterLog.Info(logger.ColorizeWarning("skipped"))
+		case res.err != nil:
+			clusterLog.Error(res.err, fmt.Sprintf("failed after %s", res.duration.Round(time.Second)))
+		default:
+			clusterLog.Info(fmt.Sprintf("applied successfully in %s", res.duration.Round(time.Second)))
+		}
+	}
+}
+
+// rolloutErr returns the error of the failed clusters, if any. A single
+// failure is returned as is, multiple failures are joined and prefixed
+// with the cluster name.
+func rolloutErr(results []clusterRolloutResult) error {
+	var failed []clusterRolloutResult
+	for _, res := range results {
+		if res.err != nil {
+			failed = append(failed, res)
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0].err
+	default:
+		errs := make([]error, 0, len(failed))
+		for _, res := range failed {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", res.cluster.Name, res.err))
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+func testRolloutClusters() []apiv1.RuntimeCluster {
+	return []apiv1.RuntimeCluster{
+		{Name: "staging-1", Group: "staging"},
+		{Name: "prod-1", Group: "production"},
+		{Name: "staging-2", Group: "staging"},
+		{Name: "prod-2", Group: "production"},
+	}
+}
+
+func rolloutStageNames(stages [][]apiv1.RuntimeCluster) [][]string {
+	var result [][]string
+	for _, stage := range stages {
+		var names []string
+		for _, cluster := range stage {
+			names = append(names, cluster.Name)
+		}
+		result = append(result, names)
+	}
+	return result
+}
+
+func Test_RolloutStages(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     [][]string
+		wantErr  string
+	}{
+		{
+			strategy: rolloutSerial,
+			want:     [][]string{{"staging-1"}, {"prod-1"}, {"staging-2"}, {"prod-2"}},
+		},
+		{
+			strategy: rolloutParallel,
+			want:     [][]string{{"staging-1", "prod-1", "staging-2", "prod-2"}},
+		},
+		{
+			strategy: rolloutGroup,
+			want:     [][]string{{"staging-1", "staging-2"}, {"prod-1", "prod-2"}},
+		},
+		{
+			strategy: rolloutCanary,
+			want:     [][]string{{"staging-1"}, {"prod-1", "staging-2", "prod-2"}},
+		},
+		{
+			strategy: "blue-green",
+			wantErr:  "unknown rollout strategy 'blue-green'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			g := NewWithT(t)
+			stages, err := rolloutStages(tt.strategy, testRolloutClusters())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(rolloutStageNames(stages)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_RolloutClusters(t *testing.T) {
+	t.Run("limits concurrency to max unavailable", func(t *testing.T) {
+		g := NewWithT(t)
+		stages, err := rolloutStages(rolloutParallel, testRolloutClusters())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rolloutConcurrency(stages, 2)).To(Equal(2))
+
+		var inFlight, peak atomic.Int32
+		results := rolloutClusters(context.Background(), stages, 2, true,
+			func(_ context.Context, _ apiv1.RuntimeCluster) error {
+				n := inFlight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+				return nil
+			})
+
+		g.Expect(results).To(HaveLen(4))
+		g.Expect(peak.Load()).To(BeNumerically("<=", 2))
+		g.Expect(rolloutErr(results)).ToNot(HaveOccurred())
+	})
+
+	t.Run("halts the next stages on failure", func(t *testing.T) {
+		g := NewWithT(t)
+		stages, err := rolloutStages(rolloutGroup, testRolloutClusters())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		results := rolloutClusters(context.Background(), stages, 0, true,
+			func(_ context.Context, cluster apiv1.RuntimeCluster) error {
+				if cluster.Name == "staging-2" {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+		g.Expect(results).To(HaveLen(4))
+		g.Expect(results[0].err).ToNot(HaveOccurred())
+		g.Expect(results[1].err).To(MatchError("boom"))
+		g.Expect(results[2].skipped).To(BeTrue())
+		g.Expect(results[3].skipped).To(BeTrue())
+		g.Expect(rolloutErr(results)).To(MatchError("boom"))
+	})
+
+	t.Run("continues on failure", func(t *testing.T) {
+		g := NewWithT(t)
+		stages, err := rolloutStages(rolloutSerial, testRolloutClusters())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var mu sync.Mutex
+		var applied []string
+		results := rolloutClusters(context.Background(), stages, 0, false,
+			func(_ context.Context, cluster apiv1.RuntimeCluster) error {
+				mu.Lock()
+				applied = append(applied, cluster.Name)
+				mu.Unlock()
+				if cluster.Group == "production" {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+		g.Expect(results).To(HaveLen(4))
+		g.Expect(applied).To(Equal([]string{"staging-1", "prod-1", "staging-2", "prod-2"}))
+
+		err = rolloutErr(results)
+		g.Expect(err).To(MatchError(ContainSubstring("cluster prod-1: boom")))
+		g.Expect(err).To(MatchError(ContainSubstring("cluster prod-2: boom")))
+	})
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cuelang.org/go/cue"
+	"github.com/go-logr/logr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/runtime"
+)
+
+// bundleRunRecorder keeps the run record of a bundle apply on a cluster
+// up to date with the outcome of each instance, so that an interrupted
+// apply can be resumed and its progress inspected with bundle status.
+type bundleRunRecorder struct {
+	sm  *runtime.StorageManager
+	run *apiv1.BundleRun
+
+	// last is the record of the previous run, if any.
+	last *apiv1.BundleRun
+
+	// resume is set when the instances applied successfully
+	// in the previous run should be skipped.
+	resume bool
+
+	// dryRun is set when the cluster state must not be changed,
+	// in which case the record is not saved.
+	dryRun bool
+}
+
+// newBundleRunRecorder loads the record of the previous run and saves the
+// record of a new run, with the selected instances pending. The outcome of
+// the instances not selected in this run is carried over from the previous
+// run, and so is the outcome of all instances when resuming, so that an
+// entry only changes once its instance is processed. The record is kept in
+// the namespace of the previous record or, for a first run, in the
+// namespace of the kubeconfig.
+func newBundleRunRecorder(ctx context.Context,
+	kubeconfig *genericclioptions.ConfigFlags,
+	bundle *apiv1.Bundle,
+	digest string,
+	resume bool,
+	dryRun bool) (*bundleRunRecorder, error) {
+	rm, err := runtime.NewResourceManager(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	sm := runtime.NewStorageManager(rm)
+
+	last, err := sm.GetBundleRun(ctx, bundle.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if digest == "" {
+		digest, err = bundleDigest(bundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	run := &apiv1.BundleRun{
+		Bundle:    bundle.Name,
+		Namespace: *kubeconfig.Namespace,
+		Digest:    digest,
+		StartTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	if last != nil {
+		run.Namespace = last.Namespace
+	}
+
+	for _, instance := range bundle.Instances {
+		entry := apiv1.BundleRunInstance{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			Status:    apiv1.BundleRunPending,
+		}
+		if last != nil && (resume || !bundleSelectArgs.matches(instance.Name)) {
+			if prev := last.GetInstance(instance.Name, instance.Namespace); prev != nil {
+				entry = *prev
+			}
+		}
+		run.Instances = append(run.Instances, entry)
+	}
+
+	b := &bundleRunRecorder{
+		sm:     sm,
+		run:    run,
+		last:   last,
+		resume: resume,
+		dryRun: dryRun,
+	}
+
+	if err := b.save(ctx); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// resumeDigest returns the rendered digest the instance was applied with in
+// the previous run, or an empty string if the instance must be applied.
+func (b *bundleRunRecorder) resumeDigest(instance *apiv1.BundleInstance) string {
+	if !b.resume || b.last == nil {
+		return ""
+	}
+	prev := b.last.GetInstance(instance.Name, instance.Namespace)
+	if prev == nil || prev.Status != apiv1.BundleRunApplied {
+		return ""
+	}
+	return prev.Digest
+}
+
+// update records the outcome of an instance and saves the record.
+func (b *bundleRunRecorder) update(ctx context.Context, instance *apiv1.BundleInstance, digest string, applyErr error) error {
+	if entry := b.run.GetInstance(instance.Name, instance.Namespace); entry != nil {
+		entry.Digest = digest
+		entry.Status = apiv1.BundleRunApplied
+		entry.Error = ""
+		if applyErr != nil {
+			entry.Status = apiv1.BundleRunFailed
+			entry.Error = applyErr.Error()
+		}
+	}
+
+	return b.save(ctx)
+}
+
+// save writes the record to the cluster, unless running in dry-run mode.
+func (b *bundleRunRecorder) save(ctx context.Context) error {
+	if b.dryRun {
+		return nil
+	}
+	return b.sm.ApplyBundleRun(ctx, b.run)
+}
+
+// bundleDigest returns the digest of the bundle computed value.
+func bundleDigest(bundle *apiv1.Bundle) (string, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute the bundle digest: %w", err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+// renderedDigest returns the digest of the Kubernetes objects
+// rendered by the instance build.
+func renderedDigest(builder *engine.ModuleBuilder, buildResult cue.Value) (string, error) {
+	sets, err := builder.GetApplySets(buildResult)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	h := sha256.New()
+	for _, set := range sets {
+		for _, obj := range set.Objects {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return "", err
+			}
+			h.Write(data)
+		}
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// logBundleRun prints the progress of the last run of a bundle, with the
+// outcome of the instances that were not applied successfully.
+func logBundleRun(log logr.Logger, run *apiv1.BundleRun) {
+	var instances []apiv1.BundleRunInstance
+	for _, instance := range run.Instances {
+		if bundleSelectArgs.matches(instance.Name) {
+			instances = append(instances, instance)
+		}
+	}
+
+	applied := 0
+	for _, instance := range instances {
+		if instance.Status == apiv1.BundleRunApplied {
+			applied++
+		}
+	}
+
+	log.Info(fmt.Sprintf("last run started %s applied %s instance(s)",
+		logger.ColorizeSubject(run.StartTime),
+		logger.ColorizeSubject(fmt.Sprintf("%d/%d", applied, len(instances)))))
+	log.Info(fmt.Sprintf("last run digest %s", logger.ColorizeSubject(run.Digest)))
+
+	for _, instance := range instances {
+		switch instance.Status {
+		case apiv1.BundleRunFailed:
+			log.Error(errors.New(instance.Error), fmt.Sprintf("instance %s failed",
+				logger.ColorizeSubject(instance.Name)))
+		case apiv1.BundleRunPending:
+			log.Info(fmt.Sprintf("instance %s %s",
+				logger.ColorizeSubject(instance.Name), logger.ColorizeWarning("pending")))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// bundleSelectFlags holds the flags for selecting
+// a subset of the bundle instances.
+type bundleSelectFlags struct {
+	instances        []string
+	excludeInstances []string
+}
+
+var bundleSelectArgs bundleSelectFlags
+
+// addBundleSelectFlags registers the instance selection flags on the given command.
+func addBundleSelectFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&bundleSelectArgs.instances, "instance", nil,
+		"Select the bundle instances by name, supports glob patterns (can specify multiple).")
+	cmd.Flags().StringArrayVar(&bundleSelectArgs.excludeInstances, "exclude-instance", nil,
+		"Exclude the bundle instances by name, supports glob patterns (can specify multiple).")
+}
+
+// validate returns an error if any of the patterns is malformed.
+func (f bundleSelectFlags) validate() error {
+	for _, pattern := range append(f.instances, f.excludeInstances...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid instance pattern '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// isSet returns true if the instances are filtered.
+func (f bundleSelectFlags) isSet() bool {
+	return len(f.instances) > 0 || len(f.excludeInstances) > 0
+}
+
+// matches returns true if the instance name matches at least one of the
+// --instance patterns, or if there are none, and none of the
+// --exclude-instance patterns. The patterns must have been validated.
+func (f bundleSelectFlags) matches(name string) bool {
+	for _, pattern := range f.excludeInstances {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(f.instances) == 0 {
+		return true
+	}
+	for _, pattern := range f.instances {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// selectBundleInstances returns the bundle instances matching the selection
+// flags, in the order defined by the bundle. It returns an error if the
+// flags are set and no instance matches.
+func selectBundleInstances(instances []*apiv1.BundleInstance) ([]*apiv1.BundleInstance, error) {
+	if !bundleSelectArgs.isSet() {
+		return instances, nil
+	}
+
+	var result []*apiv1.BundleInstance
+	for _, instance := range instances {
+		if bundleSelectArgs.matches(instance.Name) {
+			result = append(result, instance)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no instances found matching the --instance and --exclude-instance filters")
+	}
+	return result, nil
+}
+
+// selectStoredInstances returns the instances of a bundle, as found in
+// the cluster storage, that match the selection flags.
+func selectStoredInstances(instances []*apiv1.Instance) []*apiv1.Instance {
+	if !bundleSelectArgs.isSet() {
+		return instances
+	}
+
+	var result []*apiv1.Instance
+	for _, instance := range instances {
+		if bundleSelectArgs.matches(instance.Name) {
+			result = append(result, instance)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+func Test_SelectBundleInstances(t *testing.T) {
+	instances := []*apiv1.BundleInstance{
+		{Name: "frontend"},
+		{Name: "backend-api"},
+		{Name: "backend-worker"},
+		{Name: "redis"},
+	}
+
+	tests := []struct {
+		name    string
+		flags   bundleSelectFlags
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "selects all instances by default",
+			want: []string{"frontend", "backend-api", "backend-worker", "redis"},
+		},
+		{
+			name:  "selects by name and glob in bundle order",
+			flags: bundleSelectFlags{instances: []string{"redis", "backend-*"}},
+			want:  []string{"backend-api", "backend-worker", "redis"},
+		},
+		{
+			name:  "excludes by glob",
+			flags: bundleSelectFlags{excludeInstances: []string{"*-worker", "redis"}},
+			want:  []string{"frontend", "backend-api"},
+		},
+		{
+			name: "exclusion takes precedence",
+			flags: bundleSelectFlags{
+				instances:        []string{"backend-*"},
+				excludeInstances: []string{"backend-worker"},
+			},
+			want: []string{"backend-api"},
+		},
+		{
+			name:    "fails when nothing matches",
+			flags:   bundleSelectFlags{instances: []string{"fronted"}},
+			wantErr: "no instances found matching",
+		},
+		{
+			name:    "fails for malformed patterns",
+			flags:   bundleSelectFlags{excludeInstances: []string{"back[end"}},
+			wantErr: "invalid instance pattern 'back[end'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			bundleSelectArgs = tt.flags
+			defer func() { bundleSelectArgs = bundleSelectFlags{} }()
+
+			err := bundleSelectArgs.validate()
+			var selected []*apiv1.BundleInstance
+			if err == nil {
+				selected, err = selectBundleInstances(instances)
+			}
+
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var names []string
+			for _, instance := range selected {
+				names = append(names, instance.Name)
+			}
+			g.Expect(names).To(Equal(tt.want))
+		})
+	}
+}
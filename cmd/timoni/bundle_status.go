@@ -43,6 +43,9 @@ var bundleStatusCmd = &cobra.Command{
 
   # Show the status using a named bundle
   timoni bundle status my-app
+
+  # Show the status of a single instance of a bundle
+  timoni bundle status my-app --instance frontend
 `,
 	RunE: runBundleStatusCmd,
 }
@@ -57,6 +60,7 @@ var bundleStatusArgs bundleStatusFlags
 func init() {
 	bundleStatusCmd.Flags().StringVarP(&bundleStatusArgs.filename, "file", "f", "",
 		"The local path to bundle.cue file.")
+	addBundleSelectFlags(bundleStatusCmd)
 	bundleCmd.AddCommand(bundleStatusCmd)
 }
 
@@ -64,6 +68,9 @@ func runBundleStatusCmd(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 && bundleStatusArgs.filename == "" {
 		return fmt.Errorf("bundle name is required")
 	}
+	if err := bundleSelectArgs.validate(); err != nil {
+		return err
+	}
 
 	switch {
 	case bundleStatusArgs.filename != "":
@@ -104,9 +111,18 @@ func runBundleStatusCmd(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		instances = selectStoredInstances(instances)
 
 		log := loggerBundle(ctx, bundleStatusArgs.name, cluster.Name)
 
+		run, err := sm.GetBundleRun(ctx, bundleStatusArgs.name)
+		if err != nil {
+			return err
+		}
+		if run != nil {
+			logBundleRun(log, run)
+		}
+
 		if len(instances) == 0 {
 			log.Error(nil, "no instances found in bundle")
 			failed = true
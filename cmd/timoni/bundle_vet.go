@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"os"
@@ -39,6 +40,14 @@ var bundleVetCmd = &cobra.Command{
 	Short:   "Validate a bundle definition",
 	Long: `The bundle vet command validates that a bundle definition conforms
 with Timoni's schema and optionally prints the computed value.
+
+With --modules, the modules referenced by the bundle instances are fetched,
+and each instance is built offline to check that its values conform with
+the module's schema. All the errors found are reported with the position
+of the offending values in the bundle files.
+
+With --policy, the objects rendered for each instance are checked against
+the rules of the CUE policy packs, which implies --modules.
 `,
 	Example: `  # Validate a bundle and list its instances
   timoni bundle vet -f bundle.cue
@@ -54,6 +63,20 @@ with Timoni's schema and optionally prints the computed value.
   -f bundle.cue \
   -r runtime.cue \
   --print-value
+
+  # Validate a bundle for all the clusters of a runtime snapshot
+  timoni bundle vet \
+  -f bundle.cue \
+  --runtime-snapshot snapshot.json
+
+  # Validate a bundle stored in a container registry
+  timoni bundle vet -f oci://ghcr.io/org/bundles/app:1.0.0
+
+  # Validate the instance values against the modules schema
+  timoni bundle vet -f bundle.cue --modules
+
+  # Validate the objects rendered for each instance against a policy pack
+  timoni bundle vet -f bundle.cue --policy oci://ghcr.io/org/policies/baseline:1.0.0
 `,
 	Args: cobra.NoArgs,
 	RunE: runBundleVetCmd,
@@ -63,6 +86,9 @@ type bundleVetFlags struct {
 	pkg        flags.Package
 	files      []string
 	printValue bool
+	modules    bool
+	creds      flags.Credentials
+	policies   []string
 }
 
 var bundleVetArgs bundleVetFlags
@@ -70,9 +96,15 @@ var bundleVetArgs bundleVetFlags
 func init() {
 	bundleVetCmd.Flags().VarP(&bundleVetArgs.pkg, bundleVetArgs.pkg.Type(), bundleVetArgs.pkg.Shorthand(), bundleVetArgs.pkg.Description())
 	bundleVetCmd.Flags().StringSliceVarP(&bundleVetArgs.files, "file", "f", nil,
-		"The local path to bundle.cue files.")
+		"The local path to bundle.cue files or the OCI URL of a bundle artifact.")
 	bundleVetCmd.Flags().BoolVar(&bundleVetArgs.printValue, "print-value", false,
 		"Print the computed value of the bundle.")
+	bundleVetCmd.Flags().BoolVar(&bundleVetArgs.modules, "modules", false,
+		"Fetch the modules and validate the instance values against each module's schema.")
+	bundleVetCmd.Flags().Var(&bundleVetArgs.creds, bundleVetArgs.creds.Type(), bundleVetArgs.creds.Description())
+	addPolicyFlag(bundleVetCmd, &bundleVetArgs.policies)
+	addBundleVerifyFlags(bundleVetCmd)
+	addRuntimeSnapshotFlag(bundleVetCmd)
 	bundleCmd.AddCommand(bundleVetCmd)
 }
 
@@ -97,6 +129,17 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 		defer os.Remove(stdinFile)
 	}
 
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files, _, err = resolveBundleArtifact(cmd, files, tmpDir, bundleVetArgs.creds.String())
+	if err != nil {
+		return err
+	}
+
 	workdir, err := resolveWorkdir(bundleArgs.workdir)
 	if err != nil {
 		return err
@@ -105,6 +148,8 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 	cuectx := cuecontext.New()
 	bm := engine.NewBundleBuilder(cuectx, files)
 	bm.SetWorkdir(workdir)
+	decrypter := newDecrypter()
+	bm.SetDecrypter(decrypter)
 
 	runtimeValues := make(map[string]string)
 
@@ -112,11 +157,21 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 		maps.Copy(runtimeValues, engine.GetEnv())
 	}
 
-	rt, err := buildRuntime(bundleArgs.runtimeFiles, bundleArgs.workdir)
+	snapshot, err := loadRuntimeSnapshot()
 	if err != nil {
 		return err
 	}
 
+	var rt *apiv1.Runtime
+	if snapshot != nil {
+		rt = snapshot.Runtime()
+	} else {
+		rt, err = buildRuntime(bundleArgs.runtimeFiles, bundleArgs.workdir)
+		if err != nil {
+			return err
+		}
+	}
+
 	clusters := rt.SelectClusters(bundleArgs.runtimeCluster, bundleArgs.runtimeClusterGroup)
 	if len(clusters) == 0 {
 		return fmt.Errorf("no cluster found")
@@ -125,6 +180,14 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 	kctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 	defer cancel()
 
+	policies, err := loadPolicies(cmd, bundleVetArgs.policies, tmpDir, bundleVetArgs.creds.String())
+	if err != nil {
+		return err
+	}
+
+	moduleCache := make(map[moduleCacheKey]*fetchedModule)
+	var vetErrs []error
+
 	for _, cluster := range clusters {
 		kubeconfigArgs.Context = &cluster.KubeContext
 
@@ -133,17 +196,25 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 		// add values from env
 		maps.Copy(clusterValues, runtimeValues)
 
-		// add values from cluster
-		rm, err := runtime.NewResourceManager(kubeconfigArgs)
-		if err != nil {
-			return err
-		}
-		reader := runtime.NewResourceReader(rm)
-		rv, err := reader.Read(kctx, rt.Refs)
-		if err != nil {
-			return err
+		// add values from the snapshot or from cluster
+		if snapshot != nil {
+			rv, err := runtime.SnapshotValues(snapshot.GetCluster(cluster.Name), decrypter)
+			if err != nil {
+				return err
+			}
+			maps.Copy(clusterValues, rv)
+		} else {
+			rm, err := runtime.NewResourceManager(kubeconfigArgs)
+			if err != nil {
+				return err
+			}
+			reader := newResourceReader(rm, decrypter, bundleLocalProviders())
+			rv, err := reader.Read(kctx, rt.Refs)
+			if err != nil {
+				return err
+			}
+			maps.Copy(clusterValues, rv)
 		}
-		maps.Copy(clusterValues, rv)
 
 		// add cluster info
 		maps.Copy(clusterValues, cluster.NameGroupValues())
@@ -170,6 +241,20 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no instances found in bundle")
 		}
 
+		invalid := make(map[string]bool)
+		if bundleVetArgs.modules || policies != nil {
+			for _, i := range bundle.Instances {
+				log := loggerBundleInstance(logr.NewContext(cmd.Context(), log), bundle.Name, cluster.Name, i.Name, true)
+				if err := vetBundleInstanceModule(kctx, log, cuectx, bm, i, tmpDir, moduleCache, policies); err != nil {
+					if !cluster.IsDefault() {
+						err = fmt.Errorf("cluster %s: %w", cluster.Name, err)
+					}
+					vetErrs = append(vetErrs, fmt.Errorf("instance %s: %w", i.Name, err))
+					invalid[i.Name] = true
+				}
+			}
+		}
+
 		if bundleVetArgs.printValue {
 			val := v.LookupPath(cue.ParsePath("bundle"))
 			if val.Err() != nil {
@@ -188,14 +273,70 @@ func runBundleVetCmd(cmd *cobra.Command, args []string) error {
 				if i.Namespace == "" {
 					return fmt.Errorf("instance %s does not have a namespace", i.Name)
 				}
+				if invalid[i.Name] {
+					continue
+				}
 				log := loggerBundleInstance(logr.NewContext(cmd.Context(), log), bundle.Name, cluster.Name, i.Name, true)
 				log.Info("instance is valid")
 			}
 		}
 	}
 
+	if len(vetErrs) > 0 {
+		return errors.Join(vetErrs...)
+	}
+
 	if !bundleVetArgs.printValue {
 		log.Info("bundle is valid")
 	}
 	return nil
 }
+
+// vetBundleInstanceModule fetches the module of a bundle instance and
+// builds the instance offline, to check that its values conform with
+// the module's schema and that the rendered objects comply with the policies.
+func vetBundleInstanceModule(ctx context.Context,
+	log logr.Logger,
+	cuectx *cue.Context,
+	bm *engine.BundleBuilder,
+	instance *apiv1.BundleInstance,
+	rootDir string,
+	cache map[moduleCacheKey]*fetchedModule,
+	policies *engine.PolicySet) error {
+	modDir, err := fetchBundleInstanceModule(ctx, instance, rootDir, bundleVetArgs.creds.String(), cache)
+	if err != nil {
+		return err
+	}
+
+	builder := engine.NewModuleBuilder(
+		cuectx,
+		instance.Name,
+		instance.Namespace,
+		modDir,
+		bundleVetArgs.pkg.String(),
+	)
+
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+
+	// The values are checked against the schema before the build,
+	// as the build errors point to the values merged with the
+	// module defaults instead of the bundle files.
+	if err := builder.ValidateValues(instance.Values); err != nil {
+		return describeBundleErr(bm, modDir, "invalid values", err)
+	}
+
+	if err := builder.OverlayValuesFileWithDefaults(instance.Values); err != nil {
+		return describeBundleErr(bm, modDir, "invalid values", err)
+	}
+
+	builder.SetVersionInfo(instance.Module.Version, "")
+
+	buildResult, err := builder.Build()
+	if err != nil {
+		return describeBundleErr(bm, modDir, "build failed", err)
+	}
+
+	return enforcePolicies(log, policies, builder, buildResult)
+}
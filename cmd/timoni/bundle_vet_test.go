@@ -501,3 +501,68 @@ bundle: {
 		g.Expect(err.Error()).To(ContainSubstring("invalid workdir"))
 	})
 }
+
+func Test_BundleVet_Modules(t *testing.T) {
+	g := NewWithT(t)
+
+	modPath, err := filepath.Abs("testdata/module")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundleCue := fmt.Sprintf(`bundle: {
+	apiVersion: "v1alpha1"
+	name:       "modules-test"
+	instances: {
+		frontend: {
+			module: url: "file://%[1]s"
+			namespace: "modules-test"
+			values: team: "frontend"
+		}
+		backend: {
+			module: url: "file://%[1]s"
+			namespace: "modules-test"
+			values: {
+				team:     "backend"
+				priority: -1
+			}
+		}
+		database: {
+			module: url: "file://%[1]s"
+			namespace: "modules-test"
+			values: {
+				team: "database"
+				servr: enabled: false
+			}
+		}
+	}
+}
+`, modPath)
+
+	wd := t.TempDir()
+	bundlePath := filepath.Join(wd, "bundle.cue")
+	g.Expect(os.WriteFile(bundlePath, []byte(bundleCue), 0644)).ToNot(HaveOccurred())
+
+	t.Run("reports the errors of all instances", func(t *testing.T) {
+		g := NewWithT(t)
+
+		output, err := executeCommand(fmt.Sprintf("bundle vet -f %s --modules", bundlePath))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("frontend"))
+		g.Expect(output).To(ContainSubstring("instance is valid"))
+
+		g.Expect(err.Error()).To(ContainSubstring("instance backend: invalid values"))
+		g.Expect(err.Error()).To(ContainSubstring("values.priority: invalid value -1"))
+		g.Expect(err.Error()).To(ContainSubstring(bundlePath + ":15:15"))
+
+		g.Expect(err.Error()).To(ContainSubstring("instance database: invalid values"))
+		g.Expect(err.Error()).To(ContainSubstring("values.servr: field not allowed"))
+		g.Expect(err.Error()).To(ContainSubstring(bundlePath + ":23:5"))
+		g.Expect(err.Error()).ToNot(ContainSubstring("instance frontend"))
+	})
+
+	t.Run("skips the modules by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := executeCommand(fmt.Sprintf("bundle vet -f %s", bundlePath))
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
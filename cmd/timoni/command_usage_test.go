@@ -40,6 +40,8 @@ func TestRequiredOperandsAreNotOptionalInUsage(t *testing.T) {
 		{inspectResourcesCmd, "resources INSTANCE_NAME"},
 		{inspectValuesCmd, "values INSTANCE_NAME"},
 		{initModCmd, "init MODULE_NAME [PATH]"},
+		{diffModCmd, "diff PREVIOUS_MODULE NEW_MODULE"},
+		{importYAMLModCmd, "yaml MODULE_NAME [PATH]"},
 		{listModCmd, "list MODULE_URL"},
 		{pullModCmd, "pull MODULE_URL"},
 		{pushModCmd, "push MODULE_PATH MODULE_URL"},
@@ -18,8 +18,14 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+
+	"github.com/stefanprodan/timoni/internal/engine"
 )
 
 func describeErr(moduleRoot, description string, err error) error {
@@ -27,3 +33,45 @@ func describeErr(moduleRoot, description string, err error) error {
 		Cwd: moduleRoot,
 	}))
 }
+
+// describeBundleErr is like describeErr but for errors involving the values
+// of a bundle instance. The positions in the bundle workspace are mapped to
+// the files the bundle was loaded from, and the positions in the module are
+// made relative to the module root.
+func describeBundleErr(bm *engine.BundleBuilder, moduleRoot, description string, err error) error {
+	var sb strings.Builder
+	var seen []string
+	for _, e := range errors.Errors(err) {
+		var eb strings.Builder
+		if path := e.Path(); len(path) > 0 {
+			eb.WriteString(strings.Join(path, "."))
+			eb.WriteString(": ")
+		}
+		format, args := e.Msg()
+		eb.WriteString(fmt.Sprintf(format, args...))
+
+		positions := e.InputPositions()
+		if pos := e.Position(); pos.IsValid() && !slices.Contains(positions, pos) {
+			positions = append([]token.Pos{pos}, positions...)
+		}
+		if len(positions) > 0 {
+			eb.WriteString(":")
+		}
+		eb.WriteString("\n")
+		for _, pos := range positions {
+			file := pos.Filename()
+			if origin, ok := bm.OriginFile(file); ok {
+				file = origin
+			} else if rel, err := filepath.Rel(moduleRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			eb.WriteString(fmt.Sprintf("    %s:%d:%d\n", file, pos.Line(), pos.Column()))
+		}
+
+		if msg := eb.String(); !slices.Contains(seen, msg) {
+			seen = append(seen, msg)
+			sb.WriteString(msg)
+		}
+	}
+	return fmt.Errorf("%s:\n%s", description, strings.TrimSuffix(sb.String(), "\n"))
+}
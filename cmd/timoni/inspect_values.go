@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/stefanprodan/timoni/internal/engine"
 	"github.com/stefanprodan/timoni/internal/runtime"
 )
 
@@ -33,6 +35,11 @@ var inspectValuesCmd = &cobra.Command{
 
   # Export the values of an instance to a CUE file
   timoni -n default inspect values app > values.cue
+
+  # Export the values of an instance including the sensitive values
+  # encrypted with age, for example to roll back to a previous configuration
+  timoni -n default inspect values app --decrypt \
+  --sops-age-key-file ./age.txt > values.cue
 `,
 	RunE: runInspectValuesCmd,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -46,12 +53,15 @@ var inspectValuesCmd = &cobra.Command{
 }
 
 type inspectValuesFlags struct {
-	name string
+	name    string
+	decrypt bool
 }
 
 var inspectValuesArgs inspectValuesFlags
 
 func init() {
+	inspectValuesCmd.Flags().BoolVar(&inspectValuesArgs.decrypt, "decrypt", false,
+		"Decrypt the sensitive values encrypted with age, instead of masking them.")
 	inspectCmd.AddCommand(inspectValuesCmd)
 }
 
@@ -75,6 +85,15 @@ func runInspectValuesCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	_, err = fmt.Fprintln(cmd.OutOrStdout(), "values:", inst.Values)
+	var decrypt func(string) (string, error)
+	if inspectValuesArgs.decrypt {
+		decrypt = newDecrypter().DecryptValue
+	}
+	values, err := engine.RevealValues(inst.Values, decrypt)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), "values:", values)
 	return err
 }
@@ -63,6 +63,7 @@ type rootFlags struct {
 	coloredLog       bool
 	cacheDir         string
 	registryInsecure bool
+	sopsAgeKeyFile   string
 }
 
 var (
@@ -86,6 +87,8 @@ func init() {
 		"Artifacts cache dir, can be disable with 'TIMONI_CACHING=false' env var. (defaults to \"$HOME/.timoni/cache\")")
 	rootCmd.PersistentFlags().BoolVar(&rootArgs.registryInsecure, "registry-insecure", false,
 		"If true, allows connecting to a container registry without TLS or with a self-signed certificate.")
+	rootCmd.PersistentFlags().StringVar(&rootArgs.sopsAgeKeyFile, "sops-age-key-file", "",
+		"The path to the age keys used to decrypt SOPS files. (defaults to the SOPS_AGE_KEY and SOPS_AGE_KEY_FILE env vars)")
 
 	addKubeConfigFlags(rootCmd)
 
@@ -173,6 +176,36 @@ func addKubeConfigFlags(cmd *cobra.Command) {
 	}
 }
 
+// kubeconfigForContext returns a copy of the kubectl config flags set to the
+// given kubeconfig context. Unlike setting the context on the global flags,
+// the copy allows multiple clusters to be targeted concurrently.
+func kubeconfigForContext(kubeContext string) *genericclioptions.ConfigFlags {
+	kc := genericclioptions.NewConfigFlags(false)
+	kc.CacheDir = kubeconfigArgs.CacheDir
+	kc.KubeConfig = kubeconfigArgs.KubeConfig
+	kc.ClusterName = kubeconfigArgs.ClusterName
+	kc.AuthInfoName = kubeconfigArgs.AuthInfoName
+	kc.Namespace = kubeconfigArgs.Namespace
+	kc.APIServer = kubeconfigArgs.APIServer
+	kc.TLSServerName = kubeconfigArgs.TLSServerName
+	kc.Insecure = kubeconfigArgs.Insecure
+	kc.CertFile = kubeconfigArgs.CertFile
+	kc.KeyFile = kubeconfigArgs.KeyFile
+	kc.CAFile = kubeconfigArgs.CAFile
+	kc.BearerToken = kubeconfigArgs.BearerToken
+	kc.Impersonate = kubeconfigArgs.Impersonate
+	kc.ImpersonateUID = kubeconfigArgs.ImpersonateUID
+	kc.ImpersonateGroup = kubeconfigArgs.ImpersonateGroup
+	kc.ImpersonateUserExtra = kubeconfigArgs.ImpersonateUserExtra
+	kc.Username = kubeconfigArgs.Username
+	kc.Password = kubeconfigArgs.Password
+	kc.Timeout = kubeconfigArgs.Timeout
+	kc.DisableCompression = kubeconfigArgs.DisableCompression
+	kc.WrapConfigFn = kubeconfigArgs.WrapConfigFn
+	kc.Context = &kubeContext
+	return kc
+}
+
 func getCurrentKubeconfigPath() string {
 	defaultPath := ""
 
@@ -187,8 +187,10 @@ func resetCmdArgs() {
 	inspectModuleArgs = inspectModuleFlags{}
 	inspectResourcesArgs = inspectResourcesFlags{}
 	inspectValuesArgs = inspectValuesFlags{}
+	testModArgs = testModFlags{name: "default"}
 	vetModArgs = vetModFlags{
-		name: "default",
+		name:       "default",
+		lintOutput: "text",
 	}
 	listArgs = listFlags{}
 	listModArgs = listModFlags{withDigest: true, limit: 100}
@@ -196,15 +198,26 @@ func resetCmdArgs() {
 	pullModArgs = pullModFlags{}
 	configShowModArgs = configModFlags{name: "module-name"}
 	readmeShowModArgs = readmeModFlags{}
+	valuesTemplateShowModArgs = valuesTemplateModFlags{output: "cue"}
 	pushModArgs = pushModFlags{}
+	diffModArgs = diffModFlags{}
+	migrateValuesModArgs = migrateValuesModFlags{}
 	buildModArgs = buildModFlags{format: "oci-archive"}
 	bundleArgs = bundleFlags{}
-	bundleApplyArgs = bundleApplyFlags{}
+	bundleApplyArgs = bundleApplyFlags{
+		rolloutStrategy: rolloutSerial,
+		haltOnFailure:   true,
+	}
 	bundleVetArgs = bundleVetFlags{}
 	bundleDelArgs = bundleDelFlags{}
+	bundleVerifyArgs = bundleVerifyFlags{}
+	valuesVerifyArgs = valuesVerifyFlags{}
+	policyVerifyArgs = policyVerifyFlags{}
+	bundleSelectArgs = bundleSelectFlags{}
 	bundleBuildArgs = bundleBuildFlags{}
 	vendorCrdArgs = vendorCrdFlags{}
 	vendorK8sArgs = vendorK8sFlags{}
+	importYAMLModArgs = importYAMLModFlags{blueprintURL: modBlueprintURL, kubeVersion: "latest"}
 	pushArtifactArgs = pushArtifactFlags{
 		path:        ".",
 		contentType: "generic",
@@ -218,6 +231,7 @@ func resetCmdArgs() {
 	digestArtifactArgs = digestArtifactFlags{}
 	runtimeBuildArgs = runtimeBuildFlags{}
 	versionArgs = versionFlags{output: "yaml"}
+	rootArgs.sopsAgeKeyFile = ""
 }
 
 func rnd(prefix string) string {
@@ -113,6 +113,10 @@ func buildModCmdRun(cmd *cobra.Command, args []string) (err error) {
 	if err := appendImagesAnnotation(module, annotations); err != nil {
 		return err
 	}
+	log := LoggerFrom(cmd.Context())
+	if err := appendConfigSchemaAnnotation(module, annotations); err != nil {
+		log.Info(logger.ColorizeWarning(fmt.Sprintf("config schema annotation skipped: %s", err)))
+	}
 	ignorePaths, err := engine.ReadIgnoreFile(module)
 	if err != nil {
 		return fmt.Errorf("reading %s failed: %w", apiv1.IgnoreFile, err)
@@ -129,7 +133,6 @@ func buildModCmdRun(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	log := LoggerFrom(cmd.Context())
 	log.Info(fmt.Sprintf("artifact: %s", logger.ColorizeSubject(filepath.Clean(buildModArgs.output))))
 	log.Info(fmt.Sprintf("digest: %s", logger.ColorizeSubject(build.Digest.String())))
 	return nil
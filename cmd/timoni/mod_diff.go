@@ -0,0 +1,294 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/engine/fetcher"
+	"github.com/stefanprodan/timoni/internal/flags"
+	"github.com/stefanprodan/timoni/internal/logger"
+)
+
+var diffModCmd = &cobra.Command{
+	Use:   "diff PREVIOUS_MODULE NEW_MODULE",
+	Args:  cobra.ExactArgs(2),
+	Short: "Report the breaking changes between two versions of a module",
+	Long: `The diff command compares two versions of a module and classifies
+the changes as breaking or non-breaking.
+
+The #Config schemas are compared field by field. Removed or renamed fields,
+newly required fields and narrowed types are breaking, while added optional
+fields, widened types and changed defaults are not.
+
+The objects rendered with the default values of each version are compared too.
+Changes to immutable fields, such as the selector of a Deployment or the volume
+claim templates of a StatefulSet, are breaking unless the object is annotated
+with 'action.timoni.sh/force: "enabled"'. Both versions are rendered with the
+same module version, so that the version labels don't count as changes. When a
+module can't be built with its default values, the objects are not compared.
+
+The modules can be local directories or OCI artifacts, in the format
+'oci://<repo>:<version>' or 'oci://<repo>@<digest>'.
+
+The command exits with an error if breaking changes are found.`,
+	Example: `  # Compare two versions of a module published to a container registry
+  timoni mod diff oci://docker.io/org/app:1.2.0 oci://docker.io/org/app:1.3.0
+
+  # Compare the latest release with the module in the current directory
+  timoni mod diff oci://docker.io/org/app:latest .
+
+  # Print the changes in JSON format
+  timoni mod diff oci://docker.io/org/app:1.2.0 ./app -o json
+`,
+	RunE: runDiffModCmd,
+}
+
+type diffModFlags struct {
+	creds  flags.Credentials
+	pkg    flags.Package
+	output string
+}
+
+var diffModArgs diffModFlags
+
+func init() {
+	diffModCmd.Flags().Var(&diffModArgs.creds, diffModArgs.creds.Type(), diffModArgs.creds.Description())
+	diffModCmd.Flags().VarP(&diffModArgs.pkg, diffModArgs.pkg.Type(), diffModArgs.pkg.Shorthand(), diffModArgs.pkg.Description())
+	diffModCmd.Flags().StringVarP(&diffModArgs.output, "output", "o", "",
+		"The format in which the changes should be printed, can be 'yaml' or 'json'.")
+	modCmd.AddCommand(diffModCmd)
+}
+
+func runDiffModCmd(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(diffModArgs.output, true); err != nil {
+		return err
+	}
+
+	log := LoggerFrom(cmd.Context())
+
+	_, changes, err := diffModules(cmd, args[0], args[1], diffModArgs.creds.String(), diffModArgs.pkg.String())
+	if err != nil {
+		return err
+	}
+
+	switch diffModArgs.output {
+	case "json":
+		if changes == nil {
+			changes = []engine.ModuleChange{}
+		}
+		marshalled, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("changes JSON conversion failed: %w", err)
+		}
+		marshalled = append(marshalled, "\n"...)
+		if _, err := cmd.OutOrStdout().Write(marshalled); err != nil {
+			return err
+		}
+	case "yaml":
+		if changes == nil {
+			changes = []engine.ModuleChange{}
+		}
+		marshalled, err := yaml.Marshal(changes)
+		if err != nil {
+			return fmt.Errorf("changes YAML conversion failed: %w", err)
+		}
+		if _, err := cmd.OutOrStdout().Write(marshalled); err != nil {
+			return err
+		}
+	default:
+		logModuleChanges(log, changes)
+	}
+
+	if n := countBreakingChanges(changes); n > 0 {
+		return fmt.Errorf("found %d breaking change(s)", n)
+	}
+	if diffModArgs.output == "" {
+		log.Info("no breaking changes found")
+	}
+	return nil
+}
+
+// diffModules fetches the previous and the new module versions and returns
+// the changes between them, along with the reference of the previous module.
+func diffModules(cmd *cobra.Command, previous, next, creds, pkg string) (*apiv1.ModuleReference, []engine.ModuleChange, error) {
+	log := LoggerFrom(cmd.Context())
+	cuectx := cuecontext.New()
+
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldMod, oldFields, oldObjects, err := loadModuleForDiff(cmd, cuectx, previous, filepath.Join(tmpDir, "previous"), creds, pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, newFields, newObjects, err := loadModuleForDiff(cmd, cuectx, next, filepath.Join(tmpDir, "new"), creds, pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := engine.DiffConfig(oldFields, newFields)
+	if oldObjects != nil && newObjects != nil {
+		changes = append(changes, engine.DiffObjects(oldObjects, newObjects)...)
+	} else {
+		log.Info(logger.ColorizeWarning("rendered objects not compared, the module can't be built with its default values"))
+	}
+
+	return oldMod, changes, nil
+}
+
+// loadModuleForDiff fetches the module and returns its #Config fields and
+// the objects rendered with the default values. The objects are nil if
+// the module can't be built without values, e.g. it has required fields.
+func loadModuleForDiff(cmd *cobra.Command, cuectx *cue.Context, src, dir, creds, pkg string) (*apiv1.ModuleReference, []engine.ConfigField, []*unstructured.Unstructured, error) {
+	source, version := splitModuleSource(src)
+
+	ctxPull, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	f, err := fetcher.New(ctxPull, fetcher.Options{
+		Source:       source,
+		Version:      version,
+		Destination:  dir,
+		CacheDir:     rootArgs.cacheDir,
+		Creds:        creds,
+		Insecure:     rootArgs.registryInsecure,
+		DefaultLocal: true,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	mod, err := f.Fetch()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	builder := engine.NewModuleBuilder(
+		cuectx,
+		"module-name",
+		*kubeconfigArgs.Namespace,
+		f.GetModuleRoot(),
+		pkg,
+	)
+
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	configValue, err := builder.BuildConfig()
+	if err != nil {
+		return nil, nil, nil, describeErr(f.GetModuleRoot(), fmt.Sprintf("build of %s failed", src), err)
+	}
+
+	fields, err := builder.GetConfigDoc(configValue)
+	if err != nil {
+		return nil, nil, nil, describeErr(f.GetModuleRoot(), "failed to get config structure", err)
+	}
+
+	buildResult, err := builder.Build()
+	if err != nil {
+		return mod, fields, nil, nil
+	}
+
+	applySets, err := builder.GetApplySets(buildResult)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("build of %s failed: %w", src, err)
+	}
+
+	objects := []*unstructured.Unstructured{}
+	for _, set := range applySets {
+		objects = append(objects, set.Objects...)
+	}
+
+	return mod, fields, objects, nil
+}
+
+// splitModuleSource splits an OCI module URL in the format
+// 'oci://<repo>:<version>' or 'oci://<repo>@<digest>' into the repository
+// and the version. A URL without a version refers to the latest version,
+// while local paths are returned as they are.
+func splitModuleSource(src string) (string, string) {
+	if !strings.HasPrefix(src, apiv1.ArtifactPrefix) {
+		return src, ""
+	}
+	if i := strings.LastIndex(src, "@"); i > 0 {
+		return src[:i], src[i:]
+	}
+	if i := strings.LastIndex(src, ":"); i > strings.LastIndex(src, "/") {
+		return src[:i], src[i+1:]
+	}
+	return src, apiv1.LatestVersion
+}
+
+// logModuleChanges prints the changes, the breaking ones as errors.
+func logModuleChanges(log logr.Logger, changes []engine.ModuleChange) {
+	for _, c := range changes {
+		if c.Breaking {
+			log.Error(nil, fmt.Sprintf("%s %s", logger.ColorizeSubject(c.Subject), c.String()))
+			continue
+		}
+		log.Info(fmt.Sprintf("%s %s", logger.ColorizeSubject(c.Subject), logger.ColorizeInfo(c.String())))
+	}
+}
+
+// countBreakingChanges returns the number of breaking changes.
+func countBreakingChanges(changes []engine.ModuleChange) int {
+	var n int
+	for _, c := range changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// isMajorRelease returns true if the new version is allowed to contain
+// breaking changes compared to the previous one, which is the case for
+// a major version bump, or a minor version bump before 1.0.0.
+func isMajorRelease(previous, next string) (bool, error) {
+	pv, err := semver.StrictNewVersion(previous)
+	if err != nil {
+		return false, fmt.Errorf("previous version %s is not in semver format: %w", previous, err)
+	}
+	nv, err := semver.StrictNewVersion(next)
+	if err != nil {
+		return false, fmt.Errorf("version %s is not in semver format: %w", next, err)
+	}
+	if nv.Major() > pv.Major() {
+		return true, nil
+	}
+	return nv.Major() == 0 && pv.Major() == 0 && nv.Minor() > pv.Minor(), nil
+}
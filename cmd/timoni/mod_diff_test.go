@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/stefanprodan/timoni/internal/engine"
+)
+
+// copyModuleWithConfig copies the test module and applies the replacements
+// to its #Config definition.
+func copyModuleWithConfig(t *testing.T, replacements ...string) string {
+	g := NewWithT(t)
+	modPath := filepath.Join(t.TempDir(), "module")
+	g.Expect(engine.CopyDir("testdata/module", modPath, true)).To(Succeed())
+
+	configPath := filepath.Join(modPath, "templates", "config.cue")
+	config, err := os.ReadFile(configPath)
+	g.Expect(err).ToNot(HaveOccurred())
+	config = []byte(strings.NewReplacer(replacements...).Replace(string(config)))
+	g.Expect(os.WriteFile(configPath, config, os.ModePerm)).To(Succeed())
+	return modPath
+}
+
+func Test_DiffMod(t *testing.T) {
+	modPath := "testdata/module"
+
+	t.Run("reports no changes", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf("mod diff %s %s -o json", modPath, modPath))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(MatchJSON("[]"))
+	})
+
+	t.Run("reports non-breaking changes", func(t *testing.T) {
+		g := NewWithT(t)
+		newPath := copyModuleWithConfig(t,
+			`logLevel?: *"info" | "debug" | "info"`, `logLevel?: *"info" | "debug" | "info" | "warn"`,
+			`enabled: *false | bool`, `enabled: *true | bool`,
+		)
+
+		output, err := executeCommand(fmt.Sprintf("mod diff %s %s -o yaml", modPath, newPath))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("kind: type-widened"))
+		g.Expect(output).To(ContainSubstring("kind: default-changed"))
+		g.Expect(output).To(ContainSubstring("subject: Namespace/module-name-ns"))
+		g.Expect(output).ToNot(ContainSubstring("breaking: true"))
+	})
+
+	t.Run("fails for breaking changes", func(t *testing.T) {
+		g := NewWithT(t)
+		newPath := copyModuleWithConfig(t,
+			`priority: (*1 | int) & >=0`, `priority: (*1 | int) & >=1`,
+		)
+
+		output, err := executeCommand(fmt.Sprintf("mod diff %s %s -o json", modPath, newPath))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("found 1 breaking change(s)"))
+		g.Expect(output).To(ContainSubstring(`"kind": "type-narrowed"`))
+		g.Expect(output).To(ContainSubstring(`"subject": "priority:"`))
+	})
+}
+
+func Test_DiffModSplitSource(t *testing.T) {
+	tests := []struct {
+		src     string
+		source  string
+		version string
+	}{
+		{"./module", "./module", ""},
+		{"oci://ghcr.io/org/app", "oci://ghcr.io/org/app", "latest"},
+		{"oci://ghcr.io/org/app:1.2.0", "oci://ghcr.io/org/app", "1.2.0"},
+		{"oci://localhost:5000/org/app", "oci://localhost:5000/org/app", "latest"},
+		{"oci://localhost:5000/org/app:1.2.0", "oci://localhost:5000/org/app", "1.2.0"},
+		{"oci://ghcr.io/org/app@sha256:abc", "oci://ghcr.io/org/app", "@sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			g := NewWithT(t)
+			source, version := splitModuleSource(tt.src)
+			g.Expect(source).To(Equal(tt.source))
+			g.Expect(version).To(Equal(tt.version))
+		})
+	}
+}
+
+func Test_DiffModMajorRelease(t *testing.T) {
+	tests := []struct {
+		previous string
+		next     string
+		major    bool
+	}{
+		{"1.2.0", "2.0.0", true},
+		{"1.2.0", "1.3.0", false},
+		{"1.2.0", "1.2.1", false},
+		{"0.2.0", "0.3.0", true},
+		{"0.2.0", "0.2.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.previous+"-"+tt.next, func(t *testing.T) {
+			g := NewWithT(t)
+			major, err := isMajorRelease(tt.previous, tt.next)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(major).To(Equal(tt.major))
+		})
+	}
+}
+
+func Test_PushModCheckCompat(t *testing.T) {
+	modPath := "testdata/module"
+	modURL := fmt.Sprintf("oci://%s/%s", dockerRegistry, rnd("my-mod"))
+
+	g := NewWithT(t)
+	_, err := executeCommand(fmt.Sprintf("mod push %s %s -v 1.0.0 --resolve-symlinks", modPath, modURL))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	newPath := copyModuleWithConfig(t,
+		`domain: *"example.internal" | string`, `domain: *"example.internal" | string & =~"^[a-z.]+$"`,
+	)
+
+	_, err = executeCommand(fmt.Sprintf("mod push %s %s -v 1.1.0 --check-compat 1.0.0", newPath, modURL))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("version 1.1.0 contains 1 breaking change(s) since 1.0.0"))
+
+	_, err = executeCommand(fmt.Sprintf("mod push %s %s -v 2.0.0 --check-compat %s:1.0.0", newPath, modURL, modURL))
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/cuecontext"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/oci"
+)
+
+var importYAMLModCmd = &cobra.Command{
+	Use:   "yaml MODULE_NAME [PATH]",
+	Args:  cobra.MaximumNArgs(2),
+	Short: "Generate a module from Kubernetes YAML manifests",
+	Long: `The import yaml command converts Kubernetes YAML manifests into a module
+scaffold based on the starter blueprint. Each object is converted to a CUE template
+typed against the Kubernetes schemas vendored in the module, with its name and
+namespace set from the instance metadata.
+
+The container images, the resource requirements and the number of replicas of
+the workloads are extracted into #Config, with the values found in the manifests
+as defaults. If the objects use Kubernetes APIs that are not vendored in the
+blueprint, the schemas are vendored for the specified Kubernetes version.`,
+	Example: `  # Generate a module in the current directory from a directory of manifests
+  timoni mod import yaml my-app -f ./manifests/
+
+  # Generate a module at the specified path from multiple files
+  timoni mod import yaml my-app ./modules \
+  -f deployment.yaml \
+  -f service.yaml
+`,
+	RunE: runImportYAMLModCmd,
+}
+
+type importYAMLModFlags struct {
+	name         string
+	path         string
+	files        []string
+	blueprintURL string
+	kubeVersion  string
+}
+
+var importYAMLModArgs = importYAMLModFlags{
+	blueprintURL: modBlueprintURL,
+	kubeVersion:  "latest",
+}
+
+func init() {
+	importYAMLModCmd.Flags().StringSliceVarP(&importYAMLModArgs.files, "file", "f", nil,
+		"The local path to the YAML manifests, can be a file or a directory (can be specified multiple times).")
+	importYAMLModCmd.Flags().StringVarP(&importYAMLModArgs.blueprintURL, "blueprint", "b", modBlueprintURL,
+		"The blueprint OCI URL or local directory the module is generated from.")
+	importYAMLModCmd.Flags().StringVar(&importYAMLModArgs.kubeVersion, "kube-version", "latest",
+		"The Kubernetes minor version of the schemas vendored for the APIs missing from the blueprint e.g. 1.28.")
+	modImportCmd.AddCommand(importYAMLModCmd)
+}
+
+const modBlueprintURL = "oci://ghcr.io/stefanprodan/timoni/blueprints/starter"
+
+func runImportYAMLModCmd(cmd *cobra.Command, args []string) (err error) {
+	if len(args) < 1 {
+		return errors.New("module name is required")
+	}
+	importYAMLModArgs.name = args[0]
+
+	if len(args) == 2 {
+		importYAMLModArgs.path = args[1]
+	} else {
+		importYAMLModArgs.path = "."
+	}
+
+	if len(importYAMLModArgs.files) == 0 {
+		return errors.New("at least one manifest is required")
+	}
+
+	log := LoggerFrom(cmd.Context())
+
+	if fs, err := os.Stat(importYAMLModArgs.path); err != nil || !fs.IsDir() {
+		return fmt.Errorf("path not found: %s", importYAMLModArgs.path)
+	}
+
+	objects, err := readManifests(importYAMLModArgs.files)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	blueprintDir := filepath.Join(tmpDir, "blueprint")
+	if fs, err := os.Stat(importYAMLModArgs.blueprintURL); err == nil && fs.IsDir() {
+		if err := engine.CopyDir(importYAMLModArgs.blueprintURL, blueprintDir, true); err != nil {
+			return err
+		}
+	} else {
+		spin := logger.StartSpinner(fmt.Sprintf("pulling blueprint from %s", importYAMLModArgs.blueprintURL))
+		opts := oci.Options(ctx, "", rootArgs.registryInsecure)
+		err = oci.PullArtifact(importYAMLModArgs.blueprintURL, blueprintDir, apiv1.AnyContentType, opts)
+		spin.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	dst := filepath.Join(importYAMLModArgs.path, importYAMLModArgs.name)
+	if err := initializeModule(importYAMLModArgs.name, "blueprint", blueprintDir, dst); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(dst)
+		}
+	}()
+
+	// The blueprint templates and values are replaced with the imported ones.
+	if err := os.RemoveAll(filepath.Join(dst, "templates")); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dst, "templates"), 0o755); err != nil {
+		return err
+	}
+	for _, file := range []string{"images.cue", "debug_values.cue"} {
+		if err := os.RemoveAll(filepath.Join(dst, file)); err != nil {
+			return err
+		}
+	}
+
+	cuectx := cuecontext.New()
+	imp := engine.NewManifestImporter(cuectx, dst)
+	if missing := imp.MissingSchemas(objects); len(missing) > 0 {
+		log.Info(fmt.Sprintf("vendoring the schemas of %s", logger.ColorizeSubject(strings.Join(missing, ", "))))
+		if err := vendorK8sSchemas(ctx, filepath.Join(dst, "cue.mod"), importYAMLModArgs.kubeVersion); err != nil {
+			log.Info(logger.ColorizeWarning(fmt.Sprintf("the objects of these APIs are not validated, vendoring failed: %s", err)))
+		}
+	}
+
+	files, err := imp.Generate(objects)
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := os.WriteFile(filepath.Join(dst, name), files[name], 0o644); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("generated %s", logger.ColorizeSubject(name)))
+	}
+
+	// Make sure the generated module builds with the default values.
+	namespace := "default"
+	if ns := objects[0].GetNamespace(); ns != "" {
+		namespace = ns
+	}
+	modRoot, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	builder := engine.NewModuleBuilder(cuectx, importYAMLModArgs.name, namespace, modRoot, "main")
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+	if _, err := builder.Build(); err != nil {
+		return describeErr(dst, "the generated module failed to build", err)
+	}
+
+	log.Info(fmt.Sprintf("module imported at %s", dst))
+	return nil
+}
+
+// readManifests reads the Kubernetes objects from the YAML files.
+// The directories are walked recursively for .yaml and .yml files.
+func readManifests(paths []string) ([]*unstructured.Unstructured, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if ext := filepath.Ext(path); path == p || ext == ".yaml" || ext == ".yml" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading manifests from %s failed: %w", p, err)
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		objs, err := ssautil.ReadObjects(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s failed: %w", file, err)
+		}
+		objects = append(objects, objs...)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no Kubernetes objects found in %v", paths)
+	}
+	return objects, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ImportYAMLMod(t *testing.T) {
+	g := NewWithT(t)
+
+	manifestsDir := t.TempDir()
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: podinfo
+  template:
+    metadata:
+      labels:
+        app: podinfo
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.5.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  selector:
+    app: podinfo
+  ports:
+  - port: 9898
+`
+	g.Expect(os.WriteFile(filepath.Join(manifestsDir, "app.yaml"), []byte(manifests), os.ModePerm)).To(Succeed())
+
+	modPath := t.TempDir()
+	name := "podinfo"
+
+	t.Run("generates the module", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod import yaml %s %s -f %s --blueprint ../../blueprints/starter",
+			name, modPath, manifestsDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, file := range []string{"timoni.cue", "values.cue", "templates/config.cue", "templates/deployment.cue", "templates/service.cue"} {
+			g.Expect(filepath.Join(modPath, name, file)).To(BeAnExistingFile())
+		}
+		g.Expect(filepath.Join(modPath, name, "images.cue")).ToNot(BeAnExistingFile())
+	})
+
+	t.Run("builds the module with the extracted defaults", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"build test %s -n test",
+			filepath.Join(modPath, name),
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(ContainSubstring("replicas: 3"))
+		g.Expect(output).To(ContainSubstring("image: ghcr.io/stefanprodan/podinfo:6.5.0"))
+		g.Expect(output).To(ContainSubstring("namespace: test"))
+		g.Expect(output).To(ContainSubstring("name: test"))
+	})
+
+	t.Run("fails if the module exists", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod import yaml %s %s -f %s --blueprint ../../blueprints/starter",
+			name, modPath, manifestsDir,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("already exists"))
+	})
+
+	t.Run("fails without manifests", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf("mod import yaml %s %s", name, t.TempDir()))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("at least one manifest is required"))
+	})
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	cueyaml "cuelang.org/go/encoding/yaml"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/engine/fetcher"
+	"github.com/stefanprodan/timoni/internal/flags"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+var migrateValuesModCmd = &cobra.Command{
+	Use:   "migrate-values [MODULE PATH | MODULE URL]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Migrate values files written for a previous version of a module",
+	Long: `The migrate-values command runs the migrations declared by the module under
+'timoni: migrations:' on the given values files, and rewrites the files with
+the values expected by the current version of the module. Only the migrations
+matching the version specified with '--from' are applied.
+The module can be a local directory or an OCI artifact.
+
+The values files are rewritten in their original format (CUE, YAML or JSON),
+with the comments removed. SOPS encrypted files are not supported.`,
+	Example: `  # migrate the values written for version 1.x of the module in the current directory
+  timoni mod migrate-values --from 1.4.0 -f values.cue
+
+  # print the migrated values of a module published to a container registry
+  timoni mod migrate-values oci://docker.io/org/app -v 2.0.0 \
+  --from 1.4.0 \
+  -f values-prod.yaml \
+  --dry-run
+`,
+	RunE: runMigrateValuesModCmd,
+}
+
+type migrateValuesModFlags struct {
+	path        string
+	version     flags.Version
+	creds       flags.Credentials
+	pkg         flags.Package
+	from        string
+	valuesFiles []string
+	dryrun      bool
+}
+
+var migrateValuesModArgs migrateValuesModFlags
+
+func init() {
+	migrateValuesModCmd.Flags().VarP(&migrateValuesModArgs.version, migrateValuesModArgs.version.Type(), migrateValuesModArgs.version.Shorthand(), migrateValuesModArgs.version.Description())
+	migrateValuesModCmd.Flags().Var(&migrateValuesModArgs.creds, migrateValuesModArgs.creds.Type(), migrateValuesModArgs.creds.Description())
+	migrateValuesModCmd.Flags().VarP(&migrateValuesModArgs.pkg, migrateValuesModArgs.pkg.Type(), migrateValuesModArgs.pkg.Shorthand(), migrateValuesModArgs.pkg.Description())
+	migrateValuesModCmd.Flags().StringVar(&migrateValuesModArgs.from, "from", "",
+		"The version of the module the values were written for.")
+	migrateValuesModCmd.Flags().StringSliceVarP(&migrateValuesModArgs.valuesFiles, "values", "f", nil,
+		"The local path to the values files to migrate (cue, yaml or json format).")
+	migrateValuesModCmd.Flags().BoolVar(&migrateValuesModArgs.dryrun, "dry-run", false,
+		"Print the migrated values instead of rewriting the files.")
+	modCmd.AddCommand(migrateValuesModCmd)
+}
+
+func runMigrateValuesModCmd(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		migrateValuesModArgs.path = "."
+	} else {
+		migrateValuesModArgs.path = args[0]
+	}
+
+	if migrateValuesModArgs.from == "" {
+		return errors.New("--from is required")
+	}
+	if len(migrateValuesModArgs.valuesFiles) == 0 {
+		return errors.New("at least one values file is required")
+	}
+
+	log := LoggerFrom(cmd.Context())
+
+	version := migrateValuesModArgs.version.String()
+	if version == "" {
+		version = apiv1.LatestVersion
+	}
+
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctxPull, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	f, err := fetcher.New(ctxPull, fetcher.Options{
+		Source:       migrateValuesModArgs.path,
+		Version:      version,
+		Destination:  tmpDir,
+		CacheDir:     rootArgs.cacheDir,
+		Creds:        migrateValuesModArgs.creds.String(),
+		Insecure:     rootArgs.registryInsecure,
+		DefaultLocal: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Fetch(); err != nil {
+		return err
+	}
+
+	cuectx := cuecontext.New()
+	builder := engine.NewModuleBuilder(
+		cuectx,
+		"module-name",
+		*kubeconfigArgs.Namespace,
+		f.GetModuleRoot(),
+		migrateValuesModArgs.pkg.String(),
+	)
+
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+
+	configValue, err := builder.BuildConfig()
+	if err != nil {
+		return describeErr(f.GetModuleRoot(), "build failed", err)
+	}
+
+	migrations, err := builder.GetValuesMigrations(configValue, migrateValuesModArgs.from)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		log.Info(fmt.Sprintf("no migrations found for version %s", migrateValuesModArgs.from))
+		return nil
+	}
+
+	for _, m := range migrations {
+		log.Info(fmt.Sprintf("migrating values: %s", m))
+	}
+
+	for _, path := range migrateValuesModArgs.valuesFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read values file at %s: %w", path, err)
+		}
+		if sops.IsEncrypted(data) {
+			return fmt.Errorf("migrating the encrypted values file %s is not supported", path)
+		}
+
+		valuesCue, err := convertToCue(cmd, []string{path}, nil)
+		if err != nil {
+			return err
+		}
+
+		values, err := engine.NewValuesBuilder(cuectx).MergeOverlays(valuesCue)
+		if err != nil {
+			return err
+		}
+
+		migrated, err := builder.MigrateValues(values, migrations)
+		if err != nil {
+			return fmt.Errorf("migrating %s failed: %w", path, err)
+		}
+
+		out, err := formatValuesFile(cuectx, migrated, filepath.Ext(path))
+		if err != nil {
+			return fmt.Errorf("formatting %s failed: %w", path, err)
+		}
+
+		if migrateValuesModArgs.dryrun {
+			if _, err := cmd.OutOrStdout().Write(out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("values file %s migrated", path))
+	}
+
+	return nil
+}
+
+// formatValuesFile encodes the values under the values key
+// in the format matching the values file extension.
+func formatValuesFile(ctx *cue.Context, values cue.Value, ext string) ([]byte, error) {
+	file := ctx.CompileString("{}").FillPath(cue.ParsePath(apiv1.ValuesSelector.String()), values)
+	if err := file.Err(); err != nil {
+		return nil, err
+	}
+
+	switch ext {
+	case ".cue":
+		node := file.Syntax(cue.Final(), cue.Concrete(true))
+		if st, ok := node.(*ast.StructLit); ok {
+			node = &ast.File{Decls: st.Elts}
+		}
+		return format.Node(node)
+	case ".json":
+		out, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case ".yaml", ".yml":
+		return cueyaml.Encode(file)
+	default:
+		return nil, fmt.Errorf("unknown values file format %s", ext)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/stefanprodan/timoni/internal/engine"
+)
+
+func Test_MigrateValuesMod(t *testing.T) {
+	g := NewWithT(t)
+
+	// Copy the module and declare the migrations from 1.x
+	modPath := filepath.Join(t.TempDir(), "module")
+	g.Expect(engine.CopyDir("testdata/module", modPath, true)).To(Succeed())
+	migrations := `package main
+
+timoni: migrations: [{
+	from:        "<2.0.0"
+	description: "rename owner to team"
+	rename: "owner": "team"
+}, {
+	from:        "<2.0.0"
+	description: "drop the domain field"
+	input: {...}
+	output: {
+		for k, v in input if k != "domain" {(k): v}
+	}
+}]
+`
+	g.Expect(os.WriteFile(filepath.Join(modPath, "migrations.cue"), []byte(migrations), os.ModePerm)).To(Succeed())
+
+	valuesPath := filepath.Join(t.TempDir(), "values.yaml")
+	values := `values:
+  owner: dev
+  domain: example.com
+  client:
+    enabled: false
+`
+	g.Expect(os.WriteFile(valuesPath, []byte(values), os.ModePerm)).To(Succeed())
+
+	t.Run("prints the migrated values", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod migrate-values %s --from 1.2.0 -f %s --dry-run",
+			modPath, valuesPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(ContainSubstring("team: dev"))
+		g.Expect(output).ToNot(ContainSubstring("owner:"))
+		g.Expect(output).ToNot(ContainSubstring("domain:"))
+
+		data, err := os.ReadFile(valuesPath)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(Equal(values))
+	})
+
+	t.Run("skips newer versions", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod migrate-values %s --from 2.0.0 -f %s",
+			modPath, valuesPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(valuesPath)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(Equal(values))
+	})
+
+	t.Run("rewrites the values file", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod migrate-values %s --from 1.2.0 -f %s",
+			modPath, valuesPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = executeCommand(fmt.Sprintf(
+			"build test %s -f %s",
+			modPath, valuesPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		data, err := os.ReadFile(valuesPath)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(ContainSubstring("team: dev"))
+		g.Expect(string(data)).To(ContainSubstring("enabled: false"))
+	})
+
+	t.Run("fails without from version", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod migrate-values %s -f %s",
+			modPath, valuesPath,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("--from is required"))
+	})
+}
@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 
@@ -84,6 +86,11 @@ extracting the version from the archive manifest.`,
 
   # Push a pre-built module OCI archive produced by 'timoni mod build'
   timoni mod push ./module-1.0.0.oci.tar oci://docker.io/org/app-module
+
+  # Refuse to push a minor release that contains breaking changes
+  timoni mod push ./path/to/module oci://docker.io/org/app-module \
+	--version=1.3.0 \
+	--check-compat=1.2.0
 `,
 	RunE: pushModCmdRun,
 }
@@ -99,6 +106,7 @@ type pushModFlags struct {
 	resolveSymlinks bool
 	sign            string
 	cosignKey       string
+	checkCompat     string
 }
 
 var pushModArgs pushModFlags
@@ -118,6 +126,8 @@ func init() {
 		"Signs the module with the specified provider.")
 	pushModCmd.Flags().StringVar(&pushModArgs.cosignKey, "cosign-key", "",
 		"The Cosign private key for signing the module.")
+	pushModCmd.Flags().StringVar(&pushModArgs.checkCompat, "check-compat", "",
+		"Compare the module with the previous version, given as a version of the same repository or as an OCI URL, and refuse to push a minor or patch release that contains breaking changes.")
 
 	modCmd.AddCommand(pushModCmd)
 }
@@ -162,6 +172,9 @@ func pushModCmdRun(cmd *cobra.Command, args []string) error {
 				return err
 			}
 		}
+		if pushModArgs.checkCompat != "" {
+			return fmt.Errorf("--check-compat is not supported when pushing a pre-built archive")
+		}
 	} else {
 		if err := flags.ValidateModuleVersion(version); err != nil {
 			return err
@@ -172,6 +185,12 @@ func pushModCmdRun(cmd *cobra.Command, args []string) error {
 
 	log := LoggerFrom(cmd.Context())
 
+	if pushModArgs.checkCompat != "" {
+		if err := checkModuleCompat(cmd, args[1], version); err != nil {
+			return err
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 	defer cancel()
 
@@ -198,6 +217,9 @@ func pushModCmdRun(cmd *cobra.Command, args []string) error {
 		if err := appendImagesAnnotation(pushModArgs.module, annotations); err != nil {
 			return err
 		}
+		if err := appendConfigSchemaAnnotation(pushModArgs.module, annotations); err != nil {
+			log.Info(logger.ColorizeWarning(fmt.Sprintf("config schema annotation skipped: %s", err)))
+		}
 
 		ps, err := engine.ReadIgnoreFile(pushModArgs.module)
 		if err != nil {
@@ -289,3 +311,43 @@ func pushModCmdRun(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// checkModuleCompat compares the module with the previous version set with
+// --check-compat and returns an error if the version is not a major release
+// and the module contains breaking changes.
+func checkModuleCompat(cmd *cobra.Command, url, version string) error {
+	log := LoggerFrom(cmd.Context())
+
+	previous := pushModArgs.checkCompat
+	if !strings.HasPrefix(previous, apiv1.ArtifactPrefix) {
+		if _, err := semver.StrictNewVersion(previous); err != nil {
+			return fmt.Errorf("--check-compat must be a semver version or an OCI URL: %w", err)
+		}
+		previous = fmt.Sprintf("%s:%s", url, previous)
+	}
+
+	var pkg flags.Package
+	previousMod, changes, err := diffModules(cmd, previous, pushModArgs.module, pushModArgs.creds.String(), pkg.String())
+	if err != nil {
+		return fmt.Errorf("compatibility check failed: %w", err)
+	}
+
+	breaking := countBreakingChanges(changes)
+	if breaking == 0 {
+		log.Info(fmt.Sprintf("no breaking changes found since %s", previousMod.Version))
+		return nil
+	}
+
+	major, err := isMajorRelease(previousMod.Version, version)
+	if err != nil {
+		return fmt.Errorf("compatibility check failed: %w", err)
+	}
+	if major {
+		log.Info(logger.ColorizeWarning(fmt.Sprintf("found %d breaking change(s) since %s", breaking, previousMod.Version)))
+		return nil
+	}
+
+	logModuleChanges(log, changes)
+	return fmt.Errorf("version %s contains %d breaking change(s) since %s, a major release is required",
+		version, breaking, previousMod.Version)
+}
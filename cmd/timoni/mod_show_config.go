@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -50,10 +51,15 @@ var configShowModCmd = &cobra.Command{
 	Short: "Output the #Config schema of a module",
 	Long: `The config command prints the module's #Config schema as CUE, with the
 field documentation, default values and constraints, marking optional fields
-with ? and required fields with !. The module can be a local directory or
-an OCI artifact.
-
-With --output, the schema is written as a Markdown table to the given file.
+with ? and required fields with !. The module requirements, such as the
+supported Kubernetes versions, are printed after the schema. The module
+can be a local directory or an OCI artifact.
+
+With --output jsonschema, the schema is printed as a JSON Schema (draft
+2020-12) of the values files, which editors and other tools can use to
+validate values without CUE. With --output openapi, the schema is printed
+as an OpenAPI 3.0 document under components.schemas.Values. Any other
+--output value is the file the schema is written to as a Markdown table.
 If the file is Markdown, the table replaces the first table found under
 the Configuration section, or is appended to the end of the file.`,
 	Example: `  # print the config schema of a module in the current directory
@@ -64,6 +70,12 @@ the Configuration section, or is appended to the end of the file.`,
 
   # write the config table to the module README
   timoni mod show config --output ./README.md
+
+  # write the config JSON Schema for validating values files in editors
+  timoni mod show config --output jsonschema > values.schema.json
+
+  # print the config schema of a published module as an OpenAPI document
+  timoni mod show config oci://docker.io/org/app -v 1.0.0 -o openapi
 `,
 	RunE: runConfigShowModCmd,
 }
@@ -84,7 +96,8 @@ var configShowModArgs = configModFlags{
 func init() {
 	configShowModCmd.Flags().VarP(&configShowModArgs.version, configShowModArgs.version.Type(), configShowModArgs.version.Shorthand(), configShowModArgs.version.Description())
 	configShowModCmd.Flags().Var(&configShowModArgs.creds, configShowModArgs.creds.Type(), configShowModArgs.creds.Description())
-	configShowModCmd.Flags().StringVarP(&configShowModArgs.output, "output", "o", "", "The file to output the config Markdown to, defaults to stdout")
+	configShowModCmd.Flags().StringVarP(&configShowModArgs.output, "output", "o", "",
+		"The schema format, can be 'jsonschema' or 'openapi', or the file to output the config Markdown to, defaults to CUE on stdout.")
 	showModCmd.AddCommand(configShowModCmd)
 }
 
@@ -100,7 +113,8 @@ func runConfigShowModCmd(cmd *cobra.Command, args []string) error {
 		version = apiv1.LatestVersion
 	}
 
-	if configShowModArgs.output != "" && strings.HasPrefix(configShowModArgs.path, apiv1.ArtifactPrefix) {
+	schemaOutput := configShowModArgs.output == "jsonschema" || configShowModArgs.output == "openapi"
+	if configShowModArgs.output != "" && !schemaOutput && strings.HasPrefix(configShowModArgs.path, apiv1.ArtifactPrefix) {
 		return fmt.Errorf("--output is not supported for OCI modules, the README is published with the artifact")
 	}
 
@@ -155,16 +169,45 @@ func runConfigShowModCmd(cmd *cobra.Command, args []string) error {
 		return describeErr(f.GetModuleRoot(), "validation failed", err)
 	}
 
+	if schemaOutput {
+		schema, err := builder.GetConfigSchema(buildResult)
+		if err != nil {
+			return describeErr(f.GetModuleRoot(), "failed to get config schema", err)
+		}
+		doc := engine.ConfigJSONSchema(schema, mod.Name)
+		if configShowModArgs.output == "openapi" {
+			doc = engine.ConfigOpenAPI(schema, mod.Name, mod.Version)
+		}
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("config schema JSON conversion failed: %w", err)
+		}
+		_, err = fmt.Fprintln(rootCmd.OutOrStdout(), string(out))
+		return err
+	}
+
 	fields, err := builder.GetConfigDoc(buildResult)
 	if err != nil {
 		return describeErr(f.GetModuleRoot(), "failed to get config structure", err)
 	}
 
+	requirements, err := builder.GetRequirements(buildResult)
+	if err != nil {
+		return describeErr(f.GetModuleRoot(), "failed to get requirements", err)
+	}
+
 	if configShowModArgs.output == "" {
 		out, err := engine.FormatConfigCUE(fields)
 		if err != nil {
 			return describeErr(f.GetModuleRoot(), "failed to format config", err)
 		}
+		if requirements != nil {
+			req, err := engine.FormatRequirementsCUE(cuectx, requirements)
+			if err != nil {
+				return err
+			}
+			out += "\n" + req
+		}
 		_, err = fmt.Fprint(rootCmd.OutOrStdout(), out)
 		return err
 	}
@@ -349,3 +392,52 @@ func isMarkdownFile(filename string) bool {
 	extension := strings.ToLower(filepath.Ext(filename))
 	return extension == ".md" || extension == ".markdown"
 }
+
+// appendConfigSchemaAnnotation records the JSON Schema of the module's
+// #Config under the sh.timoni.config.schema annotation, so that values
+// files can be validated without CUE. An annotation set explicitly by
+// the user is preserved.
+func appendConfigSchemaAnnotation(modulePath string, annotations map[string]string) error {
+	if _, ok := annotations[apiv1.ConfigSchemaAnnotation]; ok {
+		return nil
+	}
+
+	moduleRoot, err := filepath.Abs(modulePath)
+	if err != nil {
+		return err
+	}
+
+	// Modules are published with their templates in the default package.
+	var pkg flags.Package
+	builder := engine.NewModuleBuilder(
+		cuecontext.New(),
+		"module-name",
+		*kubeconfigArgs.Namespace,
+		moduleRoot,
+		pkg.String(),
+	)
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+
+	name, err := builder.GetModuleName()
+	if err != nil {
+		return err
+	}
+
+	buildResult, err := builder.Build()
+	if err != nil {
+		return describeErr(moduleRoot, "validation failed", err)
+	}
+
+	schema, err := builder.GetConfigSchema(buildResult)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(engine.ConfigJSONSchema(schema, name))
+	if err != nil {
+		return err
+	}
+	annotations[apiv1.ConfigSchemaAnnotation] = string(data)
+	return nil
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -25,6 +26,8 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 )
 
 func Test_ShowConfig(t *testing.T) {
@@ -172,3 +175,61 @@ func Test_ShowConfigOCI(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("not supported for OCI modules"))
 }
+
+func Test_ShowConfigSchema(t *testing.T) {
+	g := NewWithT(t)
+
+	output, err := executeCommand("mod show config testdata/module -o jsonschema")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var schema map[string]any
+	g.Expect(json.Unmarshal([]byte(output), &schema)).To(Succeed())
+	g.Expect(schema).To(HaveKeyWithValue("$schema", "https://json-schema.org/draft/2020-12/schema"))
+	g.Expect(schema).To(HaveKeyWithValue("title", "timoni.sh/test"))
+	values := schema["properties"].(map[string]any)["values"].(map[string]any)
+	g.Expect(values).To(HaveKeyWithValue("additionalProperties", false))
+	properties := values["properties"].(map[string]any)
+	g.Expect(properties).To(HaveKeyWithValue("logLevel", map[string]any{
+		"type":        "string",
+		"enum":        []any{"debug", "info"},
+		"default":     "info",
+		"description": "Log level, info by default",
+	}))
+	g.Expect(properties).ToNot(HaveKey("kubeVersion"))
+
+	output, err = executeCommand("mod show config testdata/module -o openapi")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var doc map[string]any
+	g.Expect(json.Unmarshal([]byte(output), &doc)).To(Succeed())
+	g.Expect(doc).To(HaveKeyWithValue("openapi", "3.0.3"))
+	g.Expect(doc).To(HaveKeyWithValue("info", map[string]any{
+		"title":   "timoni.sh/test",
+		"version": "0.0.0-devel",
+	}))
+	g.Expect(doc["components"]).To(HaveKeyWithValue("schemas", HaveKey("Values")))
+}
+
+func Test_AppendConfigSchemaAnnotation(t *testing.T) {
+	g := NewWithT(t)
+
+	// The schema is recorded when the annotation is not set.
+	annotations := map[string]string{}
+	g.Expect(appendConfigSchemaAnnotation("testdata/module", annotations)).To(Succeed())
+	g.Expect(annotations).To(HaveKey(apiv1.ConfigSchemaAnnotation))
+
+	var schema map[string]any
+	g.Expect(json.Unmarshal([]byte(annotations[apiv1.ConfigSchemaAnnotation]), &schema)).To(Succeed())
+	g.Expect(schema).To(HaveKeyWithValue("title", "timoni.sh/test"))
+	g.Expect(schema["properties"]).To(HaveKeyWithValue("values", HaveKeyWithValue("properties", HaveKey("team"))))
+
+	// An annotation set by the user is preserved.
+	annotations = map[string]string{apiv1.ConfigSchemaAnnotation: "{}"}
+	g.Expect(appendConfigSchemaAnnotation("testdata/module", annotations)).To(Succeed())
+	g.Expect(annotations).To(HaveKeyWithValue(apiv1.ConfigSchemaAnnotation, "{}"))
+
+	// A module that fails to build carries no annotation.
+	annotations = map[string]string{}
+	g.Expect(appendConfigSchemaAnnotation(t.TempDir(), annotations)).ToNot(Succeed())
+	g.Expect(annotations).ToNot(HaveKey(apiv1.ConfigSchemaAnnotation))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/engine/fetcher"
+	"github.com/stefanprodan/timoni/internal/flags"
+)
+
+var valuesTemplateShowModCmd = &cobra.Command{
+	Use:   "values-template [MODULE PATH | MODULE URL]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Output a values file skeleton generated from the #Config schema of a module",
+	Long: `The values-template command prints a ready-to-edit values file generated
+from the module's #Config schema. The required fields are set to a placeholder
+that fails validation until it is replaced, the fields with a default are set
+to it, and the optional fields are commented out with their documentation.
+The module can be a local directory or an OCI artifact.
+
+The values file can be printed in the CUE, YAML or JSON format. Since JSON has
+no comments, the optional fields are left out of the JSON output.`,
+	Example: `  # print a values file for a module in the current directory
+  timoni mod show values-template
+
+  # write a YAML values file for a module published to a container registry
+  timoni mod show values-template oci://docker.io/org/app -v 1.0.0 -o yaml > values.yaml
+
+  # print only the fields that must be set
+  timoni mod show values-template oci://docker.io/org/app --only-required
+`,
+	RunE: runValuesTemplateShowModCmd,
+}
+
+type valuesTemplateModFlags struct {
+	path         string
+	version      flags.Version
+	creds        flags.Credentials
+	pkg          flags.Package
+	output       string
+	onlyRequired bool
+}
+
+var valuesTemplateShowModArgs = valuesTemplateModFlags{
+	output: "cue",
+}
+
+func init() {
+	valuesTemplateShowModCmd.Flags().VarP(&valuesTemplateShowModArgs.version, valuesTemplateShowModArgs.version.Type(), valuesTemplateShowModArgs.version.Shorthand(), valuesTemplateShowModArgs.version.Description())
+	valuesTemplateShowModCmd.Flags().Var(&valuesTemplateShowModArgs.creds, valuesTemplateShowModArgs.creds.Type(), valuesTemplateShowModArgs.creds.Description())
+	valuesTemplateShowModCmd.Flags().StringVarP(&valuesTemplateShowModArgs.output, "output", "o", "cue",
+		"The format of the values file, can be 'cue', 'yaml' or 'json'.")
+	valuesTemplateShowModCmd.Flags().BoolVar(&valuesTemplateShowModArgs.onlyRequired, "only-required", false,
+		"Output only the fields that must be set in the values.")
+	showModCmd.AddCommand(valuesTemplateShowModCmd)
+}
+
+func runValuesTemplateShowModCmd(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		valuesTemplateShowModArgs.path = "."
+	} else {
+		valuesTemplateShowModArgs.path = args[0]
+	}
+
+	if !slices.Contains(engine.ValuesTemplateFormats, valuesTemplateShowModArgs.output) {
+		return fmt.Errorf("unknown --output=%s, can be %s",
+			valuesTemplateShowModArgs.output, strings.Join(engine.ValuesTemplateFormats, ", "))
+	}
+
+	version := valuesTemplateShowModArgs.version.String()
+	if version == "" {
+		version = apiv1.LatestVersion
+	}
+
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctxPull, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	f, err := fetcher.New(ctxPull, fetcher.Options{
+		Source:       valuesTemplateShowModArgs.path,
+		Version:      version,
+		Destination:  tmpDir,
+		CacheDir:     rootArgs.cacheDir,
+		Creds:        valuesTemplateShowModArgs.creds.String(),
+		Insecure:     rootArgs.registryInsecure,
+		DefaultLocal: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Fetch(); err != nil {
+		return err
+	}
+
+	builder := engine.NewModuleBuilder(
+		cuecontext.New(),
+		"module-name",
+		*kubeconfigArgs.Namespace,
+		f.GetModuleRoot(),
+		valuesTemplateShowModArgs.pkg.String(),
+	)
+
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+
+	// The required fields are not set yet, so the instance is not validated.
+	buildResult, err := builder.BuildConfig()
+	if err != nil {
+		return describeErr(f.GetModuleRoot(), "build failed", err)
+	}
+
+	fields, err := builder.GetConfigDoc(buildResult)
+	if err != nil {
+		return describeErr(f.GetModuleRoot(), "failed to get config structure", err)
+	}
+
+	out, err := engine.FormatValuesTemplate(fields, valuesTemplateShowModArgs.output, valuesTemplateShowModArgs.onlyRequired)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(rootCmd.OutOrStdout(), out)
+	return err
+}
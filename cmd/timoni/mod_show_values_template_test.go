@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/stefanprodan/timoni/internal/engine"
+)
+
+func Test_ShowValuesTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	// Copy the module and unset the required team field
+	modPath := filepath.Join(t.TempDir(), "module")
+	g.Expect(engine.CopyDir("testdata/module", modPath, true)).To(Succeed())
+	valuesPath := filepath.Join(modPath, "values.cue")
+	values, err := os.ReadFile(valuesPath)
+	g.Expect(err).ToNot(HaveOccurred())
+	values = []byte(strings.Replace(string(values), "team: \"test\"", "", 1))
+	g.Expect(os.WriteFile(valuesPath, values, os.ModePerm)).To(Succeed())
+
+	t.Run("generates CUE by default", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf("mod show values-template %s", modPath))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(HavePrefix("values: {"))
+		g.Expect(output).To(ContainSubstring("team: string // required"))
+		g.Expect(output).To(ContainSubstring("// logLevel: \"info\""))
+	})
+
+	t.Run("generates YAML with required fields only", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod show values-template %s -o yaml --only-required",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(HavePrefix("values:"))
+		g.Expect(output).To(ContainSubstring("team: null # required, string"))
+		g.Expect(output).ToNot(ContainSubstring("client"))
+	})
+
+	t.Run("generates JSON", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod show values-template %s -o json",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var doc map[string]map[string]any
+		g.Expect(json.Unmarshal([]byte(output), &doc)).To(Succeed())
+		g.Expect(doc["values"]).To(HaveKeyWithValue("team", BeNil()))
+	})
+
+	t.Run("fails for unknown format", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod show values-template %s -o toml",
+			modPath,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("unknown --output=toml"))
+	})
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/engine/fetcher"
+	"github.com/stefanprodan/timoni/internal/flags"
+	"github.com/stefanprodan/timoni/internal/logger"
+)
+
+var testModCmd = &cobra.Command{
+	Use:   "test [MODULE PATH]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Run the test cases of a local module",
+	Long: `The test command runs the test cases found in the module's tests directory.
+Each CUE file in the tests directory holds a test case with a values overlay,
+and the assertions over the rendered objects, the expected build error or the
+expected health check results. Every test case is built in isolation and the
+test cases run concurrently.
+
+With --snapshot, the objects rendered for each test case are compared with
+the YAML snapshot stored in the tests/__snapshots__ directory, and the
+differences are reported on mismatch. With --update, the snapshots are
+rewritten instead.`,
+	Example: `  # run the tests of the module in the current directory
+  timoni mod test
+
+  # run the tests and write a JUnit report
+  timoni mod test ./path/to/module -o junit > report.xml
+
+  # run the tests and print the results in the TAP format
+  timoni mod test ./path/to/module -o tap
+
+  # run the tests and compare the rendered objects with the snapshots
+  timoni mod test ./path/to/module --snapshot
+
+  # rewrite the snapshots after changing the templates
+  timoni mod test ./path/to/module --snapshot --update
+`,
+	RunE: runTestModCmd,
+}
+
+type testModFlags struct {
+	path        string
+	pkg         flags.Package
+	name        string
+	output      string
+	concurrency int
+	snapshot    bool
+	update      bool
+}
+
+var testModArgs testModFlags
+
+func init() {
+	testModCmd.Flags().StringVar(&testModArgs.name, "name", "default", "Name of the instance used to build the module")
+	testModCmd.Flags().VarP(&testModArgs.pkg, testModArgs.pkg.Type(), testModArgs.pkg.Shorthand(), testModArgs.pkg.Description())
+	testModCmd.Flags().StringVarP(&testModArgs.output, "output", "o", "",
+		"The format of the test report, can be 'junit' or 'tap'. By default, the results are logged.")
+	testModCmd.Flags().IntVar(&testModArgs.concurrency, "concurrency", 0,
+		"The number of test cases to run concurrently, defaults to the number of CPU cores capped at 8.")
+	testModCmd.Flags().BoolVar(&testModArgs.snapshot, "snapshot", false,
+		"Compare the rendered objects of each test case with its snapshot stored in the tests/__snapshots__ directory.")
+	testModCmd.Flags().BoolVar(&testModArgs.update, "update", false,
+		"Rewrite the snapshots with the rendered objects, implies --snapshot.")
+	modCmd.AddCommand(testModCmd)
+}
+
+func runTestModCmd(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		testModArgs.path = "."
+	} else {
+		testModArgs.path = args[0]
+	}
+
+	switch testModArgs.output {
+	case "", "junit", "tap":
+	default:
+		return fmt.Errorf("unknown --output=%s, can be junit or tap", testModArgs.output)
+	}
+
+	if fs, err := os.Stat(testModArgs.path); err != nil || !fs.IsDir() {
+		return fmt.Errorf("module not found at path %s", testModArgs.path)
+	}
+
+	testsDir := filepath.Join(testModArgs.path, apiv1.TestsDir)
+	tests, err := engine.FindModuleTests(testsDir)
+	if err != nil {
+		return err
+	}
+	if len(tests) == 0 {
+		return fmt.Errorf("no test cases found in %s", testsDir)
+	}
+
+	log := LoggerFrom(cmd.Context())
+
+	tmpDir, err := os.MkdirTemp("", apiv1.FieldManager)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	ctxPull, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	f, err := fetcher.New(ctxPull, fetcher.Options{
+		Source:       testModArgs.path,
+		Version:      apiv1.LatestVersion,
+		Destination:  tmpDir,
+		CacheDir:     rootArgs.cacheDir,
+		Insecure:     rootArgs.registryInsecure,
+		DefaultLocal: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Fetch(); err != nil {
+		return err
+	}
+
+	builder := engine.NewModuleBuilder(
+		cuecontext.New(),
+		testModArgs.name,
+		*kubeconfigArgs.Namespace,
+		f.GetModuleRoot(),
+		testModArgs.pkg.String(),
+	)
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return err
+	}
+	modName, err := builder.GetModuleName()
+	if err != nil {
+		return fmt.Errorf("build failed: %w", err)
+	}
+
+	tester := engine.NewModuleTester(
+		testModArgs.name,
+		*kubeconfigArgs.Namespace,
+		f.GetModuleRoot(),
+		testModArgs.pkg.String(),
+	)
+	if testModArgs.snapshot || testModArgs.update {
+		tester.SetSnapshots(filepath.Join(testsDir, apiv1.TestSnapshotsDir), testModArgs.update)
+	}
+
+	results := make([]engine.ModuleTestResult, len(tests))
+	var eg errgroup.Group
+	eg.SetLimit(testConcurrency())
+	for i, test := range tests {
+		eg.Go(func() error {
+			results[i] = tester.Run(test)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	var failed int
+	for _, result := range results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+
+	switch testModArgs.output {
+	case "junit":
+		if err := engine.WriteJUnitReport(cmd.OutOrStdout(), modName, results); err != nil {
+			return err
+		}
+	case "tap":
+		if err := engine.WriteTAPReport(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+	default:
+		for _, result := range results {
+			if result.Passed() {
+				log.Info(fmt.Sprintf("%s %s",
+					logger.ColorizeSubject(result.Name), logger.ColorizeInfo("passed")))
+				continue
+			}
+			log.Error(nil, fmt.Sprintf("%s %s\n%s",
+				logger.ColorizeSubject(result.Name), "failed:", strings.Join(result.Failures, "\n")))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d test cases failed", failed, len(results))
+	}
+
+	if testModArgs.output == "" {
+		log.Info(fmt.Sprintf("%s %s",
+			logger.ColorizeSubject(modName), logger.ColorizeInfo(fmt.Sprintf("passed %d test cases", len(results)))))
+	}
+	return nil
+}
+
+// testConcurrency returns the number of test cases to run concurrently,
+// as every in-flight test case holds its own CUE evaluation context.
+func testConcurrency() int {
+	if testModArgs.concurrency > 0 {
+		return testModArgs.concurrency
+	}
+	return min(goruntime.NumCPU(), 8)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/stefanprodan/timoni/internal/fscopy"
+)
+
+func TestModTest(t *testing.T) {
+	// The module is shared with the engine tests.
+	modPath := "../../internal/engine/testdata/module-tests"
+
+	t.Run("runs the module tests", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod test %s -p main",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("defaults passed"))
+		g.Expect(output).To(ContainSubstring("invalid-replicas passed"))
+		g.Expect(output).To(ContainSubstring("timoni.sh/test-tests passed 4 test cases"))
+	})
+
+	t.Run("writes the TAP report", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod test %s -p main -o tap",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("1..4"))
+		g.Expect(output).To(ContainSubstring("ok 4 - replicas"))
+	})
+
+	t.Run("fails on unmet assertions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tmpDir := t.TempDir()
+		g.Expect(fscopy.CopyDir(modPath, tmpDir, fscopy.Options{})).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(tmpDir, "tests", "message.cue"), []byte(`
+values: message: "hi"
+assert: "sets the message": apply.app[0].data.message == "hello"
+`), 0o600)).To(Succeed())
+
+		output, err := executeCommand(fmt.Sprintf(
+			"mod test %s -p main -o junit",
+			tmpDir,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("1 of 5 test cases failed"))
+		g.Expect(output).To(ContainSubstring(`<testsuite name="timoni.sh/test-tests" tests="5" failures="1"`))
+		g.Expect(output).To(ContainSubstring(`assertion &#34;sets the message&#34; failed`))
+	})
+
+	t.Run("compares the snapshots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tmpDir := t.TempDir()
+		g.Expect(fscopy.CopyDir(modPath, tmpDir, fscopy.Options{})).To(Succeed())
+
+		_, err := executeCommand(fmt.Sprintf(
+			"mod test %s -p main --snapshot",
+			tmpDir,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("3 of 4 test cases failed"))
+
+		_, err = executeCommand(fmt.Sprintf(
+			"mod test %s -p main --update",
+			tmpDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		snapshotFile := filepath.Join(tmpDir, "tests", "__snapshots__", "defaults.yaml")
+		g.Expect(snapshotFile).To(BeARegularFile())
+		data, err := os.ReadFile(snapshotFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(os.WriteFile(snapshotFile, bytes.Replace(data, []byte("message: hello"), []byte("message: hey"), 1), 0o644)).To(Succeed())
+
+		output, err := executeCommand(fmt.Sprintf(
+			"mod test %s -p main --snapshot",
+			tmpDir,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("defaults.yaml does not match"))
+		g.Expect(output).To(ContainSubstring("data.message"))
+	})
+
+	t.Run("fails without test cases", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand("mod test testdata/module -p main")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("no test cases found"))
+	})
+}
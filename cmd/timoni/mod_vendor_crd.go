@@ -99,7 +99,7 @@ func runVendorCrdCmd(cmd *cobra.Command, args []string) error {
 		hctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 		defer cancel()
 
-		crdData, err = readRemoteCRDManifest(hctx, cleanhttp.DefaultClient(), vendorCrdArgs.crdFile,
+		crdData, err = readRemoteFile(hctx, cleanhttp.DefaultClient(), vendorCrdArgs.crdFile,
 			maxRemoteCRDManifestSize)
 		if err != nil {
 			return err
@@ -168,8 +168,20 @@ func runVendorCrdCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// readRemoteCRDManifest downloads a response within the size limit without redirecting to HTTP.
-func readRemoteCRDManifest(ctx context.Context, client *http.Client, url string, maxSize int64) ([]byte, error) {
+// remoteStatusError is returned by readRemoteFile for the responses
+// with a status other than OK.
+type remoteStatusError struct {
+	url    string
+	status string
+	code   int
+}
+
+func (e *remoteStatusError) Error() string {
+	return fmt.Sprintf("failed to download %s, status: %s", e.url, e.status)
+}
+
+// readRemoteFile downloads a response within the size limit without redirecting to HTTP.
+func readRemoteFile(ctx context.Context, client *http.Client, url string, maxSize int64) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request for %s, error: %w", url, err)
@@ -192,23 +204,23 @@ func readRemoteCRDManifest(ctx context.Context, client *http.Client, url string,
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download manifest from %s, error: %w", url, err)
+		return nil, fmt.Errorf("failed to download %s, error: %w", url, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download manifest from %s, status: %s", url, resp.Status)
+		return nil, &remoteStatusError{url: url, status: resp.Status, code: resp.StatusCode}
 	}
 	if resp.ContentLength > maxSize {
-		return nil, fmt.Errorf("failed to download manifest from %s, response exceeds the %d-byte limit", url, maxSize)
+		return nil, fmt.Errorf("failed to download %s, response exceeds the %d-byte limit", url, maxSize)
 	}
 
 	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to download manifest from %s, error: %w", url, err)
+		return nil, fmt.Errorf("failed to download %s, error: %w", url, err)
 	}
 	if int64(len(data)) > maxSize {
-		return nil, fmt.Errorf("failed to download manifest from %s, response exceeds the %d-byte limit", url, maxSize)
+		return nil, fmt.Errorf("failed to download %s, response exceeds the %d-byte limit", url, maxSize)
 	}
 
 	return data, nil
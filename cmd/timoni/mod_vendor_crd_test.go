@@ -34,20 +34,20 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func TestReadRemoteCRDManifestAcceptsBodyAtLimit(t *testing.T) {
+func TestReadRemoteFileAcceptsBodyAtLimit(t *testing.T) {
 	g := NewWithT(t)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte("crd!"))
 	}))
 	defer server.Close()
 
-	data, err := readRemoteCRDManifest(context.Background(), server.Client(), server.URL, 4)
+	data, err := readRemoteFile(context.Background(), server.Client(), server.URL, 4)
 
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(string(data)).To(Equal("crd!"))
 }
 
-func TestReadRemoteCRDManifestHonorsCancellation(t *testing.T) {
+func TestReadRemoteFileHonorsCancellation(t *testing.T) {
 	g := NewWithT(t)
 	started := make(chan struct{})
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -62,12 +62,12 @@ func TestReadRemoteCRDManifestHonorsCancellation(t *testing.T) {
 		cancel()
 	}()
 
-	_, err := readRemoteCRDManifest(ctx, server.Client(), server.URL, 4)
+	_, err := readRemoteFile(ctx, server.Client(), server.URL, 4)
 
 	g.Expect(err).To(MatchError(ContainSubstring(context.Canceled.Error())))
 }
 
-func TestReadRemoteCRDManifestRejectsLargeContentLength(t *testing.T) {
+func TestReadRemoteFileRejectsLargeContentLength(t *testing.T) {
 	g := NewWithT(t)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Length", "5")
@@ -75,13 +75,13 @@ func TestReadRemoteCRDManifestRejectsLargeContentLength(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, err := readRemoteCRDManifest(context.Background(), server.Client(), server.URL, 4)
+	_, err := readRemoteFile(context.Background(), server.Client(), server.URL, 4)
 
 	g.Expect(err).To(MatchError(ContainSubstring("exceeds the 4-byte limit")))
 	g.Expect(err).To(MatchError(ContainSubstring(server.URL)))
 }
 
-func TestReadRemoteCRDManifestRejectsUnknownLengthBody(t *testing.T) {
+func TestReadRemoteFileRejectsUnknownLengthBody(t *testing.T) {
 	g := NewWithT(t)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.(http.Flusher).Flush()
@@ -89,12 +89,12 @@ func TestReadRemoteCRDManifestRejectsUnknownLengthBody(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, err := readRemoteCRDManifest(context.Background(), server.Client(), server.URL, 4)
+	_, err := readRemoteFile(context.Background(), server.Client(), server.URL, 4)
 
 	g.Expect(err).To(MatchError(ContainSubstring("exceeds the 4-byte limit")))
 }
 
-func TestReadRemoteCRDManifestLimitsDecodedBody(t *testing.T) {
+func TestReadRemoteFileLimitsDecodedBody(t *testing.T) {
 	g := NewWithT(t)
 	var compressed bytes.Buffer
 	zw := gzip.NewWriter(&compressed)
@@ -109,12 +109,12 @@ func TestReadRemoteCRDManifestLimitsDecodedBody(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, err = readRemoteCRDManifest(context.Background(), server.Client(), server.URL, 100)
+	_, err = readRemoteFile(context.Background(), server.Client(), server.URL, 100)
 
 	g.Expect(err).To(MatchError(ContainSubstring("exceeds the 100-byte limit")))
 }
 
-func TestReadRemoteCRDManifestRejectsInsecureRedirect(t *testing.T) {
+func TestReadRemoteFileRejectsInsecureRedirect(t *testing.T) {
 	g := NewWithT(t)
 	insecure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte("crd"))
@@ -126,7 +126,7 @@ func TestReadRemoteCRDManifestRejectsInsecureRedirect(t *testing.T) {
 	}))
 	defer secure.Close()
 
-	_, err := readRemoteCRDManifest(context.Background(), secure.Client(), secure.URL, 4)
+	_, err := readRemoteFile(context.Background(), secure.Client(), secure.URL, 4)
 
 	g.Expect(err).To(MatchError(ContainSubstring("redirect to insecure HTTP")))
 }
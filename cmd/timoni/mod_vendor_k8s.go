@@ -75,22 +75,26 @@ func runVendorK8sCmd(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
 	defer cancel()
 
-	ociURL := fmt.Sprintf("%s:%s", k8sSchemaURL, vendorK8sArgs.version)
-	if ver := vendorK8sArgs.version; ver != "latest" && !strings.HasPrefix(ver, "v") {
-		ociURL = fmt.Sprintf("%s:v%s", k8sSchemaURL, ver)
-	}
-
-	spin := logger.StartSpinner(fmt.Sprintf("importing schemas from %s", ociURL))
-	defer spin.Stop()
-
-	opts := oci.Options(ctx, "", rootArgs.registryInsecure)
-	err := oci.PullArtifact(ociURL, path.Join(cueModDir, "gen"), apiv1.CueModGenContentType, opts)
-	if err != nil {
+	if err := vendorK8sSchemas(ctx, cueModDir, vendorK8sArgs.version); err != nil {
 		return err
 	}
 
-	spin.Stop()
 	log.Info(fmt.Sprintf("schemas vendored: %s", logger.ColorizeSubject(path.Join(cueModDir, "gen", "k8s.io", "api"))))
 
 	return nil
 }
+
+// vendorK8sSchemas pulls the CUE schemas generated from the
+// Kubernetes APIs of the given minor version into the cue.mod/gen dir.
+func vendorK8sSchemas(ctx context.Context, cueModDir, version string) error {
+	ociURL := fmt.Sprintf("%s:%s", k8sSchemaURL, version)
+	if version != "latest" && !strings.HasPrefix(version, "v") {
+		ociURL = fmt.Sprintf("%s:v%s", k8sSchemaURL, version)
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("importing schemas from %s", ociURL))
+	defer spin.Stop()
+
+	opts := oci.Options(ctx, "", rootArgs.registryInsecure)
+	return oci.PullArtifact(ociURL, path.Join(cueModDir, "gen"), apiv1.CueModGenContentType, opts)
+}
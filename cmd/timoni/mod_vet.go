@@ -18,10 +18,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
 
+	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/pkg/strings"
 	ssautil "github.com/fluxcd/pkg/ssa/utils"
@@ -48,16 +50,36 @@ var vetModCmd = &cobra.Command{
 
   # validate module using debug values
   timoni mod vet ./path/to/module --debug
+
+  # validate the rendered objects against a policy pack
+  timoni mod vet ./path/to/module --policy ./policies/baseline
+
+  # validate the objects against the OpenAPI schemas of Kubernetes and CRDs
+  timoni mod vet ./path/to/module --kube-version 1.30 --crd-dir ./crds
+
+  # validate the objects against the Kubernetes schemas from a local directory
+  timoni mod vet ./path/to/module --kube-version 1.30 --kube-schema-dir ./openapi-spec/v3
+
+  # run the built-in lint rules and write the findings in the SARIF format
+  timoni mod vet ./path/to/module --lint --lint-output sarif > timoni.sarif
 `,
 	RunE: runVetModCmd,
 }
 
 type vetModFlags struct {
-	path        string
-	pkg         flags.Package
-	debug       bool
-	valuesFiles []string
-	name        string
+	path          string
+	pkg           flags.Package
+	debug         bool
+	valuesFiles   []string
+	valuesSet     valuesSetFlags
+	name          string
+	creds         flags.Credentials
+	policies      []string
+	lint          bool
+	lintOutput    string
+	kubeVersion   string
+	kubeSchemaDir string
+	crdDirs       []string
 }
 
 var vetModArgs vetModFlags
@@ -68,7 +90,21 @@ func init() {
 	vetModCmd.Flags().BoolVar(&vetModArgs.debug, "debug", false,
 		"Use debug_values.cue if found in the module root instead of the default values.")
 	vetModCmd.Flags().StringSliceVarP(&vetModArgs.valuesFiles, "values", "f", nil,
-		"The local path to values files (cue, yaml or json format).")
+		"The path to values files (cue, yaml or json format), an HTTPS URL or an OCI artifact URL.")
+	vetModArgs.valuesSet.addFlags(vetModCmd)
+	addValuesVerifyFlags(vetModCmd)
+	vetModCmd.Flags().Var(&vetModArgs.creds, vetModArgs.creds.Type(), vetModArgs.creds.Description())
+	addPolicyFlag(vetModCmd, &vetModArgs.policies)
+	vetModCmd.Flags().StringVar(&vetModArgs.kubeVersion, "kube-version", "",
+		"The Kubernetes version whose OpenAPI schemas are used to validate the objects e.g. 1.30.")
+	vetModCmd.Flags().StringVar(&vetModArgs.kubeSchemaDir, "kube-schema-dir", "",
+		"The path to a directory with the Kubernetes OpenAPI v3 documents of --kube-version, used instead of downloading them.")
+	vetModCmd.Flags().StringSliceVar(&vetModArgs.crdDirs, "crd-dir", nil,
+		"The path to a directory with CRDs whose OpenAPI schemas are used to validate the custom resources.")
+	vetModCmd.Flags().BoolVar(&vetModArgs.lint, "lint", false,
+		"Check the rendered objects with the built-in best-practice lint rules.")
+	vetModCmd.Flags().StringVar(&vetModArgs.lintOutput, "lint-output", "text",
+		"The format of the lint findings, can be 'text', 'json' or 'sarif'. The json and sarif formats imply --lint.")
 	modCmd.AddCommand(vetModCmd)
 }
 
@@ -79,6 +115,18 @@ func runVetModCmd(cmd *cobra.Command, args []string) error {
 		vetModArgs.path = args[0]
 	}
 
+	switch vetModArgs.lintOutput {
+	case "text":
+	case "json", "sarif":
+		vetModArgs.lint = true
+	default:
+		return fmt.Errorf("unknown --lint-output=%s, can be text, json or sarif", vetModArgs.lintOutput)
+	}
+
+	if vetModArgs.kubeSchemaDir != "" && vetModArgs.kubeVersion == "" {
+		return errors.New("--kube-schema-dir requires --kube-version")
+	}
+
 	if fs, err := os.Stat(vetModArgs.path); err != nil || !fs.IsDir() {
 		return fmt.Errorf("module not found at path %s", vetModArgs.path)
 	}
@@ -145,11 +193,20 @@ func runVetModCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("build failed: %w", err)
 	}
 
-	if len(vetModArgs.valuesFiles) > 0 {
-		valuesCue, err := convertToCue(cmd, vetModArgs.valuesFiles)
+	valuesFiles, err := resolveRemoteValues(cmd, vetModArgs.valuesFiles, tmpDir, vetModArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if len(valuesFiles) > 0 || vetModArgs.valuesSet.isSet() {
+		valuesCue, err := convertToCue(cmd, valuesFiles, newDecrypter())
 		if err != nil {
 			return err
 		}
+		setCue, err := vetModArgs.valuesSet.toCue()
+		if err != nil {
+			return err
+		}
+		valuesCue = append(valuesCue, setCue...)
 		err = builder.OverlayValuesFile(valuesCue)
 		if err != nil {
 			return err
@@ -179,11 +236,31 @@ func runVetModCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("build failed, no objects to apply")
 	}
 
+	if vetModArgs.kubeVersion != "" || len(vetModArgs.crdDirs) > 0 {
+		if err := validateSchemas(cmd, vetModArgs.kubeVersion, vetModArgs.kubeSchemaDir, vetModArgs.crdDirs, builder, buildResult); err != nil {
+			return err
+		}
+	}
+
 	for _, object := range objects {
 		log.Info(fmt.Sprintf("%s %s",
 			logger.ColorizeSubject(ssautil.FmtUnstructured(object)), logger.ColorizeInfo("valid resource")))
 	}
 
+	policies, err := loadPolicies(cmd, vetModArgs.policies, tmpDir, vetModArgs.creds.String())
+	if err != nil {
+		return err
+	}
+	if err := enforcePolicies(log, policies, builder, buildResult); err != nil {
+		return err
+	}
+
+	if vetModArgs.lint {
+		if err := lintObjects(cmd, builder, buildResult, objects); err != nil {
+			return err
+		}
+	}
+
 	images, err := builder.GetContainerImages(buildResult)
 	if err != nil {
 		return fmt.Errorf("failed to extract images: %w", err)
@@ -209,3 +286,50 @@ func runVetModCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// lintObjects runs the built-in lint rules against the objects and reports
+// the findings in the format set with --lint-output. It returns an error
+// if any of the findings has the error severity.
+func lintObjects(cmd *cobra.Command, builder *engine.ModuleBuilder, buildResult cue.Value, objects []*unstructured.Unstructured) error {
+	log := LoggerFrom(cmd.Context())
+
+	results := engine.Lint(objects)
+	engine.SetPositions(results, builder.GetObjectPositions(buildResult))
+
+	var failed int
+	for _, r := range results {
+		if r.Failed() {
+			failed++
+		}
+	}
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	switch vetModArgs.lintOutput {
+	case "json":
+		if err := engine.WriteLintJSONReport(cmd.OutOrStdout(), baseDir, results); err != nil {
+			return err
+		}
+	case "sarif":
+		if err := engine.WriteSARIFReport(cmd.OutOrStdout(), baseDir, VERSION, results); err != nil {
+			return err
+		}
+	default:
+		for _, r := range results {
+			if r.Failed() {
+				log.Error(nil, fmt.Sprintf("%s %s", logger.ColorizeSubject(ssautil.FmtUnstructured(r.Object)), r.String()))
+				continue
+			}
+			log.Info(fmt.Sprintf("%s %s", logger.ColorizeSubject(ssautil.FmtUnstructured(r.Object)),
+				logger.ColorizeWarning(r.String())))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("lint failed, %d finding(s) with error severity", failed)
+	}
+	return nil
+}
@@ -159,3 +159,45 @@ func TestModVetSetNamespaceName(t *testing.T) {
 		g.Expect(err.Error()).To(ContainSubstring("cannot find package"))
 	})
 }
+
+func TestModVetLint(t *testing.T) {
+	modPath := "testdata/module-lint"
+
+	t.Run("reports warnings", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --lint",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(output).To(ContainSubstring(`[image-latest-tag] container "server" uses the latest tag`))
+		g.Expect(output).To(ContainSubstring(`[container-probes] container "server" does not define livenessProbe`))
+		g.Expect(output).ToNot(ContainSubstring(`[container-probes] container "worker"`))
+		g.Expect(output).To(ContainSubstring("timoni.sh/test-lint valid"))
+	})
+
+	t.Run("fails with errors in the SARIF format", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --debug --lint-output sarif",
+			modPath,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("lint failed, 2 finding(s) with error severity"))
+
+		g.Expect(output).To(ContainSubstring(`"ruleId": "host-path-volume"`))
+		g.Expect(output).To(ContainSubstring(`"ruleId": "service-selector"`))
+		g.Expect(output).To(ContainSubstring(`"uri": "testdata/module-lint/timoni.cue"`))
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --lint-output xml",
+			modPath,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("unknown --lint-output=xml"))
+	})
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"github.com/Masterminds/semver/v3"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+)
+
+// kubeOpenAPIURL is the location of the Kubernetes OpenAPI v3 documents,
+// formatted with the release tag and the document file name.
+var kubeOpenAPIURL = "https://raw.githubusercontent.com/kubernetes/kubernetes/%s/api/openapi-spec/v3/%s"
+
+const maxKubeOpenAPISize int64 = 32 << 20
+
+// kubeSpecLoader returns a KubeSpecLoader which reads the OpenAPI v3
+// documents of the Kubernetes release from the cache directory. The
+// documents missing from the cache are downloaded once, then the
+// validation works offline. Group versions which are not served by the
+// release are cached as empty files.
+func kubeSpecLoader(ctx context.Context, kubeVersion, cacheDir string) (engine.KubeSpecLoader, error) {
+	v, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kubernetes version %s: %w", kubeVersion, err)
+	}
+	tag := fmt.Sprintf("v%d.%d.%d", v.Major(), v.Minor(), v.Patch())
+
+	dir := ""
+	if cacheDir != "" {
+		dir = filepath.Join(cacheDir, "kubernetes-openapi", tag)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(gv schema.GroupVersion) ([]byte, error) {
+		// Only the Kubernetes API groups are published,
+		// the others are served by CRDs.
+		if strings.Contains(gv.Group, ".") && !strings.HasSuffix(gv.Group, ".k8s.io") {
+			return nil, nil
+		}
+
+		file := engine.KubeSpecFile(gv)
+		if dir != "" {
+			if data, err := os.ReadFile(filepath.Join(dir, file)); err == nil {
+				if len(data) == 0 {
+					return nil, nil
+				}
+				return data, nil
+			}
+		}
+
+		data, err := readRemoteFile(ctx, cleanhttp.DefaultClient(), fmt.Sprintf(kubeOpenAPIURL, tag, file), maxKubeOpenAPISize)
+		var statusErr *remoteStatusError
+		if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+			data, err = nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if dir != "" {
+			if err := writeCacheFile(filepath.Join(dir, file), data); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	}, nil
+}
+
+// writeCacheFile writes the file through a rename, so that
+// concurrent runs never read a partially written file.
+func writeCacheFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// validateSchemas validates the objects against the OpenAPI v3 schemas of the
+// Kubernetes release and of the CRDs found in the directories. The Kubernetes
+// schemas are read from kubeSchemaDir when set, otherwise they are downloaded
+// to the cache. The objects without a schema are reported, unless only the
+// CRDs are supplied.
+func validateSchemas(cmd *cobra.Command, kubeVersion, kubeSchemaDir string, crdDirs []string,
+	builder *engine.ModuleBuilder, buildResult cue.Value) error {
+	log := LoggerFrom(cmd.Context())
+
+	var loader engine.KubeSpecLoader
+	switch {
+	case kubeSchemaDir != "":
+		if fs, err := os.Stat(kubeSchemaDir); err != nil || !fs.IsDir() {
+			return fmt.Errorf("Kubernetes schemas not found at path %s", kubeSchemaDir)
+		}
+		loader = engine.KubeSpecDirLoader(kubeSchemaDir)
+	case kubeVersion != "":
+		ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+		defer cancel()
+
+		var err error
+		loader, err = kubeSpecLoader(ctx, kubeVersion, rootArgs.cacheDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	validator := engine.NewSchemaValidator(kubeVersion, loader)
+	for _, dir := range crdDirs {
+		if err := validator.LoadCRDs(dir); err != nil {
+			return fmt.Errorf("loading CRDs from %s failed: %w", dir, err)
+		}
+	}
+
+	skipped, err := builder.ValidateSchemas(buildResult, validator)
+	if err != nil {
+		return fmt.Errorf("schema validation failed:\n%w", err)
+	}
+
+	if kubeVersion != "" {
+		for _, object := range skipped {
+			log.Info(fmt.Sprintf("%s %s", logger.ColorizeSubject(ssautil.FmtUnstructured(object)),
+				logger.ColorizeWarning("schema not found, skipping validation")))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kubeOpenAPIDir holds the OpenAPI documents shared with the engine tests.
+const kubeOpenAPIDir = "../../internal/engine/testdata/openapi"
+
+// serveKubeOpenAPI serves the OpenAPI documents from kubeOpenAPIDir
+// in place of the Kubernetes repository, counting the requests.
+func serveKubeOpenAPI(t *testing.T) *atomic.Int32 {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !strings.HasPrefix(r.URL.Path, "/v1.30.0/") {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(kubeOpenAPIDir, filepath.Base(r.URL.Path)))
+	}))
+	t.Cleanup(server.Close)
+
+	prevURL := kubeOpenAPIURL
+	kubeOpenAPIURL = server.URL + "/%s/%s"
+	t.Cleanup(func() { kubeOpenAPIURL = prevURL })
+	return &requests
+}
+
+// withCacheDir sets the cache directory for the duration of the test.
+func withCacheDir(t *testing.T) string {
+	prevDir := rootArgs.cacheDir
+	rootArgs.cacheDir = t.TempDir()
+	t.Cleanup(func() { rootArgs.cacheDir = prevDir })
+	return rootArgs.cacheDir
+}
+
+func TestKubeSpecLoader(t *testing.T) {
+	g := NewWithT(t)
+	requests := serveKubeOpenAPI(t)
+	cacheDir := t.TempDir()
+
+	loader, err := kubeSpecLoader(context.Background(), "1.30", cacheDir)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+	data, err := loader(apps)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring("io.k8s.api.apps.v1.Deployment"))
+	g.Expect(filepath.Join(cacheDir, "kubernetes-openapi", "v1.30.0", "apis__apps__v1_openapi.json")).To(BeARegularFile())
+
+	data, err = loader(schema.GroupVersion{Group: "extensions", Version: "v1beta1"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(BeNil())
+
+	data, err = loader(schema.GroupVersion{Group: "cert-manager.io", Version: "v1"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(BeNil())
+	g.Expect(requests.Load()).To(BeEquivalentTo(2))
+
+	// The documents and the group versions not served are read from the cache.
+	_, err = loader(apps)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = loader(schema.GroupVersion{Group: "extensions", Version: "v1beta1"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(requests.Load()).To(BeEquivalentTo(2))
+
+	_, err = kubeSpecLoader(context.Background(), "latest", cacheDir)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestModVetOpenAPI(t *testing.T) {
+	modPath := "testdata/module-openapi"
+	crdDir := filepath.Join(kubeOpenAPIDir, "crds")
+	serveKubeOpenAPI(t)
+	withCacheDir(t)
+
+	t.Run("vets objects against the schemas", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main -n default --kube-version 1.30 --crd-dir %s",
+			modPath, crdDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("PodInfo/default/default valid resource"))
+		g.Expect(output).ToNot(ContainSubstring("schema not found"))
+	})
+
+	t.Run("reports the objects without schema", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main -n default --kube-version 1.30",
+			modPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("PodInfo/default/default schema not found"))
+	})
+
+	t.Run("fails with the CUE path of the invalid objects", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main -n default --kube-version 1.30 --crd-dir %s -f %s",
+			modPath, crdDir, filepath.Join(modPath, "invalid-values.cue"),
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(`invalid object at path timoni.apply.app[0]: spec: property "replica" is unsupported`))
+		g.Expect(err.Error()).To(ContainSubstring(`invalid object at path timoni.apply.app[1]: spec.interval`))
+	})
+
+	t.Run("works offline from the cache", func(t *testing.T) {
+		g := NewWithT(t)
+		cacheDir := withCacheDir(t)
+		specDir := filepath.Join(cacheDir, "kubernetes-openapi", "v1.30.0")
+		g.Expect(os.MkdirAll(specDir, os.ModePerm)).To(Succeed())
+		data, err := os.ReadFile(filepath.Join(kubeOpenAPIDir, "apis__apps__v1_openapi.json"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(os.WriteFile(filepath.Join(specDir, "apis__apps__v1_openapi.json"), data, 0o644)).To(Succeed())
+
+		kubeOpenAPIURL = "http://127.0.0.1:1/%s/%s"
+		_, err = executeCommand(fmt.Sprintf(
+			"mod vet %s -p main -n default --kube-version 1.30 --crd-dir %s",
+			modPath, crdDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("reads the schemas from a local directory", func(t *testing.T) {
+		g := NewWithT(t)
+		kubeOpenAPIURL = "http://127.0.0.1:1/%s/%s"
+		withCacheDir(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main -n default --kube-version 1.30 --kube-schema-dir %s --crd-dir %s",
+			modPath, kubeOpenAPIDir, crdDir,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("PodInfo/default/default valid resource"))
+	})
+
+	t.Run("fails for a local directory without version", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --kube-schema-dir %s",
+			modPath, kubeOpenAPIDir,
+		))
+		g.Expect(err).To(MatchError("--kube-schema-dir requires --kube-version"))
+	})
+}
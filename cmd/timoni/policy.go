@@ -0,0 +1,219 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/oci"
+)
+
+// policyVerifyFlags holds the flags for verifying
+// the signature of policy pack artifacts.
+type policyVerifyFlags struct {
+	verify                      string
+	cosignKey                   string
+	certificateIdentity         string
+	certificateIdentityRegexp   string
+	certificateOidcIssuer       string
+	certificateOidcIssuerRegexp string
+}
+
+var policyVerifyArgs policyVerifyFlags
+
+// addPolicyFlag registers the --policy flag on the given command, along with
+// the signature verification flags of the policy pack artifacts. These are
+// prefixed, as they don't apply to the module and values artifacts.
+func addPolicyFlag(cmd *cobra.Command, policies *[]string) {
+	cmd.Flags().StringSliceVar(policies, "policy", nil,
+		"The path to a CUE policy pack directory or the OCI URL of a policy pack artifact, can be specified multiple times.")
+	cmd.Flags().StringVar(&policyVerifyArgs.verify, "verify-policy", "",
+		"Verifies the signed policy pack artifacts with the specified provider.")
+	cmd.Flags().StringVar(&policyVerifyArgs.cosignKey, "policy-cosign-key", "",
+		"The Cosign public key for verifying the policy pack artifacts.")
+	cmd.Flags().StringVar(&policyVerifyArgs.certificateIdentity, "policy-certificate-identity", "",
+		"The identity expected in a valid Fulcio certificate for verifying the Cosign signature of the policy pack artifacts.")
+	cmd.Flags().StringVar(&policyVerifyArgs.certificateIdentityRegexp, "policy-certificate-identity-regexp", "",
+		"A regular expression alternative to --policy-certificate-identity for verifying the Cosign signature of the policy pack artifacts.")
+	cmd.Flags().StringVar(&policyVerifyArgs.certificateOidcIssuer, "policy-certificate-oidc-issuer", "",
+		"The OIDC issuer expected in a valid Fulcio certificate for verifying the Cosign signature of the policy pack artifacts.")
+	cmd.Flags().StringVar(&policyVerifyArgs.certificateOidcIssuerRegexp, "policy-certificate-oidc-issuer-regexp", "",
+		"A regular expression alternative to --policy-certificate-oidc-issuer for verifying the Cosign signature of the policy pack artifacts.")
+}
+
+// loadPolicies loads the policy packs from the local directories or from
+// the OCI artifacts pulled to the destination directory. The artifacts are
+// verified when --verify-policy is specified. It returns nil when no policy
+// pack is specified.
+func loadPolicies(cmd *cobra.Command, packs []string, dstDir, creds string) (*engine.PolicySet, error) {
+	if !slices.ContainsFunc(packs, func(p string) bool { return strings.HasPrefix(p, apiv1.ArtifactPrefix) }) {
+		if err := validateProviderCompanionFlags(cmd, policyVerifyArgs.verify, "verify-policy",
+			"policy-cosign-key", "policy-certificate-identity", "policy-certificate-identity-regexp",
+			"policy-certificate-oidc-issuer", "policy-certificate-oidc-issuer-regexp"); err != nil {
+			return nil, err
+		}
+		if policyVerifyArgs.verify != "" {
+			return nil, errors.New("--verify-policy requires a policy pack artifact to be specified with --policy")
+		}
+	}
+	if len(packs) == 0 {
+		return nil, nil
+	}
+
+	policies := engine.NewPolicySet(cuecontext.New())
+	for i, pack := range packs {
+		dir := pack
+		name := filepath.Base(filepath.Clean(pack))
+		if strings.HasPrefix(pack, apiv1.ArtifactPrefix) {
+			dir = filepath.Join(dstDir, fmt.Sprintf("policy-%d", i))
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return nil, err
+			}
+			if err := pullPolicyArtifact(cmd, pack, dir, creds); err != nil {
+				return nil, err
+			}
+			name = policyArtifactName(pack)
+		}
+
+		if err := policies.LoadPack(name, dir); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// policyArtifactName returns the repository base name of the artifact URL
+// e.g. 'baseline' for 'oci://ghcr.io/org/policies/baseline:1.0.0'.
+func policyArtifactName(ociURL string) string {
+	name := path.Base(strings.TrimPrefix(ociURL, apiv1.ArtifactPrefix))
+	if i := strings.IndexAny(name, ":@"); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// pullPolicyArtifact resolves the artifact URL to a digest, verifies the
+// artifact signature if requested, and extracts the content of the policy
+// pack artifact to the destination directory.
+func pullPolicyArtifact(cmd *cobra.Command, ociURL, dstDir, creds string) error {
+	log := LoggerFrom(cmd.Context())
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	if err := validatePrefixedVerificationFlags(cmd, policyVerifyArgs.verify, "verify-policy", "policy-"); err != nil {
+		return err
+	}
+	if policyVerifyArgs.verify != "" {
+		if err := oci.ValidateVerificationProvider(policyVerifyArgs.verify); err != nil {
+			return err
+		}
+	}
+	if _, err := oci.ParseArtifactURL(ociURL); err != nil {
+		return err
+	}
+
+	opts := oci.Options(ctx, creds, rootArgs.registryInsecure)
+
+	// Pull the artifact by digest, so that the signature that is
+	// verified covers the content that is extracted.
+	digestURL, err := oci.ResolveDigestURL(ociURL, opts)
+	if err != nil {
+		return err
+	}
+
+	if policyVerifyArgs.verify != "" {
+		err = oci.VerifyArtifact(ctx, log,
+			policyVerifyArgs.verify,
+			digestURL,
+			policyVerifyArgs.cosignKey,
+			policyVerifyArgs.certificateIdentity,
+			policyVerifyArgs.certificateIdentityRegexp,
+			policyVerifyArgs.certificateOidcIssuer,
+			policyVerifyArgs.certificateOidcIssuerRegexp,
+			rootArgs.registryInsecure,
+			creds)
+		if err != nil {
+			return err
+		}
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("pulling %s", ociURL))
+	defer spin.Stop()
+
+	return oci.PullArtifact(digestURL, dstDir, apiv1.TimoniPolicyContentType, opts)
+}
+
+// errPolicyDenied is returned by enforcePolicies when a rule with
+// the deny action is violated.
+var errPolicyDenied = errors.New("policy check failed")
+
+// enforcePolicies checks the objects rendered by the module against the
+// policies, logs the violations, and returns an error if any of them is
+// denied. It is a no-op when policies is nil.
+func enforcePolicies(log logr.Logger, policies *engine.PolicySet, builder *engine.ModuleBuilder, buildResult cue.Value) error {
+	if policies == nil {
+		return nil
+	}
+
+	sets, err := builder.GetApplySets(buildResult)
+	if err != nil {
+		return fmt.Errorf("failed to extract objects: %w", err)
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, set := range sets {
+		objects = append(objects, set.Objects...)
+	}
+
+	violations, err := policies.Check(objects)
+	if err != nil {
+		return err
+	}
+
+	var denied int
+	for _, v := range violations {
+		if v.Denied() {
+			denied++
+			log.Error(nil, fmt.Sprintf("%s %s", logger.ColorizeSubject(ssautil.FmtUnstructured(v.Object)), v.String()))
+			continue
+		}
+		log.Info(fmt.Sprintf("%s %s", logger.ColorizeSubject(ssautil.FmtUnstructured(v.Object)),
+			logger.ColorizeWarning(v.String())))
+	}
+
+	if denied > 0 {
+		return fmt.Errorf("%w, %d violation(s) denied", errPolicyDenied, denied)
+	}
+	return nil
+}
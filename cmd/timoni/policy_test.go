@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPolicy(t *testing.T) {
+	modPath := "testdata/module"
+	labelsPath := "testdata/policy/labels"
+	tlsPath := "testdata/policy/tls"
+
+	t.Run("vets module with compliant objects", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --policy %s",
+			modPath, labelsPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("timoni.sh/test valid module"))
+	})
+
+	t.Run("fails to build with denied objects", func(t *testing.T) {
+		g := NewWithT(t)
+		output, err := executeCommand(fmt.Sprintf(
+			"build test %s -p main --policy %s --policy %s",
+			modPath, labelsPath, tlsPath,
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("policy check failed, 1 violation(s) denied"))
+		g.Expect(output).To(ContainSubstring("ConfigMap/default/test-client [tls/no-plain-tcp] servers should be reached over TLS"))
+		g.Expect(output).To(ContainSubstring("ConfigMap/default/test-server [tls/no-default-port]"))
+	})
+
+	t.Run("fails with invalid policy pack", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"build test %s -p main --policy %s",
+			modPath, t.TempDir(),
+		))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("no CUE files found"))
+	})
+
+	t.Run("vets module with policy pack artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		aURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("labels"))
+		_, err := executeCommand(fmt.Sprintf(
+			"artifact push oci://%s -f %s -t 1.0.0 --content-type=policy",
+			aURL, labelsPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		output, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --policy oci://%s:1.0.0",
+			modPath, aURL,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output).To(ContainSubstring("timoni.sh/test valid module"))
+	})
+
+	t.Run("fails for artifact without policy content type", func(t *testing.T) {
+		g := NewWithT(t)
+		aURL := fmt.Sprintf("%s/%s", dockerRegistry, rnd("labels"))
+		_, err := executeCommand(fmt.Sprintf(
+			"artifact push oci://%s -f %s -t 1.0.0 --content-type=generic",
+			aURL, labelsPath,
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --policy oci://%s:1.0.0",
+			modPath, aURL,
+		))
+		g.Expect(err).To(MatchError(ContainSubstring("content type 'policy'")))
+	})
+
+	t.Run("fails to verify policies without an artifact", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := executeCommand(fmt.Sprintf(
+			"mod vet %s -p main --policy %s --verify-policy=cosign",
+			modPath, labelsPath,
+		))
+		g.Expect(err).To(MatchError(ContainSubstring("--verify-policy requires a policy pack artifact")))
+	})
+}
+
+func TestPolicyArtifactName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"oci://ghcr.io/org/policies/baseline:1.0.0", "baseline"},
+		{"oci://ghcr.io/org/policies/baseline@sha256:abc", "baseline"},
+		{"oci://ghcr.io/org/baseline", "baseline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(policyArtifactName(tt.url)).To(Equal(tt.want))
+		})
+	}
+}
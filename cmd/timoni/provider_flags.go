@@ -44,16 +44,27 @@ func validateProviderCompanionFlags(cmd *cobra.Command, provider, providerFlag s
 
 // validateVerificationFlags checks verification companion flag relationships.
 func validateVerificationFlags(cmd *cobra.Command, provider string) error {
-	companionFlags := []string{
+	return validatePrefixedVerificationFlags(cmd, provider, "verify", "")
+}
+
+// validatePrefixedVerificationFlags checks the relationships of the
+// verification companion flags whose names start with the given prefix,
+// for the commands that verify more than one kind of artifact.
+func validatePrefixedVerificationFlags(cmd *cobra.Command, provider, providerFlag, prefix string) error {
+	names := []string{
 		"cosign-key", "certificate-identity", "certificate-identity-regexp",
 		"certificate-oidc-issuer", "certificate-oidc-issuer-regexp",
 	}
-	if err := validateProviderCompanionFlags(cmd, provider, "verify", companionFlags...); err != nil {
+	companionFlags := make([]string, 0, len(names))
+	for _, name := range names {
+		companionFlags = append(companionFlags, prefix+name)
+	}
+	if err := validateProviderCompanionFlags(cmd, provider, providerFlag, companionFlags...); err != nil {
 		return err
 	}
-	values := make(map[string]string, len(companionFlags))
-	for _, name := range companionFlags {
-		value, err := cmd.Flags().GetString(name)
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := cmd.Flags().GetString(prefix + name)
 		if err != nil {
 			return err
 		}
@@ -66,32 +77,32 @@ func validateVerificationFlags(cmd *cobra.Command, provider string) error {
 	}
 	for _, pair := range conflicts {
 		if values[pair[0]] != "" && values[pair[1]] != "" {
-			return fmt.Errorf("--%s and --%s are mutually exclusive", pair[0], pair[1])
+			return fmt.Errorf("--%s%s and --%s%s are mutually exclusive", prefix, pair[0], prefix, pair[1])
 		}
 	}
 	if provider != "cosign" {
 		return nil
 	}
 
-	certificateFlags := companionFlags[1:]
+	certificateFlags := names[1:]
 	if values["cosign-key"] != "" {
 		for _, name := range certificateFlags {
 			if values[name] != "" {
-				return fmt.Errorf("--cosign-key cannot be combined with certificate verification flags")
+				return fmt.Errorf("--%scosign-key cannot be combined with certificate verification flags", prefix)
 			}
 		}
 		return nil
 	}
 	if values["certificate-identity"] == "" && values["certificate-identity-regexp"] == "" {
-		return fmt.Errorf("--certificate-identity or --certificate-identity-regexp is required for Cosign verification in keyless mode")
+		return fmt.Errorf("--%[1]scertificate-identity or --%[1]scertificate-identity-regexp is required for Cosign verification in keyless mode", prefix)
 	}
 	if values["certificate-oidc-issuer"] == "" && values["certificate-oidc-issuer-regexp"] == "" {
-		return fmt.Errorf("--certificate-oidc-issuer or --certificate-oidc-issuer-regexp is required for Cosign verification in keyless mode")
+		return fmt.Errorf("--%[1]scertificate-oidc-issuer or --%[1]scertificate-oidc-issuer-regexp is required for Cosign verification in keyless mode", prefix)
 	}
 	for _, name := range []string{"certificate-identity-regexp", "certificate-oidc-issuer-regexp"} {
 		if value := values[name]; value != "" {
 			if _, err := regexp.Compile(value); err != nil {
-				return fmt.Errorf("invalid --%s: %w", name, err)
+				return fmt.Errorf("invalid --%s%s: %w", prefix, name, err)
 			}
 		}
 	}
@@ -20,9 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 
 	"cuelang.org/go/cue/cuecontext"
 	"github.com/spf13/cobra"
@@ -30,14 +31,28 @@ import (
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 	"github.com/stefanprodan/timoni/internal/engine"
 	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/mask"
 	"github.com/stefanprodan/timoni/internal/runtime"
 )
 
 var runtimeBuildCmd = &cobra.Command{
 	Use:   "build",
 	Short: "Build validates the runtime definition, queries the cluster, extracts the values and prints them",
+	Long: `The runtime build command validates the runtime definition, fetches the data of each query
+from its provider, extracts the values and prints them along with the provider name.
+The values of the queries marked as secret are masked.
+
+With --output, the values of each cluster are written to a snapshot file, which
+can be used to build bundles without access to the clusters. The secret values
+are written in plain text, unless age recipients are specified.`,
 	Example: `  #  Print the runtime values from a cluster
   timoni runtime build -f runtime.cue
+
+  # Write the runtime values of all clusters to a snapshot file
+  # with the secret values encrypted for an age recipient
+  timoni runtime build -f runtime.cue \
+  --output snapshot.json \
+  --age-recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
 `,
 	Args: cobra.NoArgs,
 	RunE: runRuntimeBuildCmd,
@@ -48,6 +63,9 @@ type runtimeBuildFlags struct {
 	clusterSelector      string
 	clusterGroupSelector string
 	workdir              string
+	output               string
+	ageRecipients        []string
+	localProviders       localProviders
 }
 
 var runtimeBuildArgs runtimeBuildFlags
@@ -61,6 +79,11 @@ func init() {
 		"Select clusters by group name.")
 	runtimeBuildCmd.Flags().StringVar(&runtimeBuildArgs.workdir, "workdir", "",
 		"The local path to the CUE module root (the directory containing cue.mod), used to resolve imports in the runtime definitions. Defaults to the current directory.")
+	runtimeBuildArgs.localProviders.addFlags(runtimeBuildCmd.Flags())
+	runtimeBuildCmd.Flags().StringVarP(&runtimeBuildArgs.output, "output", "o", "",
+		"The local path to a JSON file where the runtime values are written as a snapshot.")
+	runtimeBuildCmd.Flags().StringSliceVar(&runtimeBuildArgs.ageRecipients, "age-recipient", nil,
+		"The age public keys used to encrypt the secret values in the snapshot.")
 	runtimeCmd.AddCommand(runtimeBuildCmd)
 }
 
@@ -97,6 +120,12 @@ func runRuntimeBuildCmd(cmd *cobra.Command, args []string) error {
 		return errors.New("no cluster found")
 	}
 
+	if len(runtimeBuildArgs.ageRecipients) > 0 && runtimeBuildArgs.output == "" {
+		return errors.New("--age-recipient requires --output")
+	}
+
+	snapshot := runtime.NewSnapshot(rt.Name)
+	decrypter := newDecrypter()
 	for _, cluster := range clusters {
 		log := loggerRuntime(cmd.Context(), rt.Name, cluster.Name, true)
 
@@ -106,27 +135,47 @@ func runRuntimeBuildCmd(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		reader := runtime.NewResourceReader(rm)
+		reader := newResourceReader(rm, decrypter, runtimeBuildArgs.localProviders)
 
-		values, err := reader.Read(ctx, rt.Refs)
+		values, err := reader.ReadValues(ctx, rt.Refs)
 		if err != nil {
 			return err
 		}
 
-		keys := make([]string, 0, len(values))
-
-		for k := range values {
-			keys = append(keys, k)
+		// the last query defining a value takes precedence
+		latest := make(map[string]runtime.Value, len(values))
+		for _, v := range values {
+			latest[v.Name] = v
 		}
-		sort.Strings(keys)
 
-		for _, k := range keys {
-			log.Info(fmt.Sprintf("%s: %s", logger.ColorizeSubject(k), values[k]))
+		for _, k := range slices.Sorted(maps.Keys(latest)) {
+			v := latest[k]
+			value := v.Value
+			if v.Secret {
+				value = mask.Value
+			}
+			log.Info(fmt.Sprintf("%s (%s): %s", logger.ColorizeSubject(k), v.Provider, value))
 		}
 
 		if len(values) == 0 {
 			log.Info("no values defined")
 		}
+
+		if runtimeBuildArgs.output != "" {
+			sc, err := runtime.NewSnapshotCluster(cluster, values, runtimeBuildArgs.ageRecipients)
+			if err != nil {
+				return err
+			}
+			snapshot.Clusters = append(snapshot.Clusters, *sc)
+		}
+	}
+
+	if runtimeBuildArgs.output != "" {
+		if err := runtime.WriteSnapshot(runtimeBuildArgs.output, snapshot); err != nil {
+			return err
+		}
+		log := loggerRuntime(cmd.Context(), rt.Name, apiv1.RuntimeDefaultName, true)
+		log.Info(fmt.Sprintf("snapshot written to %s", logger.ColorizeSubject(runtimeBuildArgs.output)))
 	}
 
 	return nil
@@ -29,6 +29,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stefanprodan/timoni/internal/mask"
 )
 
 func Test_RuntimeBuild(t *testing.T) {
@@ -109,7 +111,7 @@ runtime: {
 		g.Expect(output).To(ContainSubstring("cm.local"))
 	})
 
-	t.Run("builds runtime from Secret", func(t *testing.T) {
+	t.Run("builds runtime from Secret with masked values", func(t *testing.T) {
 		g := NewWithT(t)
 
 		err := envTestClient.Delete(context.Background(), cm)
@@ -122,7 +124,9 @@ runtime: {
 		output, err := executeCommand(cmd)
 		g.Expect(err).ToNot(HaveOccurred())
 		t.Log("\n", output)
-		g.Expect(output).To(ContainSubstring("sc.local"))
+		g.Expect(output).To(ContainSubstring("DOMAIN"))
+		g.Expect(output).To(ContainSubstring(mask.Value))
+		g.Expect(output).ToNot(ContainSubstring("sc.local"))
 	})
 }
 
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/spf13/pflag"
+
+	"github.com/stefanprodan/timoni/internal/runtime"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+// localProviders selects the runtime value providers with
+// access to the local machine that are enabled for a command.
+type localProviders struct {
+	env  bool
+	file bool
+	exec bool
+}
+
+// addFlags registers the --allow-env, --allow-file and --allow-exec flags.
+func (p *localProviders) addFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&p.env, "allow-env", false,
+		"Enable the 'env' runtime provider, which reads environment variables.")
+	flags.BoolVar(&p.file, "allow-file", false,
+		"Enable the 'file' runtime provider, which reads local files.")
+	flags.BoolVar(&p.exec, "allow-exec", false,
+		"Enable the 'exec' runtime provider, which runs local commands.")
+}
+
+// enabled returns true if at least one local provider is enabled.
+func (p localProviders) enabled() bool {
+	return p.env || p.file || p.exec
+}
+
+// newResourceReader returns a runtime reader for the given cluster
+// that decrypts the sops queries with the given decrypter, and
+// with the given local providers enabled.
+func newResourceReader(rm *ssa.ResourceManager, decrypter *sops.Decrypter, local localProviders) *runtime.ResourceReader {
+	reader := runtime.NewResourceReader(rm)
+	reader.RegisterProvider(runtime.SOPSProvider, runtime.NewSOPSProvider(decrypter))
+	if local.env {
+		reader.RegisterProvider(runtime.EnvProvider, runtime.NewEnvProvider())
+	}
+	if local.file {
+		reader.RegisterProvider(runtime.FileProvider, runtime.NewFileProvider())
+	}
+	if local.exec {
+		reader.RegisterProvider(runtime.ExecProvider, runtime.NewExecProvider())
+	}
+	return reader
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+// newDecrypter returns a SOPS decrypter that reads the age keys from
+// the file set with --sops-age-key-file or from the SOPS env vars.
+func newDecrypter() *sops.Decrypter {
+	return sops.NewDecrypter(rootArgs.sopsAgeKeyFile)
+}
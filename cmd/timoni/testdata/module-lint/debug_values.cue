@@ -0,0 +1,10 @@
+@if(debug)
+
+package main
+
+// Values which violate the lint rules with the error severity.
+values: {
+	image:    "nginx:1.27"
+	hostPath: "/var/log"
+	selector: "-missing"
+}
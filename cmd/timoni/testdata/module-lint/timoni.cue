@@ -0,0 +1,101 @@
+package main
+
+// Define the schema for the user-supplied values.
+values: {
+	image:     *"nginx:latest" | string
+	hostPath?: string
+	selector:  *"" | string
+}
+
+// Define how Timoni should build, validate and
+// apply the Kubernetes resources.
+timoni: {
+	apiVersion: "v1alpha1"
+
+	instance: {
+		config: {
+			metadata: {
+				name:      string @tag(name)
+				namespace: string @tag(namespace)
+			}
+			image: values.image
+			if values.hostPath != _|_ {
+				hostPath: values.hostPath
+			}
+			selector: values.selector
+		}
+
+		objects: {
+			// The worker has no probes, the rule is ignored with the annotation.
+			worker: {
+				apiVersion: "apps/v1"
+				kind:       "Deployment"
+				metadata: {
+					name:      "\(config.metadata.name)-worker"
+					namespace: config.metadata.namespace
+					annotations: "lint.timoni.sh/ignore": "container-probes"
+				}
+				spec: {
+					selector: matchLabels: app: "\(config.metadata.name)-worker"
+					template: {
+						metadata: labels: app: "\(config.metadata.name)-worker"
+						spec: {
+							containers: [{
+								name:  "worker"
+								image: config.image
+								resources: {
+									requests: cpu:    "100m"
+									limits: memory: "128Mi"
+								}
+							}]
+							if config.hostPath != _|_ {
+								volumes: [{
+									name: "logs"
+									hostPath: path: config.hostPath
+								}]
+							}
+						}
+					}
+				}
+			}
+
+			server: {
+				apiVersion: "apps/v1"
+				kind:       "Deployment"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				spec: {
+					selector: matchLabels: app: config.metadata.name
+					template: {
+						metadata: labels: app: config.metadata.name
+						spec: containers: [{
+							name:  "server"
+							image: config.image
+							readinessProbe: httpGet: {path: "/", port: 80}
+						}]
+					}
+				}
+			}
+
+			svc: {
+				apiVersion: "v1"
+				kind:       "Service"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				spec: {
+					selector: app: config.metadata.name + config.selector
+					ports: [{
+						name: "http"
+						port: 80
+					}]
+				}
+			}
+		}
+	}
+
+	apply: app: [for obj in instance.objects {obj}]
+}
@@ -0,0 +1,5 @@
+// Note that this file must have no imports and all values must be concrete.
+
+package main
+
+values: {}
@@ -0,0 +1,2 @@
+module: "timoni.sh/test-openapi"
+language: version: "v0.17.1"
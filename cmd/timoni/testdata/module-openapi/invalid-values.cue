@@ -0,0 +1,4 @@
+values: {
+	interval: "1x"
+	extra: replica: 2
+}
@@ -0,0 +1,63 @@
+package main
+
+// Define the schema for the user-supplied values.
+values: {
+	interval: *"5m" | string
+	extra: {...}
+}
+
+// Define how Timoni should build, validate and
+// apply the Kubernetes resources.
+timoni: {
+	apiVersion: "v1alpha1"
+
+	instance: {
+		config: {
+			metadata: {
+				name:      string @tag(name)
+				namespace: string @tag(namespace)
+			}
+			interval: values.interval
+			extra:    values.extra
+		}
+
+		objects: {
+			// The Deployment spec is loosely typed, the extra
+			// fields are only rejected by the OpenAPI validation.
+			deploy: {
+				apiVersion: "apps/v1"
+				kind:       "Deployment"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				spec: {
+					selector: matchLabels: app: config.metadata.name
+					template: {
+						metadata: labels: app: config.metadata.name
+						spec: containers: [{
+							name:  "app"
+							image: "nginx:1.27"
+						}]
+					}
+					config.extra
+				}
+			}
+
+			podinfo: {
+				apiVersion: "example.com/v1"
+				kind:       "PodInfo"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				spec: {
+					url:      "http://\(config.metadata.name)"
+					interval: config.interval
+				}
+			}
+		}
+	}
+
+	apply: app: [for obj in instance.objects {obj}]
+}
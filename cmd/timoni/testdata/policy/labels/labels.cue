@@ -0,0 +1,8 @@
+package labels
+
+rules: "team-label": {
+	match: kind: "ConfigMap"
+	message: "the app.kubernetes.io/team label is required"
+	#object: metadata: labels: [string]: string
+	allow: #object.metadata.labels["app.kubernetes.io/team"] != _|_
+}
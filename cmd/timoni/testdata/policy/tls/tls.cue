@@ -0,0 +1,25 @@
+package tls
+
+import "strings"
+
+rules: "no-plain-tcp": {
+	match: {
+		group: ""
+		kind:  "ConfigMap"
+	}
+	severity: "warn"
+	message:  "servers should be reached over TLS"
+	#object: data: [string]: string
+	allow: !strings.HasPrefix(#object.data.server, "tcp://")
+	// The rule only applies to the objects with a server field.
+	allowIncomplete: true
+}
+
+rules: "no-default-port": {
+	match: kind: "ConfigMap"
+	message: "the default port 9090 is not allowed"
+	#object: data: [string]: string
+	allow: #object.data.port != "9090"
+	// The rule only applies to the objects with a port field.
+	allowIncomplete: true
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/dyff"
+	"github.com/stefanprodan/timoni/internal/engine"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/runtime"
+)
+
+// instanceValuesMigrations returns the stored instance and the module
+// migrations matching the module version the instance was last applied with.
+// No migrations are returned for new instances, for instances applied with
+// the same module version or with a version that is not semver. Storage
+// errors other than not found are returned, so that an unreadable instance
+// is not applied as a new one.
+// It must be called before the user values are overlaid on the builder.
+func instanceValuesMigrations(ctx context.Context,
+	log logr.Logger,
+	kubeconfig *genericclioptions.ConfigFlags,
+	builder *engine.ModuleBuilder,
+	name, namespace, version string) (*apiv1.Instance, []engine.ValuesMigration, error) {
+	rm, err := runtime.NewResourceManager(kubeconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored, err := runtime.NewStorageManager(rm).Get(ctx, name, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	storedVersion := stored.Module.Version
+	if storedVersion == version {
+		return stored, nil, nil
+	}
+	if _, err := semver.NewVersion(storedVersion); err != nil {
+		return stored, nil, nil
+	}
+
+	configValue, err := builder.BuildConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("build failed: %w", err)
+	}
+
+	migrations, err := builder.GetValuesMigrations(configValue, storedVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range migrations {
+		log.Info(fmt.Sprintf("migrating values from version %s: %s",
+			logger.ColorizeSubject(storedVersion), m))
+	}
+
+	return stored, migrations, nil
+}
+
+// overlayMigratedValues merges the values overlays, runs the migrations on
+// the result and overlays the migrated values on the module default values.
+func overlayMigratedValues(builder *engine.ModuleBuilder, overlays [][]byte, migrations []engine.ValuesMigration) error {
+	values, err := engine.NewValuesBuilder(cuecontext.New()).MergeOverlays(overlays)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := builder.MigrateValues(values, migrations)
+	if err != nil {
+		return fmt.Errorf("migrating values failed: %w", err)
+	}
+
+	return builder.OverlayValuesFileWithDefaults(migrated)
+}
+
+// printValuesMigrationDiff prints the changes made by the migrations
+// to the values stored in the cluster, with the sensitive values redacted.
+func printValuesMigrationDiff(w io.Writer,
+	tmpDir string,
+	builder *engine.ModuleBuilder,
+	stored *apiv1.Instance,
+	migrations []engine.ValuesMigration,
+	redact func([]byte) []byte) error {
+	values, err := engine.RevealValues(stored.Values, nil)
+	if err != nil {
+		return err
+	}
+
+	storedValue := cuecontext.New().CompileString(values)
+	if err := storedValue.Err(); err != nil {
+		return fmt.Errorf("parsing the stored values failed: %w", err)
+	}
+
+	migratedValue, err := builder.MigrateValues(storedValue, migrations)
+	if err != nil {
+		return fmt.Errorf("migrating the stored values failed: %w", err)
+	}
+
+	dir, err := os.MkdirTemp(tmpDir, "values")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	liveFile := filepath.Join(dir, "live.yaml")
+	if err := writeValuesYAML(liveFile, storedValue, redact); err != nil {
+		return err
+	}
+	mergedFile := filepath.Join(dir, "migrated.yaml")
+	if err := writeValuesYAML(mergedFile, migratedValue, redact); err != nil {
+		return err
+	}
+
+	return dyff.DiffYAML(liveFile, mergedFile, w)
+}
+
+// writeValuesYAML writes the values as a YAML document under the values key.
+func writeValuesYAML(path string, value cue.Value, redact func([]byte) []byte) error {
+	var data map[string]any
+	if err := value.Decode(&data); err != nil {
+		return fmt.Errorf("decoding values failed: %w", err)
+	}
+
+	out, err := yaml.Marshal(map[string]any{apiv1.ValuesSelector.String(): data})
+	if err != nil {
+		return err
+	}
+	if redact != nil {
+		out = redact(out)
+	}
+
+	return os.WriteFile(path, out, 0o600)
+}
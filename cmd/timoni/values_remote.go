@@ -0,0 +1,228 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/spf13/cobra"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/oci"
+)
+
+// maxRemoteValuesSize is the size limit of the values files
+// and bundle files downloaded over HTTPS.
+const maxRemoteValuesSize int64 = 4 << 20
+
+// valuesVerifyFlags holds the flags for verifying
+// the signature of values artifacts.
+type valuesVerifyFlags struct {
+	verify                      string
+	cosignKey                   string
+	certificateIdentity         string
+	certificateIdentityRegexp   string
+	certificateOidcIssuer       string
+	certificateOidcIssuerRegexp string
+}
+
+var valuesVerifyArgs valuesVerifyFlags
+
+// addValuesVerifyFlags registers the signature verification flags
+// of values artifacts on the given command. The flags are prefixed,
+// as they don't apply to the module artifact.
+func addValuesVerifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&valuesVerifyArgs.verify, "verify-values", "",
+		"Verifies the signed values artifacts with the specified provider. The module artifact is not verified.")
+	cmd.Flags().StringVar(&valuesVerifyArgs.cosignKey, "values-cosign-key", "",
+		"The Cosign public key for verifying the values artifacts.")
+	cmd.Flags().StringVar(&valuesVerifyArgs.certificateIdentity, "values-certificate-identity", "",
+		"The identity expected in a valid Fulcio certificate for verifying the Cosign signature of the values artifacts.")
+	cmd.Flags().StringVar(&valuesVerifyArgs.certificateIdentityRegexp, "values-certificate-identity-regexp", "",
+		"A regular expression alternative to --values-certificate-identity for verifying the Cosign signature of the values artifacts.")
+	cmd.Flags().StringVar(&valuesVerifyArgs.certificateOidcIssuer, "values-certificate-oidc-issuer", "",
+		"The OIDC issuer expected in a valid Fulcio certificate for verifying the Cosign signature of the values artifacts.")
+	cmd.Flags().StringVar(&valuesVerifyArgs.certificateOidcIssuerRegexp, "values-certificate-oidc-issuer-regexp", "",
+		"A regular expression alternative to --values-certificate-oidc-issuer for verifying the Cosign signature of the values artifacts.")
+}
+
+// isRemoteFile returns true if the given file is an HTTPS URL.
+func isRemoteFile(file string) bool {
+	return strings.HasPrefix(file, "https://")
+}
+
+// resolveRemoteValues replaces the OCI artifact URLs and the HTTPS URLs
+// found in the values files with the paths of the files downloaded to the
+// destination directory, keeping the merge order. The values files extracted
+// from an artifact are merged in lexical order. The artifacts are verified
+// when --verify-values is specified.
+func resolveRemoteValues(cmd *cobra.Command, paths []string, dstDir, creds string) ([]string, error) {
+	if !slices.ContainsFunc(paths, func(p string) bool { return strings.HasPrefix(p, apiv1.ArtifactPrefix) }) {
+		if err := validateProviderCompanionFlags(cmd, valuesVerifyArgs.verify, "verify-values",
+			"values-cosign-key", "values-certificate-identity", "values-certificate-identity-regexp",
+			"values-certificate-oidc-issuer", "values-certificate-oidc-issuer-regexp"); err != nil {
+			return nil, err
+		}
+		if valuesVerifyArgs.verify != "" {
+			return nil, errors.New("--verify-values requires a values artifact to be specified with -f")
+		}
+	}
+
+	result := make([]string, 0, len(paths))
+	for i, p := range paths {
+		switch {
+		case strings.HasPrefix(p, apiv1.ArtifactPrefix):
+			dir := filepath.Join(dstDir, fmt.Sprintf("values-%d", i))
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return nil, err
+			}
+			if err := pullValuesArtifact(cmd, p, dir, creds); err != nil {
+				return nil, err
+			}
+			files, err := scanValuesDir(dir)
+			if err != nil {
+				return nil, fmt.Errorf("invalid values artifact %s: %w", p, err)
+			}
+			result = append(result, files...)
+		case isRemoteFile(p):
+			file, err := downloadRemoteFile(cmd, p, dstDir, i)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, file)
+		default:
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// pullValuesArtifact resolves the artifact URL to a digest, verifies the
+// artifact signature if requested, and extracts the artifact content to
+// the destination directory.
+func pullValuesArtifact(cmd *cobra.Command, ociURL, dstDir, creds string) error {
+	log := LoggerFrom(cmd.Context())
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	if err := validatePrefixedVerificationFlags(cmd, valuesVerifyArgs.verify, "verify-values", "values-"); err != nil {
+		return err
+	}
+	if valuesVerifyArgs.verify != "" {
+		if err := oci.ValidateVerificationProvider(valuesVerifyArgs.verify); err != nil {
+			return err
+		}
+	}
+	if _, err := oci.ParseArtifactURL(ociURL); err != nil {
+		return err
+	}
+
+	opts := oci.Options(ctx, creds, rootArgs.registryInsecure)
+
+	// Pull the artifact by digest, so that the signature that is
+	// verified covers the content that is extracted.
+	digestURL, err := oci.ResolveDigestURL(ociURL, opts)
+	if err != nil {
+		return err
+	}
+
+	if valuesVerifyArgs.verify != "" {
+		err = oci.VerifyArtifact(ctx, log,
+			valuesVerifyArgs.verify,
+			digestURL,
+			valuesVerifyArgs.cosignKey,
+			valuesVerifyArgs.certificateIdentity,
+			valuesVerifyArgs.certificateIdentityRegexp,
+			valuesVerifyArgs.certificateOidcIssuer,
+			valuesVerifyArgs.certificateOidcIssuerRegexp,
+			rootArgs.registryInsecure,
+			creds)
+		if err != nil {
+			return err
+		}
+	}
+
+	spin := logger.StartSpinner(fmt.Sprintf("pulling %s", ociURL))
+	defer spin.Stop()
+
+	return oci.PullArtifact(digestURL, dstDir, apiv1.TimoniValuesContentType, opts)
+}
+
+// scanValuesDir returns the CUE, YAML and JSON files
+// found in the given directory in lexical order.
+func scanValuesDir(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(p) {
+		case ".cue", ".yaml", ".yml", ".json":
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no values files found")
+	}
+	return files, nil
+}
+
+// downloadRemoteFile downloads the file from the HTTPS URL to the
+// destination directory and returns its path. The index is used as
+// the file name prefix, to avoid collisions between URLs with the same
+// base name, while the extension is kept for detecting the file format.
+func downloadRemoteFile(cmd *cobra.Command, rawURL, dstDir string, index int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), rootArgs.timeout)
+	defer cancel()
+
+	data, err := readRemoteFile(ctx, cleanhttp.DefaultClient(), rawURL, maxRemoteValuesSize)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dstDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dstDir, fmt.Sprintf("%d-%s", index, path.Base(u.Path)))
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return "", err
+	}
+	return file, nil
+}
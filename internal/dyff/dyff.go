@@ -94,6 +94,7 @@ func InstanceDryRunDiff(ctx context.Context,
 	nsExists bool,
 	tmpDir string,
 	withDiff bool,
+	redact func([]byte) []byte,
 	w io.Writer) error {
 	log := logr.FromContextOrDiscard(ctx)
 	diffOpts := ssa.DefaultDiffOptions()
@@ -125,12 +126,16 @@ func InstanceDryRunDiff(ctx context.Context,
 		log.Info(logger.ColorizeJoin(change, logger.DryRunServer))
 		if withDiff && change.Action == ssa.ConfiguredAction {
 			liveYAML, _ := yaml.Marshal(liveObject)
+			mergedYAML, _ := yaml.Marshal(mergedObject)
+			if redact != nil {
+				liveYAML, mergedYAML = redact(liveYAML), redact(mergedYAML)
+			}
+
 			liveFile := filepath.Join(tmpDir, "live.yaml")
 			if err := os.WriteFile(liveFile, liveYAML, 0644); err != nil {
 				return err
 			}
 
-			mergedYAML, _ := yaml.Marshal(mergedObject)
 			mergedFile := filepath.Join(tmpDir, "merged.yaml")
 			if err := os.WriteFile(mergedFile, mergedYAML, 0644); err != nil {
 				return err
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/encoding/json"
+	"cuelang.org/go/encoding/yaml"
+)
+
+// BundleArtifactFiles holds the definitions found in the
+// extracted content of a bundle artifact.
+type BundleArtifactFiles struct {
+	// Bundles is the list of files defining the bundle and its values.
+	Bundles []string
+
+	// Runtimes is the list of files defining runtimes.
+	Runtimes []string
+
+	// ModuleRoot is set when the artifact contains a
+	// cue.mod directory at its root.
+	ModuleRoot bool
+}
+
+// ScanBundleArtifact walks the extracted content of a bundle artifact and
+// sorts the CUE, YAML and JSON files by their top-level field: files
+// declaring 'bundle' are bundle definitions, files declaring 'runtime'
+// are runtime definitions. All the other files, and the content of the
+// cue.mod directory, are ignored. The files are returned in lexical order.
+func ScanBundleArtifact(dir string) (*BundleArtifactFiles, error) {
+	result := &BundleArtifactFiles{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "cue.mod" {
+				if filepath.Dir(path) == filepath.Clean(dir) {
+					result.ModuleRoot = true
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fields, err := topLevelFields(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case fields["bundle"]:
+			result.Bundles = append(result.Bundles, path)
+		case fields["runtime"]:
+			result.Runtimes = append(result.Runtimes, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Bundles) == 0 {
+		return nil, fmt.Errorf("no bundle definitions found in artifact")
+	}
+	return result, nil
+}
+
+// topLevelFields parses the given file and returns the names of its
+// top-level fields. Files with extensions other than
+// .cue, .yaml, .yml and .json have no fields.
+func topLevelFields(path string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+
+	var parsefn func(string, []byte) (ast.Node, error)
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		parsefn = func(filename string, src []byte) (ast.Node, error) { return yaml.Extract(filename, src) }
+	case ".json":
+		parsefn = func(filename string, src []byte) (ast.Node, error) { return json.Extract(filename, src) }
+	case ".cue":
+		parsefn = func(filename string, src []byte) (ast.Node, error) { return parser.ParseFile(filename, src) }
+	default:
+		return fields, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := parsefn(filepath.Base(path), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	var decls []ast.Decl
+	switch n := node.(type) {
+	case *ast.File:
+		decls = n.Decls
+	case *ast.StructLit:
+		decls = n.Elts
+	}
+
+	for _, decl := range decls {
+		if field, ok := decl.(*ast.Field); ok {
+			if name, _, err := ast.LabelName(field.Label); err == nil {
+				fields[name] = true
+			}
+		}
+	}
+	return fields, nil
+}
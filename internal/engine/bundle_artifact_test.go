@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScanBundleArtifact(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"app.bundle.cue": `
+bundle: {
+	apiVersion: "v1alpha1"
+	name: "app"
+}
+`,
+		"secrets/values.yaml": `
+bundle:
+  instances:
+    app:
+      values:
+        token: secret
+`,
+		"app.runtime.cue": `
+runtime: {
+	apiVersion: "v1alpha1"
+	name: "app"
+	values: [{
+		query: "k8s:v1:Namespace:kube-system"
+		for: "ID": "obj.metadata.uid"
+	}] @timoni(runtime:string:ID)
+}
+`,
+		"values.json":          `{"runtime": {"name": "app"}}`,
+		"README.md":            "# app",
+		"schema.cue":           `#Config: {}`,
+		"cue.mod/module.cue":   `module: "example.com/app"`,
+		"cue.mod/bundle.cue":   `bundle: {}`,
+		"nested/cue.mod/x.cue": `bundle: {}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	}
+
+	result, err := ScanBundleArtifact(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.ModuleRoot).To(BeTrue())
+	g.Expect(result.Bundles).To(Equal([]string{
+		filepath.Join(dir, "app.bundle.cue"),
+		filepath.Join(dir, "secrets/values.yaml"),
+	}))
+	g.Expect(result.Runtimes).To(Equal([]string{
+		filepath.Join(dir, "app.runtime.cue"),
+		filepath.Join(dir, "values.json"),
+	}))
+
+	t.Run("fails without bundle definitions", func(t *testing.T) {
+		g := NewWithT(t)
+		empty := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(empty, "runtime.cue"), []byte(`runtime: {}`), 0o644)).To(Succeed())
+
+		_, err := ScanBundleArtifact(empty)
+		g.Expect(err).To(MatchError(ContainSubstring("no bundle definitions found")))
+	})
+
+	t.Run("fails on invalid files", func(t *testing.T) {
+		g := NewWithT(t)
+		invalid := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(invalid, "bundle.cue"), []byte(`bundle: {`), 0o644)).To(Succeed())
+
+		_, err := ScanBundleArtifact(invalid)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to parse bundle.cue")))
+	})
+}
@@ -31,6 +31,7 @@ import (
 	"cuelang.org/go/encoding/yaml"
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
 )
 
 // BundleBuilder compiles CUE definitions to Go Bundle objects.
@@ -43,6 +44,7 @@ type BundleBuilder struct {
 	workspacesSources map[string]map[string][]byte
 	mapSourceToOrigin map[string]string
 	injector          *RuntimeInjector
+	decrypter         *sops.Decrypter
 }
 
 // NewBundleBuilder creates a BundleBuilder for the given module and package.
@@ -76,6 +78,12 @@ func (b *BundleBuilder) SetWorkdir(dir string) {
 	b.workdir = dir
 }
 
+// SetDecrypter sets the decrypter used for the YAML and JSON bundle
+// files encrypted with SOPS. The decrypted data is kept in memory.
+func (b *BundleBuilder) SetDecrypter(decrypter *sops.Decrypter) {
+	b.decrypter = decrypter
+}
+
 // InitWorkspace loads the bundle definitions into the in-memory workspace
 // identified by the given name, sets the bundle schema, and then it injects
 // the runtime values based on @timoni() attributes. Nothing is written to
@@ -92,8 +100,18 @@ func (b *BundleBuilder) InitWorkspace(workspace string, runtimeValues map[string
 			return fmt.Errorf("failed to read %s: %w", fn, err)
 		}
 
+		ext := filepath.Ext(fn)
+		if b.decrypter != nil && (ext == ".json" || ext == ".yaml" || ext == ".yml") && sops.IsEncrypted(content) {
+			content, err = b.decrypter.Decrypt(content)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", fn, err)
+			}
+			// the decrypted document is always YAML
+			ext = ".yaml"
+		}
+
 		var parsefn func(string, []byte) (ast.Node, error)
-		switch ext := filepath.Ext(fn); ext {
+		switch ext {
 		case ".yaml", ".yml":
 			parsefn = func(filename string, src []byte) (ast.Node, error) { return yaml.Extract(filename, src) }
 		case ".json":
@@ -175,11 +193,19 @@ func (b *BundleBuilder) Build(workspace string) (cue.Value, error) {
 	return v, nil
 }
 
+// OriginFile returns the path of the bundle file from which the given
+// workspace file was loaded. It returns false if the file is not part
+// of a workspace.
+func (b *BundleBuilder) OriginFile(file string) (string, bool) {
+	origin, ok := b.mapSourceToOrigin[file]
+	return origin, ok
+}
+
 func (b *BundleBuilder) getInstanceURL(v cue.Value) string {
 	url, _ := v.String()
 	if path := strings.TrimPrefix(url, apiv1.LocalPrefix); IsFileURL(url) && !filepath.IsAbs(path) {
 		source := v.Pos().Filename()
-		if origin, ok := b.mapSourceToOrigin[source]; ok {
+		if origin, ok := b.OriginFile(source); ok {
 			source = origin
 		}
 		url = apiv1.LocalPrefix + filepath.Clean(filepath.Join(filepath.Dir(source), path))
@@ -49,6 +49,13 @@ type ConfigField struct {
 	Required bool
 	// NoDoc is true for fields commented with `// +nodoc`.
 	NoDoc bool
+	// Unresolved is true for fields that fail to evaluate without the
+	// values injected by Timoni at apply time, such as the labels set
+	// from the module version.
+	Unresolved bool
+	// Value is the field value, used to compare the schemas of two
+	// module versions.
+	Value cue.Value
 }
 
 // Key returns the field path in the `a: b: c:` form used by the config table,
@@ -57,6 +64,17 @@ func (f ConfigField) Key() string {
 	return strings.Join(plainLabels(f.Path), ": ") + ":"
 }
 
+// MustSet returns true if the field has to be set in the values, either
+// because it is declared with `!` or because it is a regular field without
+// a default. Structs and lists are completed by CUE as empty and are never
+// required unless marked with `!`.
+func (f ConfigField) MustSet() bool {
+	kind := f.Value.IncompleteKind()
+	return f.Required || (!f.Optional && f.Default == "" && !f.Unresolved &&
+		kind != cue.StructKind && kind != cue.ListKind &&
+		!strings.HasPrefix(f.Type, "{") && !strings.HasPrefix(f.Type, "["))
+}
+
 // plainLabels returns the path labels without the `?` and `!` markers.
 func plainLabels(p cue.Path) []string {
 	selectors := p.Selectors()
@@ -68,9 +86,11 @@ func plainLabels(p cue.Path) []string {
 }
 
 // injectedConfigPaths are the #Config fields set by Timoni at apply time
-// from the instance name, namespace, module version and Kubernetes version.
+// from the instance name, namespace, module version and Kubernetes version
+// and API versions.
 var injectedConfigPaths = []string{
 	"kubeVersion",
+	"apiVersions",
 	"clusterVersion",
 	"moduleVersion",
 	"metadata.name",
@@ -121,12 +141,14 @@ func (b *ModuleBuilder) GetConfigDoc(value cue.Value) ([]ConfigField, error) {
 
 			doc, noDoc := b.fieldDoc(fv)
 			f := ConfigField{
-				Path:     fieldPath,
-				Type:     typeExpr(fv, local),
-				Doc:      doc,
-				Optional: optional,
-				Required: required,
-				NoDoc:    noDoc,
+				Path:       fieldPath,
+				Type:       typeExpr(fv, local),
+				Doc:        doc,
+				Optional:   optional,
+				Required:   required,
+				NoDoc:      noDoc,
+				Unresolved: fv.Err() != nil,
+				Value:      fv,
 			}
 			switch {
 			case hasDef:
@@ -434,6 +456,22 @@ func cueValue(v cue.Value) string {
 // #Config, preserving the field documentation, the optional and
 // required markers, the defaults and the type constraints.
 func FormatConfigCUE(fields []ConfigField) (string, error) {
+	root := newConfigTree(fields)
+
+	var sb strings.Builder
+	sb.WriteString("#Config: {\n")
+	root.write(&sb)
+	sb.WriteString("}\n")
+
+	out, err := format.Source([]byte(sb.String()), format.Simplify())
+	if err != nil {
+		return "", fmt.Errorf("formatting the config failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// newConfigTree nests the config fields by path, keeping the field order.
+func newConfigTree(fields []ConfigField) *configNode {
 	root := &configNode{}
 	for _, f := range fields {
 		n := root
@@ -453,17 +491,7 @@ func FormatConfigCUE(fields []ConfigField) (string, error) {
 		field := f
 		n.field = &field
 	}
-
-	var sb strings.Builder
-	sb.WriteString("#Config: {\n")
-	root.write(&sb)
-	sb.WriteString("}\n")
-
-	out, err := format.Source([]byte(sb.String()), format.Simplify())
-	if err != nil {
-		return "", fmt.Errorf("formatting the config failed: %w", err)
-	}
-	return string(out), nil
+	return root
 }
 
 // configNode is a tree node used to nest the config fields by path.
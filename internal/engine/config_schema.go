@@ -0,0 +1,531 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+const (
+	// JSONSchemaDialect is the JSON Schema draft of the config schema.
+	JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+	// OpenAPIVersion is the OpenAPI version of the config schema document.
+	OpenAPIVersion = "3.0.3"
+
+	// maxConfigSchemaDepth bounds the nesting of the config schema, so that
+	// recursive definitions do not expand indefinitely.
+	maxConfigSchemaDepth = 32
+)
+
+// GetConfigSchema converts the module's #Config schema into a JSON Schema,
+// with the field types, defaults, enums, patterns, bounds and
+// documentation. Fields that are required by the schema and have no default
+// are listed as required, and closed structs disallow additional properties.
+// The fields injected by Timoni at apply time are skipped, and the concrete
+// values set by the module's values.cue are rendered as defaults, since
+// the user-supplied values take precedence over them.
+func (b *ModuleBuilder) GetConfigSchema(value cue.Value) (map[string]any, error) {
+	root := value.LookupPath(cue.ParsePath(apiv1.ValuesSelector.String()))
+	if root.Err() != nil {
+		return nil, fmt.Errorf("lookup %s failed: %w", apiv1.ValuesSelector, root.Err())
+	}
+
+	schema, err := b.configSchema(root, cue.MakePath(), 0)
+	if err != nil {
+		return nil, err
+	}
+	delete(schema, "default")
+	return schema, nil
+}
+
+// ConfigJSONSchema wraps the config schema in a JSON Schema document
+// for the values files, which hold the config under the values field.
+func ConfigJSONSchema(schema map[string]any, title string) map[string]any {
+	return map[string]any{
+		"$schema": JSONSchemaDialect,
+		"title":   title,
+		"type":    "object",
+		"properties": map[string]any{
+			"values": schema,
+		},
+	}
+}
+
+// configSchema returns the JSON Schema of a config value at the given path.
+func (b *ModuleBuilder) configSchema(v cue.Value, path cue.Path, depth int) (map[string]any, error) {
+	s := map[string]any{}
+	if depth > maxConfigSchemaDepth {
+		return s, nil
+	}
+
+	kind := v.IncompleteKind()
+	if kind == cue.BottomKind {
+		kind = conjunctsKind(v)
+	}
+	if types := schemaTypes(kind); len(types) == 1 {
+		s["type"] = types[0]
+	} else if len(types) > 1 {
+		s["type"] = types
+	}
+	if kind == cue.BytesKind {
+		s["contentEncoding"] = "base64"
+	}
+
+	if err := b.schemaConstraints(v, s, path, depth); err != nil {
+		return nil, err
+	}
+
+	d, ok := v.Default()
+	if !ok {
+		d = v
+	}
+	if d.IsConcrete() && d.IncompleteKind() != cue.StructKind {
+		if x, err := decodeValue(d); err == nil {
+			s["default"] = x
+		}
+	}
+
+	// The structure of a disjunction of structs or lists is described
+	// by its anyOf schemas.
+	if _, ok := s["anyOf"]; ok {
+		return s, nil
+	}
+
+	switch kind {
+	case cue.StructKind:
+		if err := b.objectSchema(v, s, path, depth); err != nil {
+			return nil, err
+		}
+	case cue.ListKind:
+		if elem, ok := listElem(v); ok {
+			items, err := b.configSchema(elem, cue.MakePath(append(slices.Clone(path.Selectors()), cue.AnyIndex)...), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			delete(items, "default")
+			s["items"] = items
+		}
+	}
+	return s, nil
+}
+
+// objectSchema sets the properties, the required fields and the
+// additional properties of a struct schema.
+func (b *ModuleBuilder) objectSchema(v cue.Value, s map[string]any, path cue.Path, depth int) error {
+	iter, err := v.Fields(cue.Optional(true), cue.Hidden(false), cue.Definitions(false))
+	if err != nil {
+		return err
+	}
+
+	properties := map[string]any{}
+	var required []string
+	for iter.Next() {
+		fv := iter.Value()
+		sel := iter.Selector()
+		fieldPath := cue.MakePath(append(slices.Clone(path.Selectors()), sel)...)
+		if slices.Contains(injectedConfigPaths, strings.Join(plainLabels(fieldPath), ".")) {
+			continue
+		}
+
+		fs, err := b.configSchema(fv, fieldPath, depth+1)
+		if err != nil {
+			return err
+		}
+		if doc, _ := b.fieldDoc(fv); doc != "" {
+			fs["description"] = doc
+		}
+
+		label := sel.Unquoted()
+		properties[label] = fs
+
+		// A field must be set in the values when it is marked with `!` and
+		// not set by the module, or when CUE can not complete it from a
+		// default or a concrete value. The fields computed from the injected
+		// ones fail to evaluate without them, and are completed by Timoni.
+		_, hasDef := fv.Default()
+		switch sel.ConstraintType() {
+		case cue.OptionalConstraint:
+		case cue.RequiredConstraint:
+			if !hasDef {
+				required = append(required, label)
+			}
+		default:
+			if !hasDef && !fv.IsConcrete() && fv.Err() == nil {
+				required = append(required, label)
+			}
+		}
+	}
+
+	if len(properties) > 0 {
+		s["properties"] = properties
+	}
+	if len(required) > 0 {
+		slices.Sort(required)
+		s["required"] = required
+	}
+
+	if pattern := v.LookupPath(cue.MakePath(cue.AnyString)); pattern.Exists() {
+		ps, err := b.configSchema(pattern, cue.MakePath(append(slices.Clone(path.Selectors()), cue.AnyString)...), depth+1)
+		if err != nil {
+			return err
+		}
+		delete(ps, "default")
+		s["additionalProperties"] = ps
+	} else if v.IsClosed() && !v.Allows(cue.AnyString) {
+		s["additionalProperties"] = false
+	}
+	return nil
+}
+
+// schemaConstraints sets the enums, patterns, bounds and length limits
+// found in the value's expression, following references to definitions.
+func (b *ModuleBuilder) schemaConstraints(v cue.Value, s map[string]any, path cue.Path, depth int) error {
+	op, args := v.Expr()
+	switch op {
+	case cue.NoOp:
+		if arg, ok := reducedDisjunct(args); ok {
+			return b.schemaConstraints(arg, s, path, depth)
+		}
+	case cue.AndOp:
+		for _, a := range args {
+			if err := b.schemaConstraints(a, s, path, depth); err != nil {
+				return err
+			}
+		}
+	case cue.OrOp:
+		return b.disjunctionSchema(v, s, path, depth)
+	case cue.SelectorOp:
+		if ref, ok := resolveSelector(args); ok {
+			return b.schemaConstraints(ref, s, path, depth)
+		}
+	case cue.RegexMatchOp:
+		if p, err := args[0].String(); err == nil {
+			s["pattern"] = p
+		}
+	case cue.NotRegexMatchOp:
+		if p, err := args[0].String(); err == nil {
+			s["not"] = map[string]any{"pattern": p}
+		}
+	case cue.NotEqualOp:
+		if x, err := decodeValue(args[0]); err == nil {
+			s["not"] = map[string]any{"const": x}
+		}
+	case cue.LessThanOp, cue.LessThanEqualOp, cue.GreaterThanOp, cue.GreaterThanEqualOp:
+		if args[0].IncompleteKind()&cue.NumberKind == 0 {
+			return nil
+		}
+		x, err := decodeValue(args[0])
+		if err != nil {
+			return nil
+		}
+		s[map[cue.Op]string{
+			cue.LessThanOp:         "exclusiveMaximum",
+			cue.LessThanEqualOp:    "maximum",
+			cue.GreaterThanOp:      "exclusiveMinimum",
+			cue.GreaterThanEqualOp: "minimum",
+		}[op]] = x
+	case cue.CallOp:
+		keyword, ok := map[string]string{
+			"strings.MinRunes": "minLength",
+			"strings.MaxRunes": "maxLength",
+			"list.MinItems":    "minItems",
+			"list.MaxItems":    "maxItems",
+		}[fmt.Sprint(args[0])]
+		if ok && len(args) == 2 {
+			if n, err := args[1].Int64(); err == nil {
+				s[keyword] = n
+			}
+		}
+		if fmt.Sprint(args[0]) == "list.UniqueItems" {
+			s["uniqueItems"] = true
+		}
+	}
+	return nil
+}
+
+// disjunctionSchema renders a disjunction as an enum when all its values
+// are concrete, and as anyOf when it mixes values and types. The concrete
+// values covered by a type of the same kind, such as the default in
+// `*1 | int`, and the null values covered by the schema type are dropped.
+func (b *ModuleBuilder) disjunctionSchema(v cue.Value, s map[string]any, path cue.Path, depth int) error {
+	disjuncts := flattenDisjuncts(v)
+
+	var types cue.Kind
+	for _, d := range disjuncts {
+		if !d.IsConcrete() {
+			types |= d.IncompleteKind()
+		}
+	}
+	disjuncts = slices.DeleteFunc(disjuncts, func(d cue.Value) bool {
+		kind := d.IncompleteKind()
+		return d.IsConcrete() && (kind&types != 0 || (kind == cue.NullKind && types != 0))
+	})
+
+	var enum []any
+	concrete := true
+	for _, d := range disjuncts {
+		if !d.IsConcrete() || d.IncompleteKind()&(cue.StructKind|cue.ListKind) != 0 {
+			concrete = false
+			break
+		}
+		x, err := decodeValue(d)
+		if err != nil {
+			return nil
+		}
+		if !slices.ContainsFunc(enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(x) }) {
+			enum = append(enum, x)
+		}
+	}
+
+	switch {
+	case len(disjuncts) == 0:
+	case concrete:
+		s["enum"] = enum
+	case len(disjuncts) == 1:
+		return b.schemaConstraints(disjuncts[0], s, path, depth)
+	default:
+		anyOf := make([]any, 0, len(disjuncts))
+		for _, d := range disjuncts {
+			ds, err := b.configSchema(d, path, depth+1)
+			if err != nil {
+				return err
+			}
+			delete(ds, "default")
+			if d.IsConcrete() {
+				x, err := decodeValue(d)
+				if err != nil {
+					return nil
+				}
+				ds = map[string]any{"const": x}
+			}
+			anyOf = append(anyOf, ds)
+		}
+		s["anyOf"] = anyOf
+	}
+	return nil
+}
+
+// flattenDisjuncts returns the values of a disjunction, expanding the
+// nested disjunctions and the references to disjunctions.
+func flattenDisjuncts(v cue.Value) []cue.Value {
+	op, args := v.Expr()
+	switch op {
+	case cue.OrOp:
+		var out []cue.Value
+		for _, a := range args {
+			out = append(out, flattenDisjuncts(a)...)
+		}
+		return out
+	case cue.SelectorOp:
+		if ref, ok := resolveSelector(args); ok {
+			if refOp, _ := ref.Expr(); refOp == cue.OrOp {
+				return flattenDisjuncts(ref)
+			}
+		}
+	}
+	return []cue.Value{v}
+}
+
+// reducedDisjunct returns the remaining disjunct of a disjunction whose
+// default is subsumed by another disjunct, such as `*1 | int & >0`, which
+// CUE reports as a single expression instead of a disjunction.
+func reducedDisjunct(args []cue.Value) (cue.Value, bool) {
+	if len(args) != 1 {
+		return cue.Value{}, false
+	}
+	if op, _ := args[0].Expr(); op == cue.NoOp {
+		return cue.Value{}, false
+	}
+	return args[0], true
+}
+
+// conjunctsKind returns the kind allowed by all the conjuncts of a value
+// that failed to evaluate, such as an empty list constrained by
+// list.MinItems, so that its schema keeps the type.
+func conjunctsKind(v cue.Value) cue.Kind {
+	op, args := v.Expr()
+	if op != cue.AndOp {
+		return cue.BottomKind
+	}
+	kind := cue.TopKind
+	for _, a := range args {
+		kind &= a.IncompleteKind()
+	}
+	return kind
+}
+
+// resolveSelector looks up the value referenced by a selector expression,
+// given as the expression arguments: the parent value and the label.
+func resolveSelector(args []cue.Value) (cue.Value, bool) {
+	if len(args) != 2 {
+		return cue.Value{}, false
+	}
+	label, err := args[1].String()
+	if err != nil {
+		return cue.Value{}, false
+	}
+	ref := args[0].LookupPath(cue.ParsePath(label))
+	return ref, ref.Exists()
+}
+
+// listElem returns the element type of a list, looking it up in the
+// list conjuncts and disjuncts when the list is constrained.
+func listElem(v cue.Value) (cue.Value, bool) {
+	if elem := v.LookupPath(cue.MakePath(cue.AnyIndex)); elem.Exists() {
+		return elem, true
+	}
+	op, args := v.Expr()
+	if arg, ok := reducedDisjunct(args); ok && op == cue.NoOp {
+		return listElem(arg)
+	}
+	if op != cue.AndOp && op != cue.OrOp {
+		return cue.Value{}, false
+	}
+	for _, a := range args {
+		if elem, ok := listElem(a); ok {
+			return elem, true
+		}
+	}
+	return cue.Value{}, false
+}
+
+// schemaTypes maps a CUE kind to the JSON Schema types.
+func schemaTypes(kind cue.Kind) []string {
+	if kind == cue.TopKind {
+		return nil
+	}
+	var types []string
+	for _, k := range []struct {
+		kind cue.Kind
+		name string
+	}{
+		{cue.NullKind, "null"},
+		{cue.BoolKind, "boolean"},
+		{cue.StringKind | cue.BytesKind, "string"},
+		{cue.StructKind, "object"},
+		{cue.ListKind, "array"},
+	} {
+		if kind&k.kind != 0 {
+			types = append(types, k.name)
+		}
+	}
+	switch {
+	case kind&cue.FloatKind != 0:
+		types = append(types, "number")
+	case kind&cue.IntKind != 0:
+		types = append(types, "integer")
+	}
+	return types
+}
+
+// decodeValue decodes a concrete CUE value to its JSON representation.
+func decodeValue(v cue.Value) (any, error) {
+	var x any
+	if err := v.Decode(&x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ConfigOpenAPI wraps the config JSON Schema in an OpenAPI 3.0 document
+// under components.schemas.Values, converting the JSON Schema keywords
+// not supported by OpenAPI 3.0, such as type arrays, null and const.
+func ConfigOpenAPI(schema map[string]any, title, version string) map[string]any {
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":   title,
+			"version": version,
+		},
+		"paths": map[string]any{},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Values": openAPISchema(schema),
+			},
+		},
+	}
+}
+
+// openAPISchema converts a JSON Schema to an OpenAPI 3.0 schema object.
+func openAPISchema(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		switch k {
+		case "properties":
+			props := map[string]any{}
+			for name, p := range v.(map[string]any) {
+				props[name] = openAPISchema(p.(map[string]any))
+			}
+			out[k] = props
+		case "items", "not":
+			out[k] = openAPISchema(v.(map[string]any))
+		case "additionalProperties":
+			if p, ok := v.(map[string]any); ok {
+				out[k] = openAPISchema(p)
+			} else {
+				out[k] = v
+			}
+		case "anyOf":
+			var anyOf []any
+			for _, p := range v.([]any) {
+				anyOf = append(anyOf, openAPISchema(p.(map[string]any)))
+			}
+			out[k] = anyOf
+		case "const":
+			out["enum"] = []any{v}
+		case "contentEncoding":
+			out["format"] = "byte"
+		case "exclusiveMinimum":
+			out["minimum"] = v
+			out[k] = true
+		case "exclusiveMaximum":
+			out["maximum"] = v
+			out[k] = true
+		default:
+			out[k] = v
+		}
+	}
+
+	// OpenAPI 3.0 allows a single type, with null expressed as nullable
+	// and the other types as anyOf, unless anyOf already describes them.
+	if types, ok := schema["type"].([]string); ok {
+		delete(out, "type")
+		if i := slices.Index(types, "null"); i >= 0 {
+			out["nullable"] = true
+			types = slices.Delete(slices.Clone(types), i, i+1)
+		}
+		_, hasAnyOf := out["anyOf"]
+		switch {
+		case len(types) == 1:
+			out["type"] = types[0]
+		case len(types) > 1 && !hasAnyOf:
+			anyOf := make([]any, 0, len(types))
+			for _, t := range types {
+				anyOf = append(anyOf, map[string]any{"type": t})
+			}
+			out["anyOf"] = anyOf
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigSchema_GetConfigSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name:   "string with default",
+			config: `domain: *"example.com" | string`,
+			want:   `{"default":"example.com","type":"string"}`,
+		},
+		{
+			name:   "enum",
+			config: `logLevel?: *"info" | "debug" | "info"`,
+			want:   `{"default":"info","enum":["debug","info"],"type":"string"}`,
+		},
+		{
+			name:   "integer bounds",
+			config: `port: *8080 | int & >0 & <=65535`,
+			want:   `{"default":8080,"exclusiveMinimum":0,"maximum":65535,"type":"integer"}`,
+		},
+		{
+			name:   "string pattern and length",
+			config: `name: string & =~"^[a-z]+$" & strings.MaxRunes(63)`,
+			want:   `{"maxLength":63,"pattern":"^[a-z]+$","type":"string"}`,
+		},
+		{
+			name:   "nullable",
+			config: `token: *null | string`,
+			want:   `{"default":null,"type":["null","string"]}`,
+		},
+		{
+			name:   "value or type",
+			config: `replicas: *"auto" | int`,
+			want:   `{"anyOf":[{"const":"auto"},{"type":"integer"}],"default":"auto","type":["string","integer"]}`,
+		},
+		{
+			name:   "list",
+			config: `hosts: [...string] & list.MinItems(1)`,
+			want:   `{"items":{"type":"string"},"minItems":1,"type":"array"}`,
+		},
+		{
+			name:   "map",
+			config: `annotations?: {[string]: string}`,
+			want:   `{"additionalProperties":{"type":"string"},"type":"object"}`,
+		},
+		{
+			name:   "closed struct",
+			config: `resources: {limits?: cpu?: string, requests: memory!: string}`,
+			want: `{"additionalProperties":false,"properties":{` +
+				`"limits":{"additionalProperties":false,"properties":{"cpu":{"type":"string"}},"type":"object"},` +
+				`"requests":{"additionalProperties":false,"properties":{"memory":{"type":"string"}},"required":["memory"],"type":"object"}` +
+				`},"type":"object"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+			src := "#Config: {" + tt.config + "}\nvalues: #Config\n"
+			for _, pkg := range []string{"list", "strings"} {
+				if strings.Contains(tt.config, pkg+".") {
+					src = fmt.Sprintf("import %q\n%s", pkg, src)
+				}
+			}
+			v := ctx.CompileString(src)
+			g.Expect(v.Err()).ToNot(HaveOccurred())
+
+			b := NewModuleBuilder(ctx, "test", "default", t.TempDir(), defaultPackage)
+			schema, err := b.GetConfigSchema(v)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			properties := schema["properties"].(map[string]any)
+			g.Expect(properties).To(HaveLen(1))
+			for _, field := range properties {
+				out, err := json.Marshal(field)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(string(out)).To(MatchJSON(tt.want))
+			}
+		})
+	}
+}
+
+func TestConfigSchema_Module(t *testing.T) {
+	g := NewWithT(t)
+	modPath, err := filepath.Abs("../../cmd/timoni/testdata/module")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	b := NewModuleBuilder(cuecontext.New(), "test", "default", modPath, defaultPackage)
+	g.Expect(b.OverlaySchemaFile()).To(Succeed())
+	v, err := b.Build()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	schema, err := b.GetConfigSchema(v)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(schema).To(HaveKeyWithValue("additionalProperties", false))
+
+	properties := schema["properties"].(map[string]any)
+	g.Expect(properties).ToNot(HaveKey("kubeVersion"))
+	g.Expect(properties).ToNot(HaveKey("moduleVersion"))
+	g.Expect(properties).ToNot(HaveKey("clusterVersion"))
+	g.Expect(properties["logLevel"]).To(HaveKeyWithValue("description", "Log level, info by default"))
+	g.Expect(properties["team"]).To(HaveKeyWithValue("default", "test"))
+
+	metadata := properties["metadata"].(map[string]any)["properties"].(map[string]any)
+	g.Expect(metadata).ToNot(HaveKey("name"))
+	g.Expect(metadata).ToNot(HaveKey("namespace"))
+
+	// The labels computed from the injected fields are not required.
+	labels := metadata["labels"].(map[string]any)
+	g.Expect(labels).ToNot(HaveKey("required"))
+
+	doc := ConfigJSONSchema(schema, "test")
+	g.Expect(doc).To(HaveKeyWithValue("$schema", JSONSchemaDialect))
+	g.Expect(doc).To(HaveKeyWithValue("properties", HaveKeyWithValue("values", schema)))
+}
+
+func TestConfigSchema_ConfigOpenAPI(t *testing.T) {
+	g := NewWithT(t)
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"token":    map[string]any{"type": []string{"null", "string"}},
+			"port":     map[string]any{"type": "integer", "exclusiveMinimum": 0},
+			"mode":     map[string]any{"const": "auto"},
+			"replicas": map[string]any{"type": []string{"string", "integer"}},
+		},
+	}
+
+	out, err := json.Marshal(ConfigOpenAPI(schema, "app", "1.0.0"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(out)).To(MatchJSON(`{
+  "openapi": "3.0.3",
+  "info": {"title": "app", "version": "1.0.0"},
+  "paths": {},
+  "components": {"schemas": {"Values": {
+    "type": "object",
+    "properties": {
+      "token": {"type": "string", "nullable": true},
+      "port": {"type": "integer", "minimum": 0, "exclusiveMinimum": true},
+      "mode": {"enum": ["auto"]},
+      "replicas": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+    }
+  }}}
+}`))
+}
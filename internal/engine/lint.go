@@ -0,0 +1,379 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/token"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// LintRule is a built-in best-practice check of the rendered objects.
+type LintRule struct {
+	// ID identifies the rule in reports and in the ignore annotation.
+	ID string
+
+	// Severity is either error or warning.
+	Severity string
+
+	// Description summarises what the rule checks.
+	Description string
+
+	// check returns a message for every finding in the object,
+	// the objects holds the whole set for cross-object rules.
+	check func(object *unstructured.Unstructured, objects []*unstructured.Unstructured) []string
+}
+
+// LintResult holds a finding of a lint rule.
+type LintResult struct {
+	// Rule is the lint rule that reported the finding.
+	Rule *LintRule
+
+	// Object is the rendered object the finding refers to.
+	Object *unstructured.Unstructured
+
+	// Message describes the finding.
+	Message string
+
+	// Pos is the position of the object in the module source,
+	// set with SetPositions.
+	Pos token.Pos
+}
+
+// Failed returns true if the finding must fail the run.
+func (r LintResult) Failed() bool {
+	return r.Rule.Severity == apiv1.LintSeverityError
+}
+
+// String returns the finding message prefixed with the rule ID
+// e.g. '[host-path-volume] message'.
+func (r LintResult) String() string {
+	return fmt.Sprintf("[%s] %s", r.Rule.ID, r.Message)
+}
+
+var lintRules = []*LintRule{
+	{
+		ID:          "container-probes",
+		Severity:    apiv1.LintSeverityWarning,
+		Description: "Containers of long-running workloads should define readiness and liveness probes.",
+		check:       checkContainerProbes,
+	},
+	{
+		ID:          "container-resources",
+		Severity:    apiv1.LintSeverityWarning,
+		Description: "Containers should define resource requests and limits.",
+		check:       checkContainerResources,
+	},
+	{
+		ID:          "host-path-volume",
+		Severity:    apiv1.LintSeverityError,
+		Description: "Pods should not mount hostPath volumes.",
+		check:       checkHostPathVolumes,
+	},
+	{
+		ID:          "image-latest-tag",
+		Severity:    apiv1.LintSeverityWarning,
+		Description: "Container images should be pinned to a tag other than latest or to a digest.",
+		check:       checkImageTags,
+	},
+	{
+		ID:          "service-selector",
+		Severity:    apiv1.LintSeverityError,
+		Description: "Service selectors should match the pod labels of a rendered workload.",
+		check:       checkServiceSelector,
+	},
+}
+
+// LintRules returns the built-in lint rules sorted by ID.
+func LintRules() []*LintRule {
+	return lintRules
+}
+
+// Lint runs the built-in rules against the objects and returns the findings
+// sorted by object in the input order, then by rule ID. The rules listed in
+// the object's ignore annotation are skipped.
+func Lint(objects []*unstructured.Unstructured) []LintResult {
+	var results []LintResult
+	for _, object := range objects {
+		ignored := lintIgnoredRules(object)
+		for _, rule := range lintRules {
+			if slices.Contains(ignored, rule.ID) {
+				continue
+			}
+			for _, msg := range rule.check(object, objects) {
+				results = append(results, LintResult{Rule: rule, Object: object, Message: msg})
+			}
+		}
+	}
+	return results
+}
+
+// SetPositions sets the source position of the findings
+// from the positions returned by GetObjectPositions.
+func SetPositions(results []LintResult, positions map[string]token.Pos) {
+	for i := range results {
+		results[i].Pos = positions[ssautil.FmtUnstructured(results[i].Object)]
+	}
+}
+
+// GetObjectPositions returns the source position of the objects found under
+// 'timoni: apply:', keyed by the object's kind, namespace and name. The
+// position is the one of the object's kind field, falling back to the
+// position of the apply list item when the kind is set in the cue.mod
+// definitions.
+func (b *ModuleBuilder) GetObjectPositions(value cue.Value) map[string]token.Pos {
+	positions := make(map[string]token.Pos)
+
+	steps := value.LookupPath(cue.ParsePath(apiv1.ApplySelector.String()))
+	iter, err := steps.Fields(cue.Concrete(true), cue.Final())
+	if err != nil {
+		return positions
+	}
+	for iter.Next() {
+		items, err := iter.Value().List()
+		if err != nil {
+			continue
+		}
+		for items.Next() {
+			item := items.Value()
+			pos := item.LookupPath(cue.ParsePath("kind")).Pos()
+			if !pos.IsValid() || strings.Contains(filepath.ToSlash(pos.Filename()), "/cue.mod/") {
+				pos = item.Pos()
+			}
+
+			objects, err := decodeItem(item)
+			if err != nil {
+				continue
+			}
+			for _, obj := range objects {
+				positions[ssautil.FmtUnstructured(obj)] = pos
+			}
+		}
+	}
+	return positions
+}
+
+func lintIgnoredRules(object *unstructured.Unstructured) []string {
+	value, ok := object.GetAnnotations()[apiv1.LintIgnoreAnnotation]
+	if !ok {
+		return nil
+	}
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// podTemplate returns the pod spec and labels of the workload kinds,
+// including the pods created by Jobs and CronJobs.
+func podTemplate(object *unstructured.Unstructured) (spec map[string]any, labels map[string]string, ok bool) {
+	var path []string
+	switch object.GetKind() {
+	case "Pod":
+		return nestedMap(object.Object, "spec"), object.GetLabels(), true
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		path = []string{"spec", "template"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template"}
+	default:
+		return nil, nil, false
+	}
+
+	template := nestedMap(object.Object, path...)
+	labels, _, _ = unstructured.NestedStringMap(template, "metadata", "labels")
+	return nestedMap(template, "spec"), labels, true
+}
+
+// isLongRunning returns true for the workload kinds whose pods are
+// expected to run continuously and serve traffic.
+func isLongRunning(object *unstructured.Unstructured) bool {
+	switch object.GetKind() {
+	case "Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		return true
+	}
+	return false
+}
+
+func nestedMap(object map[string]any, fields ...string) map[string]any {
+	m, _, _ := unstructured.NestedMap(object, fields...)
+	return m
+}
+
+// podContainers returns the containers, and optionally the init containers,
+// of the pod spec.
+func podContainers(spec map[string]any, withInit bool) []map[string]any {
+	fields := []string{"containers"}
+	if withInit {
+		fields = append(fields, "initContainers")
+	}
+
+	var containers []map[string]any
+	for _, field := range fields {
+		list, _, _ := unstructured.NestedSlice(spec, field)
+		for _, item := range list {
+			if c, ok := item.(map[string]any); ok {
+				containers = append(containers, c)
+			}
+		}
+	}
+	return containers
+}
+
+func containerName(container map[string]any) string {
+	name, _ := container["name"].(string)
+	return name
+}
+
+func checkContainerProbes(object *unstructured.Unstructured, _ []*unstructured.Unstructured) []string {
+	spec, _, ok := podTemplate(object)
+	if !ok || !isLongRunning(object) {
+		return nil
+	}
+
+	var msgs []string
+	for _, c := range podContainers(spec, false) {
+		var missing []string
+		for _, probe := range []string{"readinessProbe", "livenessProbe"} {
+			if _, ok := c[probe]; !ok {
+				missing = append(missing, probe)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf("container %q does not define %s",
+				containerName(c), strings.Join(missing, ", ")))
+		}
+	}
+	return msgs
+}
+
+func checkContainerResources(object *unstructured.Unstructured, _ []*unstructured.Unstructured) []string {
+	spec, _, ok := podTemplate(object)
+	if !ok {
+		return nil
+	}
+
+	var msgs []string
+	for _, c := range podContainers(spec, true) {
+		var missing []string
+		for _, field := range []string{"requests", "limits"} {
+			if len(nestedMap(c, "resources", field)) == 0 {
+				missing = append(missing, "resources."+field)
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf("container %q does not define %s",
+				containerName(c), strings.Join(missing, ", ")))
+		}
+	}
+	return msgs
+}
+
+func checkHostPathVolumes(object *unstructured.Unstructured, _ []*unstructured.Unstructured) []string {
+	spec, _, ok := podTemplate(object)
+	if !ok {
+		return nil
+	}
+
+	var msgs []string
+	volumes, _, _ := unstructured.NestedSlice(spec, "volumes")
+	for _, item := range volumes {
+		v, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, ok := v["hostPath"]; ok {
+			path, _, _ := unstructured.NestedString(v, "hostPath", "path")
+			msgs = append(msgs, fmt.Sprintf("volume %q mounts the host path %s", v["name"], path))
+		}
+	}
+	return msgs
+}
+
+func checkImageTags(object *unstructured.Unstructured, _ []*unstructured.Unstructured) []string {
+	spec, _, ok := podTemplate(object)
+	if !ok {
+		return nil
+	}
+
+	var msgs []string
+	for _, c := range podContainers(spec, true) {
+		image, _ := c["image"].(string)
+		if image == "" {
+			continue
+		}
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			continue
+		}
+		if tag, ok := ref.(name.Tag); ok && tag.TagStr() == "latest" {
+			msgs = append(msgs, fmt.Sprintf("container %q uses the latest tag of the image %s",
+				containerName(c), image))
+		}
+	}
+	return msgs
+}
+
+func checkServiceSelector(object *unstructured.Unstructured, objects []*unstructured.Unstructured) []string {
+	if object.GetKind() != "Service" || object.GroupVersionKind().Group != "" {
+		return nil
+	}
+	selector, _, _ := unstructured.NestedStringMap(object.Object, "spec", "selector")
+	if len(selector) == 0 {
+		return nil
+	}
+
+	for _, workload := range objects {
+		if workload.GetNamespace() != object.GetNamespace() {
+			continue
+		}
+		_, labels, ok := podTemplate(workload)
+		if !ok {
+			continue
+		}
+		if matchesSelector(selector, labels) {
+			return nil
+		}
+	}
+
+	var pairs []string
+	for _, k := range slices.Sorted(maps.Keys(selector)) {
+		pairs = append(pairs, k+"="+selector[k])
+	}
+	return []string{fmt.Sprintf("selector %s matches no rendered workload", strings.Join(pairs, ","))}
+}
+
+func matchesSelector(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
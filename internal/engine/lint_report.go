@@ -0,0 +1,197 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+)
+
+type lintJSONResult struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// WriteLintJSONReport writes the lint findings as a JSON array, with
+// the source file paths made relative to the base directory.
+func WriteLintJSONReport(w io.Writer, baseDir string, results []LintResult) error {
+	report := make([]lintJSONResult, 0, len(results))
+	for _, r := range results {
+		jr := lintJSONResult{
+			Rule:     r.Rule.ID,
+			Severity: r.Rule.Severity,
+			Object:   ssautil.FmtUnstructured(r.Object),
+			Message:  r.Message,
+		}
+		if r.Pos.IsValid() {
+			jr.File = relativeURI(baseDir, r.Pos.Filename())
+			jr.Line = r.Pos.Line()
+		}
+		report = append(report, jr)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("encoding the JSON report failed: %w", err)
+	}
+	return nil
+}
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIFReport writes the lint findings in the SARIF 2.1.0 format
+// accepted by GitHub code scanning. The findings are located in the module
+// source files, with the paths made relative to the base directory, which
+// should be the repository root.
+func WriteSARIFReport(w io.Writer, baseDir, version string, results []LintResult) error {
+	driver := sarifDriver{
+		Name:           "timoni",
+		InformationURI: "https://timoni.sh",
+		Version:        version,
+	}
+	ruleIndex := make(map[string]int)
+	for i, rule := range LintRules() {
+		ruleIndex[rule.ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		})
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: driver},
+		Results: make([]sarifResult, 0, len(results)),
+	}
+	for _, r := range results {
+		object := ssautil.FmtUnstructured(r.Object)
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: object, Kind: "object"}},
+		}
+		if r.Pos.IsValid() {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: relativeURI(baseDir, r.Pos.Filename())},
+				Region:           &sarifRegion{StartLine: r.Pos.Line(), StartColumn: r.Pos.Column()},
+			}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    r.Rule.ID,
+			RuleIndex: ruleIndex[r.Rule.ID],
+			Level:     r.Rule.Severity,
+			Message:   sarifMessage{Text: fmt.Sprintf("%s %s", object, r.Message)},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}); err != nil {
+		return fmt.Errorf("encoding the SARIF report failed: %w", err)
+	}
+	return nil
+}
+
+// relativeURI returns the file path relative to the base directory with
+// forward slashes, or the path unchanged if it's outside the base directory.
+func relativeURI(baseDir, path string) string {
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func lintDeployment(name string, annotations map[string]any, podSpec map[string]any) *unstructured.Unstructured {
+	metadata := map[string]any{"name": name, "namespace": "default"}
+	if annotations != nil {
+		metadata["annotations"] = annotations
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   metadata,
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": map[string]any{"app": name}},
+				"spec":     podSpec,
+			},
+		},
+	}}
+}
+
+func lintService(selector map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "app", "namespace": "default"},
+		"spec":       map[string]any{"selector": selector},
+	}}
+}
+
+func TestLint(t *testing.T) {
+	compliant := map[string]any{
+		"containers": []any{map[string]any{
+			"name":           "app",
+			"image":          "nginx:1.27",
+			"readinessProbe": map[string]any{},
+			"livenessProbe":  map[string]any{},
+			"resources": map[string]any{
+				"requests": map[string]any{"cpu": "100m"},
+				"limits":   map[string]any{"memory": "128Mi"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		objects []*unstructured.Unstructured
+		want    []string
+	}{
+		{
+			name: "passes compliant objects",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", nil, compliant),
+				lintService(map[string]any{"app": "app"}),
+			},
+		},
+		{
+			name: "reports containers without probes and resources",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", nil, map[string]any{
+					"containers": []any{map[string]any{
+						"name":           "app",
+						"image":          "nginx@sha256:b49fbaac0eedc22c1cfcd26684707179cccbed0df205171bae3e1bae61326a10",
+						"readinessProbe": map[string]any{},
+					}},
+				}),
+			},
+			want: []string{
+				`[container-probes] container "app" does not define livenessProbe`,
+				`[container-resources] container "app" does not define resources.requests, resources.limits`,
+			},
+		},
+		{
+			name: "reports images with the latest tag",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", map[string]any{
+					"lint.timoni.sh/ignore": "container-probes, container-resources",
+				}, map[string]any{
+					"initContainers": []any{map[string]any{"name": "init", "image": "busybox"}},
+					"containers":     []any{map[string]any{"name": "app", "image": "nginx:latest"}},
+				}),
+			},
+			want: []string{
+				`[image-latest-tag] container "app" uses the latest tag of the image nginx:latest`,
+				`[image-latest-tag] container "init" uses the latest tag of the image busybox`,
+			},
+		},
+		{
+			name: "reports hostPath volumes",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", nil, map[string]any{
+					"containers": compliant["containers"],
+					"volumes": []any{map[string]any{
+						"name":     "logs",
+						"hostPath": map[string]any{"path": "/var/log"},
+					}},
+				}),
+			},
+			want: []string{
+				`[host-path-volume] volume "logs" mounts the host path /var/log`,
+			},
+		},
+		{
+			name: "reports Services matching no workload",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", nil, compliant),
+				lintService(map[string]any{"app": "app", "tier": "web"}),
+			},
+			want: []string{
+				`[service-selector] selector app=app,tier=web matches no rendered workload`,
+			},
+		},
+		{
+			name: "skips the rules in the ignore annotation",
+			objects: []*unstructured.Unstructured{
+				lintDeployment("app", map[string]any{
+					"lint.timoni.sh/ignore": "host-path-volume",
+				}, map[string]any{
+					"containers": compliant["containers"],
+					"volumes": []any{map[string]any{
+						"name":     "logs",
+						"hostPath": map[string]any{"path": "/var/log"},
+					}},
+				}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var got []string
+			for _, r := range Lint(tt.objects) {
+				got = append(got, r.String())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestLintReports(t *testing.T) {
+	g := NewWithT(t)
+
+	moduleRoot, err := filepath.Abs("testdata/module")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	builder := NewModuleBuilder(cuecontext.New(), "test", "default", moduleRoot, "main")
+	g.Expect(builder.OverlaySchemaFile()).To(Succeed())
+	buildResult, err := builder.Build()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	sets, err := builder.GetApplySets(buildResult)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(sets).ToNot(BeEmpty())
+
+	object := sets[0].Objects[0]
+	results := []LintResult{{
+		Rule:    LintRules()[0],
+		Object:  object,
+		Message: "test finding",
+	}}
+	SetPositions(results, builder.GetObjectPositions(buildResult))
+	g.Expect(results[0].Pos.IsValid()).To(BeTrue())
+
+	var sarif bytes.Buffer
+	g.Expect(WriteSARIFReport(&sarif, filepath.Dir(moduleRoot), "1.0.0", results)).To(Succeed())
+
+	var report sarifLog
+	g.Expect(json.Unmarshal(sarif.Bytes(), &report)).To(Succeed())
+	g.Expect(report.Version).To(Equal("2.1.0"))
+	g.Expect(report.Runs).To(HaveLen(1))
+	g.Expect(report.Runs[0].Tool.Driver.Rules).To(HaveLen(len(LintRules())))
+	g.Expect(report.Runs[0].Results).To(HaveLen(1))
+
+	location := report.Runs[0].Results[0].Locations[0]
+	g.Expect(location.PhysicalLocation.ArtifactLocation.URI).To(HavePrefix("module/templates/"))
+	g.Expect(location.PhysicalLocation.Region.StartLine).To(BeNumerically(">", 0))
+
+	var out bytes.Buffer
+	g.Expect(WriteLintJSONReport(&out, moduleRoot, results)).To(Succeed())
+	g.Expect(out.String()).To(ContainSubstring(`"rule": "container-probes"`))
+	g.Expect(out.String()).To(ContainSubstring(`"file": "templates/`))
+}
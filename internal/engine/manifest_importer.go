@@ -0,0 +1,811 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/format"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ManifestImporter generates the templates and the #Config schema of a
+// module from plain Kubernetes objects. Each object is converted to a CUE
+// template typed against the Kubernetes schemas vendored in the module,
+// with its name and namespace wired to the instance metadata.
+// The container images, resources and the replicas of the workloads are
+// extracted into #Config with the values found in the objects as defaults.
+type ManifestImporter struct {
+	ctx     *cue.Context
+	genDir  string
+	schemas map[string][]string
+	exprs   []string
+}
+
+// NewManifestImporter creates a ManifestImporter for the module
+// at the given root directory.
+func NewManifestImporter(ctx *cue.Context, moduleRoot string) *ManifestImporter {
+	return &ManifestImporter{
+		ctx:     ctx,
+		genDir:  filepath.Join(moduleRoot, "cue.mod", "gen"),
+		schemas: make(map[string][]string),
+	}
+}
+
+// importedObject holds the naming and the schema of an object
+// along with the CUE expressions replacing its values.
+type importedObject struct {
+	object *unstructured.Unstructured
+	name   string
+	suffix string
+	def    string
+	key    string
+	file   string
+	pkg    string
+	alias  string
+}
+
+// importedWorkload holds the parameters extracted from a workload.
+type importedWorkload struct {
+	object     *importedObject
+	key        string
+	replicas   *int64
+	containers []importedContainer
+}
+
+// importedContainer holds the parameters extracted from a container.
+type importedContainer struct {
+	key       string
+	name      string
+	image     string
+	resources map[string]any
+}
+
+// clusterScopedKinds lists the Kubernetes kinds that are not namespaced.
+var clusterScopedKinds = []string{
+	"APIService",
+	"CSIDriver",
+	"CSINode",
+	"CertificateSigningRequest",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"FlowSchema",
+	"IngressClass",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PriorityClass",
+	"PriorityLevelConfiguration",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingAdmissionPolicy",
+	"ValidatingAdmissionPolicyBinding",
+	"ValidatingWebhookConfiguration",
+	"VolumeAttachment",
+}
+
+// importReservedKeys lists the #Config fields set by the importer
+// that can't be used as workload keys.
+var importReservedKeys = []string{
+	"kubeVersion",
+	"clusterVersion",
+	"moduleVersion",
+	"metadata",
+	"image",
+	"replicas",
+	"resources",
+	"containers",
+}
+
+// MissingSchemas returns the import paths of the Kubernetes API packages
+// needed by the objects that are not vendored in the module.
+func (imp *ManifestImporter) MissingSchemas(objects []*unstructured.Unstructured) []string {
+	var missing []string
+	for _, object := range objects {
+		pkg, _ := k8sSchemaPackage(object.GetAPIVersion())
+		if pkg == "" || slices.Contains(missing, pkg) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(imp.genDir, pkg)); err != nil {
+			missing = append(missing, pkg)
+		}
+	}
+	return missing
+}
+
+// Generate converts the objects to CUE and returns the content of the
+// generated files, indexed by their path relative to the module root.
+// The result contains the templates/config.cue file with the #Config and
+// #Instance definitions, and a template file for each object.
+func (imp *ManifestImporter) Generate(objects []*unstructured.Unstructured) (map[string][]byte, error) {
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no Kubernetes objects found")
+	}
+	imp.exprs = nil
+
+	var imported []*importedObject
+	var names []string
+	for _, object := range objects {
+		obj := object.DeepCopy()
+		cleanImportedObject(obj)
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("object without kind or name found in %s", obj.GetAPIVersion())
+		}
+		imported = append(imported, &importedObject{object: obj, name: obj.GetName()})
+		if obj.GetKind() != "Namespace" {
+			names = append(names, obj.GetName())
+		}
+	}
+
+	appName := detectAppName(names)
+	if err := imp.nameObjects(imported, appName); err != nil {
+		return nil, err
+	}
+
+	renames, namespaces := imp.wireMetadata(imported, appName)
+	for _, o := range imported {
+		rewriteReferences(o.object.Object, "", renames, namespaces, imp.expr)
+	}
+
+	workloads := imp.extractWorkloads(imported)
+
+	result := make(map[string][]byte)
+	for _, o := range imported {
+		data, err := imp.template(o)
+		if err != nil {
+			return nil, fmt.Errorf("generating the template for %s/%s failed: %w",
+				o.object.GetKind(), o.name, err)
+		}
+		result[path.Join("templates", o.file)] = data
+	}
+
+	data, err := imp.config(imported, workloads)
+	if err != nil {
+		return nil, fmt.Errorf("generating the config failed: %w", err)
+	}
+	result[path.Join("templates", "config.cue")] = data
+
+	return result, nil
+}
+
+// nameObjects sets the definition name, the instance key and the file name
+// of each object, based on its kind and its name without the app name prefix.
+func (imp *ManifestImporter) nameObjects(imported []*importedObject, appName string) error {
+	kinds := make(map[string]int)
+	for _, o := range imported {
+		kinds[o.object.GetKind()]++
+	}
+
+	defs := make(map[string]bool)
+	for _, o := range imported {
+		kind := o.object.GetKind()
+		name := o.object.GetName()
+		switch {
+		case kind == "Namespace":
+		case appName == "":
+			o.suffix = name
+		case name == appName:
+		case strings.HasPrefix(name, appName+"-"):
+			o.suffix = strings.TrimPrefix(name, appName+"-")
+		default:
+			o.suffix = name
+		}
+
+		o.def = "#" + kind
+		o.key = lowerCamel(kind)
+		o.file = strings.ToLower(kind) + ".cue"
+		if kinds[kind] > 1 && o.suffix != "" {
+			o.def += upperCamel(o.suffix)
+			o.key += upperCamel(o.suffix)
+			o.file = strings.ToLower(kind) + "-" + strings.ToLower(o.suffix) + ".cue"
+		}
+		if defs[o.def] {
+			return fmt.Errorf("duplicate object %s/%s", kind, name)
+		}
+		defs[o.def] = true
+
+		o.pkg, o.alias = k8sSchemaPackage(o.object.GetAPIVersion())
+		if o.pkg != "" && !slices.Contains(imp.vendoredKinds(o.pkg), kind) {
+			o.pkg, o.alias = "", ""
+		}
+	}
+	return nil
+}
+
+// wireMetadata replaces the name and namespace of the objects with the
+// instance metadata. It returns the CUE expressions of the renamed objects
+// indexed by their original name, and the original namespaces.
+func (imp *ManifestImporter) wireMetadata(imported []*importedObject, appName string) (map[string]string, []string) {
+	renames := make(map[string]string)
+	var namespaces []string
+	for _, o := range imported {
+		obj := o.object
+		name := obj.GetName()
+
+		nameExpr := "#config.metadata.name"
+		switch {
+		case obj.GetKind() == "Namespace":
+			nameExpr = "#config.metadata.namespace"
+			namespaces = append(namespaces, name)
+		case appName == "" || name != appName:
+			nameExpr = fmt.Sprintf(`"\(#config.metadata.name)-%s"`, o.suffix)
+		}
+		renames[name] = nameExpr
+		_ = unstructured.SetNestedField(obj.Object, imp.expr(nameExpr), "metadata", "name")
+
+		if ns := obj.GetNamespace(); ns != "" && !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+		if isNamespacedKind(obj) {
+			_ = unstructured.SetNestedField(obj.Object, imp.expr("#config.metadata.namespace"), "metadata", "namespace")
+		} else {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+		}
+
+		// The standard labels are set from the instance metadata.
+		labels := obj.GetLabels()
+		for _, l := range []string{"app.kubernetes.io/name", "app.kubernetes.io/version", "app.kubernetes.io/managed-by"} {
+			delete(labels, l)
+		}
+		obj.SetLabels(labels)
+	}
+	return renames, namespaces
+}
+
+// extractWorkloads replaces the replicas and the containers image and
+// resources of the workloads with the #Config fields.
+func (imp *ManifestImporter) extractWorkloads(imported []*importedObject) []*importedWorkload {
+	var workloads []*importedWorkload
+	for _, o := range imported {
+		if podSpecPath(o.object.GetKind()) != nil {
+			workloads = append(workloads, &importedWorkload{object: o})
+		}
+	}
+
+	keys := slices.Clone(importReservedKeys)
+	for _, w := range workloads {
+		obj := w.object.object
+		if len(workloads) > 1 {
+			w.key = lowerCamel(w.object.suffix)
+			if w.key == "" || slices.Contains(keys, w.key) {
+				w.key = w.object.key
+			}
+			keys = append(keys, w.key)
+		}
+
+		switch obj.GetKind() {
+		case "Deployment", "StatefulSet", "ReplicaSet":
+			replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+			if !found {
+				replicas = 1
+			}
+			w.replicas = &replicas
+			_ = unstructured.SetNestedField(obj.Object, imp.expr(w.path("replicas")), "spec", "replicas")
+		}
+
+		podSpec := podSpecPath(obj.GetKind())
+		var total int
+		for _, field := range []string{"initContainers", "containers"} {
+			list, _, _ := unstructured.NestedSlice(obj.Object, append(podSpec, field)...)
+			total += len(list)
+		}
+
+		var ckeys []string
+		for _, field := range []string{"initContainers", "containers"} {
+			list, found, _ := unstructured.NestedSlice(obj.Object, append(podSpec, field)...)
+			if !found {
+				continue
+			}
+			for _, item := range list {
+				container, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				image, _ := container["image"].(string)
+				if image == "" {
+					continue
+				}
+				c := importedContainer{image: image}
+				c.name, _ = container["name"].(string)
+				if total > 1 {
+					c.key = lowerCamel(c.name)
+					if c.key == "" || slices.Contains(ckeys, c.key) {
+						c.key = fmt.Sprintf("container%d", len(ckeys))
+					}
+					ckeys = append(ckeys, c.key)
+				}
+
+				container["image"] = imp.expr(w.containerPath(c, "image.reference"))
+				if res, ok := container["resources"].(map[string]any); ok && len(res) > 0 {
+					c.resources = res
+					container["resources"] = imp.expr(w.containerPath(c, "resources"))
+				}
+				w.containers = append(w.containers, c)
+			}
+			_ = unstructured.SetNestedSlice(obj.Object, list, append(podSpec, field)...)
+		}
+	}
+	return workloads
+}
+
+// path returns the CUE path of a workload field in #config.
+func (w *importedWorkload) path(field string) string {
+	if w.key == "" {
+		return "#config." + field
+	}
+	return fmt.Sprintf("#config.%s.%s", w.key, field)
+}
+
+// containerPath returns the CUE path of a container field in #config.
+func (w *importedWorkload) containerPath(c importedContainer, field string) string {
+	if c.key == "" {
+		return w.path(field)
+	}
+	return w.path(fmt.Sprintf("containers.%s.%s", c.key, field))
+}
+
+// expr returns a placeholder for the CUE expression,
+// the placeholders are replaced when generating the templates.
+func (imp *ManifestImporter) expr(e string) string {
+	imp.exprs = append(imp.exprs, e)
+	return importPlaceholder(len(imp.exprs) - 1)
+}
+
+func importPlaceholder(i int) string {
+	return fmt.Sprintf("__timoni_import_%d__", i)
+}
+
+// template generates the CUE template of the object.
+func (imp *ManifestImporter) template(o *importedObject) ([]byte, error) {
+	value := imp.ctx.Encode(o.object.Object)
+	if err := value.Err(); err != nil {
+		return nil, err
+	}
+	body, err := format.Node(value.Syntax(cue.Final()))
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.TrimSpace(string(body))
+	fields = strings.TrimSuffix(strings.TrimPrefix(fields, "{"), "}")
+
+	var b strings.Builder
+	b.WriteString("package templates\n\n")
+	if o.pkg != "" {
+		fmt.Fprintf(&b, "import (\n%s %q\n)\n\n", o.alias, o.pkg)
+	}
+
+	gvk := o.object.GroupVersionKind()
+	fmt.Fprintf(&b, "// %s is the %s %s imported from the %s manifest.\n",
+		strings.TrimPrefix(o.def, "#"), gvk.GroupVersion(), gvk.Kind, o.name)
+	if o.pkg != "" {
+		fmt.Fprintf(&b, "%s: %s.#%s & {\n", o.def, o.alias, gvk.Kind)
+	} else {
+		fmt.Fprintf(&b, "// The %s schema is not vendored, the object is not validated.\n", gvk.Kind)
+		fmt.Fprintf(&b, "%s: {\n", o.def)
+	}
+	b.WriteString("#config: #Config\n")
+	b.WriteString(fields)
+	b.WriteString("\nmetadata: labels: #config.metadata.labels\n")
+	b.WriteString("if #config.metadata.annotations != _|_ {\n")
+	b.WriteString("metadata: annotations: #config.metadata.annotations\n")
+	b.WriteString("}\n}\n")
+
+	return format.Source([]byte(imp.replaceExprs(b.String())))
+}
+
+// replaceExprs replaces the placeholders with their CUE expressions.
+func (imp *ManifestImporter) replaceExprs(src string) string {
+	for i := len(imp.exprs) - 1; i >= 0; i-- {
+		src = strings.ReplaceAll(src, strconv.Quote(importPlaceholder(i)), imp.exprs[i])
+	}
+	return src
+}
+
+// config generates the #Config and #Instance definitions.
+func (imp *ManifestImporter) config(imported []*importedObject, workloads []*importedWorkload) ([]byte, error) {
+	var params strings.Builder
+	var withCore bool
+	for _, w := range workloads {
+		obj := w.object.object
+		subject := fmt.Sprintf("%s %s", obj.GetKind(), w.object.name)
+		if w.key != "" {
+			fmt.Fprintf(&params, "\n// The %s settings.\n%s: {\n", subject, w.key)
+		}
+		if w.replicas != nil {
+			fmt.Fprintf(&params, "\n// The number of pods replicas of the %s.\nreplicas: *%d | int & >=0\n", subject, *w.replicas)
+		}
+
+		var containers bool
+		for _, c := range w.containers {
+			if c.key != "" && !containers {
+				fmt.Fprintf(&params, "\n// The containers settings of the %s.\ncontainers: {\n", subject)
+				containers = true
+			}
+			if c.key != "" {
+				fmt.Fprintf(&params, "%s: {\n", c.key)
+			}
+
+			repository, tag, digest := splitImageReference(c.image)
+			fmt.Fprintf(&params, "\n// The container image of %s.\n", c.name)
+			fmt.Fprintf(&params, "image: timoniv1.#Image & {\nrepository: *%q | string\ntag: *%q | string\ndigest: *%q | string\n}\n",
+				repository, tag, digest)
+
+			if c.resources != nil {
+				fmt.Fprintf(&params, "\n// The resource requirements of %s.\n", c.name)
+				res, core := formatImportedResources(c.resources)
+				withCore = withCore || core
+				fmt.Fprintf(&params, "resources: %s\n", res)
+			}
+
+			if c.key != "" {
+				params.WriteString("}\n")
+			}
+		}
+		if containers {
+			params.WriteString("}\n")
+		}
+		if w.key != "" {
+			params.WriteString("}\n")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("package templates\n\nimport (\n")
+	if withCore {
+		b.WriteString("corev1 \"k8s.io/api/core/v1\"\n")
+	}
+	b.WriteString("timoniv1 \"timoni.sh/core/v1alpha1\"\n)\n\n")
+	b.WriteString(importConfigHeader)
+	b.WriteString(strings.ReplaceAll(params.String(), "{\n\n", "{\n"))
+	b.WriteString("}\n\n")
+	b.WriteString("// Instance takes the config values and outputs the Kubernetes objects.\n")
+	b.WriteString("#Instance: {\nconfig: #Config\n\nobjects: {\n")
+	for _, o := range imported {
+		fmt.Fprintf(&b, "%s: %s & {#config: config}\n", o.key, o.def)
+	}
+	b.WriteString("}\n}\n")
+
+	return format.Source([]byte(b.String()))
+}
+
+// importConfigHeader holds the #Config fields set by Timoni at runtime.
+const importConfigHeader = `// Config defines the schema and defaults for the Instance values.
+#Config: {
+	// The kubeVersion is a required field, set at apply-time
+	// via timoni.cue by querying the user's Kubernetes API.
+	kubeVersion!: string
+	// Using the kubeVersion you can enforce a minimum Kubernetes minor version.
+	// By default, the minimum Kubernetes version is set to 1.20.
+	clusterVersion: timoniv1.#SemVer & {#Version: kubeVersion, #Minimum: "1.20.0"}
+
+	// The moduleVersion is set from the user-supplied module version.
+	// This field is used for the ` + "`app.kubernetes.io/version`" + ` label.
+	moduleVersion!: string
+
+	// The Kubernetes metadata common to all resources.
+	// The ` + "`metadata.name` and `metadata.namespace`" + ` fields are
+	// set from the user-supplied instance name and namespace.
+	metadata: timoniv1.#Metadata & {#Version: moduleVersion}
+
+	// The labels allows adding ` + "`metadata.labels`" + ` to all resources.
+	// The ` + "`app.kubernetes.io/name` and `app.kubernetes.io/version`" + ` labels
+	// are automatically generated and can't be overwritten.
+	metadata: labels: timoniv1.#Labels
+
+	// The annotations allows adding ` + "`metadata.annotations`" + ` to all resources.
+	metadata: annotations?: timoniv1.#Annotations
+`
+
+// formatImportedResources returns the CUE schema of the container resources
+// with the given values as defaults. The Timoni resource requirements are used
+// when the resources are limited to CPU and memory, otherwise the Kubernetes
+// schema is used, in which case the second return value is true.
+func formatImportedResources(res map[string]any) (string, bool) {
+	var timoni strings.Builder
+	timoni.WriteString("timoniv1.#ResourceRequirements & {\n")
+	compatible := true
+	for _, group := range []string{"limits", "requests"} {
+		values, ok := res[group].(map[string]any)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&timoni, "%s: {\n", group)
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			q, err := resource.ParseQuantity(fmt.Sprint(values[name]))
+			if err != nil {
+				compatible = false
+				break
+			}
+			switch name {
+			case "cpu":
+				if q.MilliValue() <= 0 {
+					compatible = false
+					continue
+				}
+				fmt.Fprintf(&timoni, "cpu: *\"%dm\" | timoniv1.#CPUQuantity\n", q.MilliValue())
+			case "memory":
+				switch v := q.Value(); {
+				case v > 0 && v%(1<<30) == 0:
+					fmt.Fprintf(&timoni, "memory: *\"%dGi\" | timoniv1.#MemoryQuantity\n", v>>30)
+				case v > 0 && v%(1<<20) == 0:
+					fmt.Fprintf(&timoni, "memory: *\"%dMi\" | timoniv1.#MemoryQuantity\n", v>>20)
+				default:
+					compatible = false
+				}
+			default:
+				compatible = false
+			}
+		}
+		timoni.WriteString("}\n")
+	}
+	timoni.WriteString("}")
+	if compatible {
+		return timoni.String(), false
+	}
+
+	var core strings.Builder
+	core.WriteString("corev1.#ResourceRequirements & {\n")
+	for _, group := range slices.Sorted(maps.Keys(res)) {
+		values, ok := res[group].(map[string]any)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&core, "%s: {\n", group)
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			v, _ := json.Marshal(values[name])
+			fmt.Fprintf(&core, "%s: *%s | _\n", cueLabel(name), v)
+		}
+		core.WriteString("}\n")
+	}
+	core.WriteString("}")
+	return core.String(), true
+}
+
+// rewriteReferences replaces the names of the renamed objects and the
+// original namespaces found in the fields referencing other objects,
+// such as the service account, the config maps, secrets and role bindings.
+func rewriteReferences(value any, parent string, renames map[string]string, namespaces []string, expr func(string) string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			s, ok := field.(string)
+			if !ok {
+				rewriteReferences(field, key, renames, namespaces, expr)
+				continue
+			}
+			switch {
+			case isReferenceField(parent, key):
+				if e, found := renames[s]; found {
+					v[key] = expr(e)
+				}
+			case key == "namespace" && parent == "subjects":
+				if slices.Contains(namespaces, s) {
+					v[key] = expr("#config.metadata.namespace")
+				}
+			}
+		}
+	case []any:
+		for _, item := range v {
+			rewriteReferences(item, parent, renames, namespaces, expr)
+		}
+	}
+}
+
+// isReferenceField returns true if the field holds the name of another object.
+func isReferenceField(parent, key string) bool {
+	switch key {
+	case "serviceAccountName", "serviceAccount", "secretName", "claimName", "serviceName":
+		return true
+	case "name":
+		switch parent {
+		case "configMapRef", "secretRef", "configMapKeyRef", "secretKeyRef",
+			"configMap", "roleRef", "scaleTargetRef", "service", "subjects", "imagePullSecrets":
+			return true
+		}
+	}
+	return false
+}
+
+// vendoredKinds returns the kinds defined in the vendored CUE package.
+func (imp *ManifestImporter) vendoredKinds(pkg string) []string {
+	if kinds, ok := imp.schemas[pkg]; ok {
+		return kinds
+	}
+
+	var kinds []string
+	files, _ := filepath.Glob(filepath.Join(imp.genDir, pkg, "*.cue"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, m := range cueDefinitionRegexp.FindAllSubmatch(data, -1) {
+			kinds = append(kinds, string(m[1]))
+		}
+	}
+	imp.schemas[pkg] = kinds
+	return kinds
+}
+
+var cueDefinitionRegexp = regexp.MustCompile(`(?m)^#(\w+):`)
+
+// k8sSchemaPackage returns the import path and the alias of the CUE package
+// generated from the Kubernetes API of the given apiVersion. An empty path is
+// returned for the APIs defined by CRDs.
+func k8sSchemaPackage(apiVersion string) (string, string) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || gv.Version == "" {
+		return "", ""
+	}
+
+	group := strings.Split(gv.Group, ".")[0]
+	switch {
+	case gv.Group == "":
+		group = "core"
+	case gv.Group == "apiextensions.k8s.io":
+		return path.Join("k8s.io/apiextensions-apiserver/pkg/apis/apiextensions", gv.Version), "apiextensions" + gv.Version
+	case gv.Group == "apiregistration.k8s.io":
+		return path.Join("k8s.io/kube-aggregator/pkg/apis/apiregistration", gv.Version), "apiregistration" + gv.Version
+	case strings.Contains(gv.Group, ".") && !strings.HasSuffix(gv.Group, ".k8s.io"):
+		return "", ""
+	}
+	return path.Join("k8s.io/api", group, gv.Version), group + gv.Version
+}
+
+// isNamespacedKind returns true if the object is namespaced. The objects
+// of unknown kinds are namespaced if their namespace is set.
+func isNamespacedKind(obj *unstructured.Unstructured) bool {
+	if slices.Contains(clusterScopedKinds, obj.GetKind()) {
+		return false
+	}
+	if pkg, _ := k8sSchemaPackage(obj.GetAPIVersion()); pkg == "" {
+		return obj.GetNamespace() != ""
+	}
+	return true
+}
+
+// podSpecPath returns the path to the pod spec of the workload kinds.
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return []string{"spec", "template", "spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Pod":
+		return []string{"spec"}
+	default:
+		return nil
+	}
+}
+
+// cleanImportedObject removes the status and the fields set by the API server.
+func cleanImportedObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"creationTimestamp", "generation", "managedFields",
+		"ownerReferences", "resourceVersion", "selfLink", "uid"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	delete(annotations, "deployment.kubernetes.io/revision")
+	obj.SetAnnotations(annotations)
+
+	if obj.GetKind() == "Service" {
+		unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		if ip, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); ip != "None" {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+		}
+	}
+}
+
+// detectAppName returns the name, or the dash-separated name prefix, shared
+// by most objects. An empty string is returned when the name is shared by
+// less than half of the objects.
+func detectAppName(names []string) string {
+	var candidates []string
+	for _, n := range names {
+		for i, r := range n {
+			if r == '-' {
+				candidates = append(candidates, n[:i])
+			}
+		}
+		candidates = append(candidates, n)
+	}
+
+	var appName string
+	var count int
+	for _, n := range candidates {
+		c := 0
+		for _, m := range names {
+			if m == n || strings.HasPrefix(m, n+"-") {
+				c++
+			}
+		}
+		if c > count || (c == count && len(n) > len(appName)) {
+			appName, count = n, c
+		}
+	}
+	if len(names) > 1 && count*2 < len(names) {
+		return ""
+	}
+	return appName
+}
+
+// splitImageReference returns the repository, tag and digest of an image.
+func splitImageReference(ref string) (string, string, string) {
+	var tag, digest string
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref, digest = ref[:i], ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, tag = ref[:i], ref[i+1:]
+	}
+	return ref, tag, digest
+}
+
+// cueLabel returns the name as a CUE label, quoted if it's not an identifier.
+func cueLabel(name string) string {
+	if cueIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+var cueIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+// lowerCamel converts a Kubernetes name to a CUE identifier in lower camel case.
+func lowerCamel(s string) string {
+	u := upperCamel(s)
+	if u == "" {
+		return ""
+	}
+	r := []rune(u)
+	r[0] = unicode.ToLower(r[0])
+	if unicode.IsDigit(r[0]) {
+		return "x" + string(r)
+	}
+	return string(r)
+}
+
+// upperCamel converts a Kubernetes name to upper camel case.
+func upperCamel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
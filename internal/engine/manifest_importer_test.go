@@ -0,0 +1,254 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	. "github.com/onsi/gomega"
+)
+
+const importManifests = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: podinfo
+  namespace: apps
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: apps
+  labels:
+    app: podinfo
+    app.kubernetes.io/name: podinfo
+  creationTimestamp: null
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: podinfo
+  template:
+    metadata:
+      labels:
+        app: podinfo
+    spec:
+      serviceAccountName: podinfo
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.5.0
+        resources:
+          requests:
+            cpu: "0.1"
+            memory: 64Mi
+status: {}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo-redis
+  namespace: apps
+spec:
+  selector:
+    matchLabels:
+      app: redis
+  template:
+    metadata:
+      labels:
+        app: redis
+    spec:
+      containers:
+      - name: redis
+        image: redis:7
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: podinfo-tls
+  namespace: apps
+spec:
+  secretName: podinfo-tls
+`
+
+func TestManifestImporter_Generate(t *testing.T) {
+	g := NewWithT(t)
+
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(importManifests)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	imp := NewManifestImporter(cuecontext.New(), "../../blueprints/starter")
+	g.Expect(imp.MissingSchemas(objects)).To(BeEmpty())
+
+	files, err := imp.Generate(objects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(slices.Sorted(maps.Keys(files))).To(Equal([]string{
+		"templates/certificate.cue",
+		"templates/config.cue",
+		"templates/deployment-redis.cue",
+		"templates/deployment.cue",
+		"templates/serviceaccount.cue",
+	}))
+
+	deployment := string(files["templates/deployment.cue"])
+	g.Expect(deployment).To(ContainSubstring(`appsv1 "k8s.io/api/apps/v1"`))
+	g.Expect(deployment).To(ContainSubstring("#Deployment: appsv1.#Deployment & {"))
+	g.Expect(deployment).To(ContainSubstring("name:      #config.metadata.name"))
+	g.Expect(deployment).To(ContainSubstring("namespace: #config.metadata.namespace"))
+	g.Expect(deployment).To(MatchRegexp(`replicas:\s+#config.deployment.replicas`))
+	g.Expect(deployment).To(MatchRegexp(`image:\s+#config.deployment.image.reference`))
+	g.Expect(deployment).To(MatchRegexp(`resources:\s+#config.deployment.resources`))
+	g.Expect(deployment).To(ContainSubstring("serviceAccountName: #config.metadata.name"))
+	g.Expect(deployment).ToNot(ContainSubstring("app.kubernetes.io/name"))
+	g.Expect(deployment).ToNot(ContainSubstring("status"))
+	g.Expect(deployment).ToNot(ContainSubstring("creationTimestamp"))
+
+	redis := string(files["templates/deployment-redis.cue"])
+	g.Expect(redis).To(ContainSubstring("#DeploymentRedis: appsv1.#Deployment & {"))
+	g.Expect(redis).To(ContainSubstring(`name:      "\(#config.metadata.name)-redis"`))
+	g.Expect(redis).To(MatchRegexp(`replicas:\s+#config.redis.replicas`))
+
+	certificate := string(files["templates/certificate.cue"])
+	g.Expect(certificate).To(ContainSubstring("The Certificate schema is not vendored"))
+	g.Expect(certificate).To(ContainSubstring(`secretName: "\(#config.metadata.name)-tls"`))
+
+	config := string(files["templates/config.cue"])
+	g.Expect(config).To(ContainSubstring("replicas: *2 | int & >=0"))
+	g.Expect(config).To(ContainSubstring(`repository: *"ghcr.io/stefanprodan/podinfo" | string`))
+	g.Expect(config).To(ContainSubstring(`tag:        *"6.5.0" | string`))
+	g.Expect(config).To(ContainSubstring(`cpu:    *"100m" | timoniv1.#CPUQuantity`))
+	g.Expect(config).To(ContainSubstring(`repository: *"redis" | string`))
+	g.Expect(config).To(ContainSubstring("deploymentRedis: #DeploymentRedis & {#config: config}"))
+}
+
+func TestManifestImporter_GenerateDuplicate(t *testing.T) {
+	g := NewWithT(t)
+
+	manifests := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: dev
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: prod
+`
+	objects, err := ssautil.ReadObjects(bytes.NewReader([]byte(manifests)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = NewManifestImporter(cuecontext.New(), "../../blueprints/starter").Generate(objects)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("duplicate object ConfigMap/app"))
+}
+
+func TestDetectAppName(t *testing.T) {
+	tests := []struct {
+		names    []string
+		expected string
+	}{
+		{[]string{"podinfo"}, "podinfo"},
+		{[]string{"podinfo", "podinfo-redis", "podinfo-config"}, "podinfo"},
+		{[]string{"app-web", "app-api"}, "app"},
+		{[]string{"my-app"}, "my-app"},
+		{[]string{"frontend", "backend", "database"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(detectAppName(tt.names)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestSplitImageReference(t *testing.T) {
+	tests := []struct {
+		image  string
+		repo   string
+		tag    string
+		digest string
+	}{
+		{"nginx", "nginx", "", ""},
+		{"nginx:1.25", "nginx", "1.25", ""},
+		{"localhost:5000/app:v1", "localhost:5000/app", "v1", ""},
+		{"localhost:5000/app", "localhost:5000/app", "", ""},
+		{"ghcr.io/org/app:v1@sha256:abc", "ghcr.io/org/app", "v1", "sha256:abc"},
+		{"ghcr.io/org/app@sha256:abc", "ghcr.io/org/app", "", "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+			repo, tag, digest := splitImageReference(tt.image)
+			g.Expect(repo).To(Equal(tt.repo))
+			g.Expect(tag).To(Equal(tt.tag))
+			g.Expect(digest).To(Equal(tt.digest))
+		})
+	}
+}
+
+func TestK8sSchemaPackage(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		pkg        string
+		alias      string
+	}{
+		{"v1", "k8s.io/api/core/v1", "corev1"},
+		{"apps/v1", "k8s.io/api/apps/v1", "appsv1"},
+		{"autoscaling/v2", "k8s.io/api/autoscaling/v2", "autoscalingv2"},
+		{"rbac.authorization.k8s.io/v1", "k8s.io/api/rbac/v1", "rbacv1"},
+		{"apiextensions.k8s.io/v1", "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1", "apiextensionsv1"},
+		{"cert-manager.io/v1", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.apiVersion, func(t *testing.T) {
+			g := NewWithT(t)
+			pkg, alias := k8sSchemaPackage(tt.apiVersion)
+			g.Expect(pkg).To(Equal(tt.pkg))
+			g.Expect(alias).To(Equal(tt.alias))
+		})
+	}
+}
+
+func TestFormatImportedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	res, core := formatImportedResources(map[string]any{
+		"limits":   map[string]any{"cpu": "1", "memory": "1024Mi"},
+		"requests": map[string]any{"cpu": "250m"},
+	})
+	g.Expect(core).To(BeFalse())
+	g.Expect(res).To(ContainSubstring(`cpu: *"1000m" | timoniv1.#CPUQuantity`))
+	g.Expect(res).To(ContainSubstring(`memory: *"1Gi" | timoniv1.#MemoryQuantity`))
+
+	res, core = formatImportedResources(map[string]any{
+		"limits": map[string]any{"memory": "1G", "ephemeral-storage": "1Gi"},
+	})
+	g.Expect(core).To(BeTrue())
+	g.Expect(res).To(ContainSubstring(`"ephemeral-storage": *"1Gi" | _`))
+	g.Expect(res).To(ContainSubstring(`memory: *"1G" | _`))
+}
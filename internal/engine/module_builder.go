@@ -19,6 +19,7 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -52,6 +53,7 @@ type ModuleBuilder struct {
 	namespace     string
 	moduleVersion string
 	kubeVersion   string
+	apiVersions   []string
 	overlays      map[string]string
 }
 
@@ -149,10 +151,70 @@ func (b *ModuleBuilder) SetVersionInfo(moduleVersion, kubeVersion string) {
 	}
 }
 
+// SetAPIVersions allows setting the API versions served by the Kubernetes
+// cluster, in the format '<group>/<version>' and '<group>/<version>/<kind>',
+// which are injected at build time as an optional CUE tag.
+func (b *ModuleBuilder) SetAPIVersions(apiVersions []string) {
+	b.apiVersions = apiVersions
+}
+
 // Build builds the Timoni instance for the specified module and returns its CUE value.
 // If the instance validation fails, the returned error may represent more than one error,
 // retrievable with errors.Errors.
 func (b *ModuleBuilder) Build(tags ...string) (cue.Value, error) {
+	var value cue.Value
+	modValue, err := b.buildValue(b.overlays, tags)
+	if err != nil {
+		return value, err
+	}
+
+	// Extract the Timoni instance from the build value.
+	instance := modValue.LookupPath(cue.ParsePath(apiv1.InstanceSelector.String()))
+	if instance.Err() != nil {
+		return modValue, fmt.Errorf("lookup %s failed: %w", apiv1.InstanceSelector, instance.Err())
+	}
+
+	// Validate the Timoni instance which should be concrete and final.
+	if err := instance.Validate(cue.Concrete(true), cue.Final()); err != nil {
+		return modValue, err
+	}
+
+	return modValue, nil
+}
+
+// BuildConfig loads the module and returns its CUE value without validating
+// the instance, so that the #Config schema of a module can be inspected when
+// its required fields are not set.
+func (b *ModuleBuilder) BuildConfig() (cue.Value, error) {
+	return b.buildValue(b.overlays, nil)
+}
+
+// ValidateValues checks the given values against the module's values schema
+// and returns all the errors found. Unlike OverlayValuesFileWithDefaults, the
+// values are neither serialised nor merged with the module defaults, so the
+// errors point to where the values are defined. The values must be created
+// with the same CUE context as the builder. Required fields and the instance
+// objects are not checked, as that is the job of Build.
+func (b *ModuleBuilder) ValidateValues(val cue.Value) error {
+	overlays := maps.Clone(b.overlays)
+	overlays[filepath.Join(b.pkgPath, defaultValuesFile)] = fmt.Sprintf("package %s\n%s: {}", b.pkgName, apiv1.ValuesSelector)
+
+	modValue, err := b.buildValue(overlays, nil)
+	if err != nil {
+		return err
+	}
+
+	schema := modValue.LookupPath(cue.ParsePath(apiv1.ValuesSelector.String()))
+	if schema.Err() != nil {
+		return fmt.Errorf("lookup %s failed: %w", apiv1.ValuesSelector, schema.Err())
+	}
+
+	return schema.Unify(val).Validate()
+}
+
+// buildValue loads the module package with the given overlays and tags,
+// and returns the CUE value of the package.
+func (b *ModuleBuilder) buildValue(overlays map[string]string, tags []string) (cue.Value, error) {
 	var value cue.Value
 	cfg := &load.Config{
 		AcceptLegacyModules: true,
@@ -175,6 +237,15 @@ func (b *ModuleBuilder) Build(tags ...string) (cue.Value, error) {
 					return ast.NewString(b.kubeVersion), nil
 				},
 			},
+			"apiVersions": {
+				Func: func() (ast.Expr, error) {
+					list := make([]ast.Expr, 0, len(b.apiVersions))
+					for _, v := range b.apiVersions {
+						list = append(list, ast.NewString(v))
+					}
+					return ast.NewList(list...), nil
+				},
+			},
 		},
 	}
 
@@ -182,9 +253,9 @@ func (b *ModuleBuilder) Build(tags ...string) (cue.Value, error) {
 		cfg.Tags = append(cfg.Tags, tags...)
 	}
 
-	if len(b.overlays) > 0 {
-		cfg.Overlay = make(map[string]load.Source, len(b.overlays))
-		for path, content := range b.overlays {
+	if len(overlays) > 0 {
+		cfg.Overlay = make(map[string]load.Source, len(overlays))
+		for path, content := range overlays {
 			cfg.Overlay[path] = load.FromString(content)
 		}
 	}
@@ -204,17 +275,6 @@ func (b *ModuleBuilder) Build(tags ...string) (cue.Value, error) {
 		return value, modValue.Err()
 	}
 
-	// Extract the Timoni instance from the build value.
-	instance := modValue.LookupPath(cue.ParsePath(apiv1.InstanceSelector.String()))
-	if instance.Err() != nil {
-		return modValue, fmt.Errorf("lookup %s failed: %w", apiv1.InstanceSelector, instance.Err())
-	}
-
-	// Validate the Timoni instance which should be concrete and final.
-	if err := instance.Validate(cue.Concrete(true), cue.Final()); err != nil {
-		return modValue, err
-	}
-
 	return modValue, nil
 }
 
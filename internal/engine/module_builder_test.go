@@ -24,6 +24,7 @@ import (
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
 	. "github.com/onsi/gomega"
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
@@ -65,6 +66,48 @@ func TestModuleBuilder(t *testing.T) {
 	g.Expect(fmt.Sprintf("%v", objects)).To(BeEquivalentTo(fmt.Sprintf("%v", gold)))
 }
 
+func TestModuleBuilder_APIVersions(t *testing.T) {
+	moduleRoot := path.Join(t.TempDir(), "module")
+	err := CopyDir("testdata/module", moduleRoot, true)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	tests := []struct {
+		name        string
+		apiVersions []string
+		want        bool
+	}{
+		{
+			name: "defaults to no API versions",
+			want: false,
+		},
+		{
+			name:        "enables resources served by the cluster",
+			apiVersions: []string{"v1", "v1/ConfigMap", "monitoring.coreos.com/v1", "monitoring.coreos.com/v1/ServiceMonitor"},
+			want:        true,
+		},
+		{
+			name:        "disables resources not served by the cluster",
+			apiVersions: []string{"v1", "v1/ConfigMap"},
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mb := NewModuleBuilder(cuecontext.New(), "test-name", "test-namespace", moduleRoot, "main")
+			mb.SetAPIVersions(tt.apiVersions)
+
+			val, err := mb.Build()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			monitoring := val.LookupPath(cue.ParsePath(apiv1.ApplySelector.String() + ".all[0].data.monitoring"))
+			g.Expect(monitoring.Exists()).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestModuleBuilder_InvalidValues(t *testing.T) {
 	g := NewWithT(t)
 	moduleRoot := path.Join(t.TempDir(), "module")
@@ -134,3 +177,49 @@ func TestModuleBuilder_OverlayKeepsModuleDirUnchanged(t *testing.T) {
 	g.Expect(valuesAfter).To(Equal(valuesBefore))
 	g.Expect(path.Join(moduleRoot, "timoni.schema.cue")).ToNot(BeAnExistingFile())
 }
+
+func TestModuleBuilder_ValidateValues(t *testing.T) {
+	g := NewWithT(t)
+	moduleRoot := path.Join(t.TempDir(), "module")
+
+	err := CopyDir("testdata/module", moduleRoot, true)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx := cuecontext.New()
+	mb := NewModuleBuilder(ctx, "test-name", "test-namespace", moduleRoot, "main")
+	g.Expect(mb.OverlaySchemaFile()).ToNot(HaveOccurred())
+
+	t.Run("accepts values matching the schema", func(t *testing.T) {
+		g := NewWithT(t)
+		val := ctx.CompileString(`hostname: "test.internal"`)
+		g.Expect(mb.ValidateValues(val)).ToNot(HaveOccurred())
+	})
+
+	t.Run("reports all errors at the values position", func(t *testing.T) {
+		g := NewWithT(t)
+		val := ctx.CompileString(`
+hostname: 1
+metadata: name: 2
+`, cue.Filename("bundle.cue"))
+
+		err := mb.ValidateValues(val)
+		g.Expect(err).To(HaveOccurred())
+
+		var positions []string
+		for _, e := range cueerrors.Errors(err) {
+			for _, p := range e.InputPositions() {
+				positions = append(positions, p.String())
+			}
+		}
+		g.Expect(positions).To(ContainElements("bundle.cue:2:11", "bundle.cue:3:17"))
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		g := NewWithT(t)
+		val := ctx.CompileString(`hostnam: "test.internal"`, cue.Filename("bundle.cue"))
+
+		err := mb.ValidateValues(val)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(cueerrors.Details(err, nil)).To(Equal("values.hostnam: field not allowed:\n    bundle.cue:1:1\n"))
+	})
+}
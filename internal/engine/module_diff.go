@@ -0,0 +1,435 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// The kinds of changes found between two versions of a module.
+const (
+	ChangeFieldRemoved   = "field-removed"
+	ChangeFieldRenamed   = "field-renamed"
+	ChangeFieldAdded     = "field-added"
+	ChangeFieldRequired  = "field-required"
+	ChangeTypeNarrowed   = "type-narrowed"
+	ChangeTypeWidened    = "type-widened"
+	ChangeDefault        = "default-changed"
+	ChangeObjectAdded    = "object-added"
+	ChangeObjectRemoved  = "object-removed"
+	ChangeImmutableField = "immutable-field"
+)
+
+// ModuleChange describes a difference between two versions of a module.
+type ModuleChange struct {
+	// Kind is the category of the change e.g. 'field-removed'.
+	Kind string `json:"kind"`
+
+	// Subject is the config field in the `a: b:` form or
+	// the rendered object in the 'Kind/namespace/name' form.
+	Subject string `json:"subject"`
+
+	// Message describes the change.
+	Message string `json:"message"`
+
+	// Breaking is true if the instances of the previous version
+	// may fail to upgrade to the new version.
+	Breaking bool `json:"breaking"`
+}
+
+// String returns the change message prefixed with its kind
+// e.g. '[field-removed] field removed'.
+func (c ModuleChange) String() string {
+	return fmt.Sprintf("[%s] %s", c.Kind, c.Message)
+}
+
+// DiffConfig compares the #Config fields of two module versions, as returned
+// by GetConfigDoc. The fields must be built with the same CUE context.
+//
+// Removed fields, renamed fields, newly required fields and narrowed types
+// are breaking, since values accepted by the previous version are rejected
+// by the new one. Added optional fields, widened types and changed defaults
+// are not breaking.
+func DiffConfig(oldFields, newFields []ConfigField) []ModuleChange {
+	oldByKey := configFieldsByKey(oldFields)
+	newByKey := configFieldsByKey(newFields)
+
+	var changes []ModuleChange
+	var added []ConfigField
+	for _, f := range newFields {
+		if _, ok := oldByKey[f.Key()]; ok {
+			continue
+		}
+		if _, ok := oldByKey[parentKey(f.Key())]; !ok && parentKey(f.Key()) != "" {
+			// The parent is added too, only the parent and
+			// the fields that must be set with it are reported.
+			if !f.MustSet() || optionalParent(f.Key(), newByKey) {
+				continue
+			}
+		}
+		added = append(added, f)
+	}
+
+	renamed := make(map[string]bool)
+	for _, of := range oldFields {
+		key := of.Key()
+		if _, ok := newByKey[key]; ok {
+			changes = append(changes, diffConfigField(of, newByKey[key], oldFields, newFields)...)
+			continue
+		}
+		if removedParent(key, oldByKey, newByKey) {
+			continue
+		}
+
+		change := ModuleChange{
+			Kind:     ChangeFieldRemoved,
+			Subject:  key,
+			Message:  "field removed",
+			Breaking: true,
+		}
+		for _, nf := range added {
+			if !renamed[nf.Key()] && parentKey(nf.Key()) == parentKey(key) &&
+				nf.Type == of.Type && nf.Default == of.Default && nf.MustSet() == of.MustSet() {
+				renamed[nf.Key()] = true
+				change.Kind = ChangeFieldRenamed
+				change.Message = fmt.Sprintf("field renamed to '%s'", nf.Key())
+				break
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for _, f := range added {
+		if renamed[f.Key()] {
+			continue
+		}
+		if f.MustSet() {
+			changes = append(changes, ModuleChange{
+				Kind:     ChangeFieldRequired,
+				Subject:  f.Key(),
+				Message:  "required field added",
+				Breaking: true,
+			})
+			continue
+		}
+		changes = append(changes, ModuleChange{
+			Kind:    ChangeFieldAdded,
+			Subject: f.Key(),
+			Message: "field added",
+		})
+	}
+
+	return changes
+}
+
+// diffConfigField compares a field present in both module versions.
+func diffConfigField(of, nf ConfigField, oldFields, newFields []ConfigField) []ModuleChange {
+	var changes []ModuleChange
+	key := nf.Key()
+
+	if nf.MustSet() && !of.MustSet() {
+		changes = append(changes, ModuleChange{
+			Kind:     ChangeFieldRequired,
+			Subject:  key,
+			Message:  "field is now required",
+			Breaking: true,
+		})
+	}
+
+	// The structs with listed fields are compared field by field.
+	if !hasChildFields(key, oldFields) && !hasChildFields(key, newFields) &&
+		!of.Unresolved && !nf.Unresolved && typeString(of.Value) != typeString(nf.Value) {
+		switch {
+		case !subsumesType(nf.Value, of.Value):
+			changes = append(changes, ModuleChange{
+				Kind:     ChangeTypeNarrowed,
+				Subject:  key,
+				Message:  fmt.Sprintf("type narrowed from '%s' to '%s'", fieldType(of), fieldType(nf)),
+				Breaking: true,
+			})
+			return changes
+		case !subsumesType(of.Value, nf.Value):
+			changes = append(changes, ModuleChange{
+				Kind:    ChangeTypeWidened,
+				Subject: key,
+				Message: fmt.Sprintf("type widened from '%s' to '%s'", fieldType(of), fieldType(nf)),
+			})
+		}
+	}
+
+	if of.Default != nf.Default {
+		var msg string
+		switch {
+		case of.Default == "":
+			msg = fmt.Sprintf("default set to %s", nf.Default)
+		case nf.Default == "":
+			msg = fmt.Sprintf("default %s removed", of.Default)
+		default:
+			msg = fmt.Sprintf("default changed from %s to %s", of.Default, nf.Default)
+		}
+		changes = append(changes, ModuleChange{
+			Kind:    ChangeDefault,
+			Subject: key,
+			Message: msg,
+		})
+	}
+
+	return changes
+}
+
+// fieldType returns the field type with its default marked with `*`.
+func fieldType(f ConfigField) string {
+	if f.Default == "" || f.Default == f.Type {
+		return f.Type
+	}
+	return fmt.Sprintf("*%s | %s", f.Default, f.Type)
+}
+
+// typeString returns the evaluated type of the value without its default.
+// CUE can't tell if a validator such as strings.MaxRunes subsumes another,
+// so the identical types are found by comparing their string form.
+func typeString(v cue.Value) string {
+	var parts []string
+	for _, d := range typeDisjuncts(v) {
+		parts = append(parts, fmt.Sprint(d))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// subsumesType returns true if all the values accepted by the type of w
+// are accepted by the type of v. The defaults are ignored, a disjunction
+// subsumes another if each disjunct of w is subsumed by a disjunct of v.
+// The check is conservative, a type that can't be compared is reported as
+// narrowed.
+func subsumesType(v, w cue.Value) bool {
+	for _, wd := range typeDisjuncts(w) {
+		subsumed := false
+		for _, vd := range typeDisjuncts(v) {
+			if vd.Subsume(wd, cue.Schema()) == nil {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			return false
+		}
+	}
+	return true
+}
+
+// typeDisjuncts returns the disjuncts of a value with a default, which
+// CUE would otherwise replace with the default when comparing the values.
+func typeDisjuncts(v cue.Value) []cue.Value {
+	if _, hasDef := v.Default(); !hasDef {
+		return []cue.Value{v}
+	}
+	op, args := v.Expr()
+	switch op {
+	case cue.OrOp:
+		return flattenDisjuncts(v)
+	case cue.NoOp:
+		// A disjunction whose default is subsumed by another disjunct,
+		// such as `*1 | int`, is reported as the remaining disjunct.
+		if len(args) == 1 {
+			if _, hasDef := args[0].Default(); !hasDef {
+				return []cue.Value{args[0]}
+			}
+		}
+	case cue.AndOp:
+		// A constrained disjunction such as `(*1 | int) & >=0`
+		// is the conjunction of the constraints without the default.
+		var t cue.Value
+		for i, a := range args {
+			ds := typeDisjuncts(a)
+			if len(ds) != 1 {
+				return []cue.Value{v}
+			}
+			if i == 0 {
+				t = ds[0]
+				continue
+			}
+			t = t.Unify(ds[0])
+		}
+		return []cue.Value{t}
+	}
+	return []cue.Value{v}
+}
+
+// configFieldsByKey indexes the fields by their `a: b:` key.
+func configFieldsByKey(fields []ConfigField) map[string]ConfigField {
+	m := make(map[string]ConfigField, len(fields))
+	for _, f := range fields {
+		m[f.Key()] = f
+	}
+	return m
+}
+
+// parentKey returns the key of the struct containing the field,
+// or an empty string for the top-level fields.
+func parentKey(key string) string {
+	i := strings.LastIndex(strings.TrimSuffix(key, ":"), ": ")
+	if i < 0 {
+		return ""
+	}
+	return key[:i+1]
+}
+
+// hasChildFields returns true if the fields of the struct are listed.
+func hasChildFields(key string, fields []ConfigField) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(f.Key(), key+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// optionalParent returns true if the field is part of an optional struct.
+func optionalParent(key string, byKey map[string]ConfigField) bool {
+	for p := parentKey(key); p != ""; p = parentKey(p) {
+		if byKey[p].Optional {
+			return true
+		}
+	}
+	return false
+}
+
+// removedParent returns true if the field is part of a struct that was
+// removed, or of a struct whose fields are no longer listed because it
+// has a default in the new version.
+func removedParent(key string, oldByKey, newByKey map[string]ConfigField) bool {
+	for p := parentKey(key); p != ""; p = parentKey(p) {
+		if _, ok := newByKey[p]; ok {
+			return newByKey[p].Default != ""
+		}
+		if _, ok := oldByKey[p]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// immutableFields lists the fields that the Kubernetes API server refuses to
+// update, by object group and kind. Changing them requires the object to be
+// recreated.
+var immutableFields = map[string][][]string{
+	"apps/Deployment":  {{"spec", "selector"}},
+	"apps/ReplicaSet":  {{"spec", "selector"}},
+	"apps/DaemonSet":   {{"spec", "selector"}},
+	"apps/StatefulSet": {{"spec", "selector"}, {"spec", "serviceName"}, {"spec", "podManagementPolicy"}, {"spec", "volumeClaimTemplates"}},
+	"batch/Job":        {{"spec", "selector"}, {"spec", "template"}, {"spec", "completionMode"}},
+	"/Service":         {{"spec", "clusterIP"}},
+	"/PersistentVolumeClaim": {{"spec", "accessModes"}, {"spec", "storageClassName"},
+		{"spec", "volumeMode"}, {"spec", "volumeName"}, {"spec", "selector"}},
+	"rbac.authorization.k8s.io/RoleBinding":        {{"roleRef"}},
+	"rbac.authorization.k8s.io/ClusterRoleBinding": {{"roleRef"}},
+}
+
+// immutableDataFields are the fields of ConfigMaps and Secrets
+// that can't be updated when the object is marked as immutable.
+var immutableDataFields = [][]string{{"data"}, {"binaryData"}, {"stringData"}}
+
+// DiffObjects compares the objects rendered by two module versions.
+//
+// The changes made to immutable fields, such as the selector of a Deployment
+// or the volume claim templates of a StatefulSet, are breaking, since the
+// upgrade fails unless the object is annotated to be recreated. Added and
+// removed objects are not breaking.
+func DiffObjects(oldObjects, newObjects []*unstructured.Unstructured) []ModuleChange {
+	oldByRef := make(map[string]*unstructured.Unstructured, len(oldObjects))
+	for _, o := range oldObjects {
+		oldByRef[objectRef(o)] = o
+	}
+
+	var changes []ModuleChange
+	newRefs := make(map[string]bool, len(newObjects))
+	for _, n := range newObjects {
+		ref := objectRef(n)
+		newRefs[ref] = true
+		o, ok := oldByRef[ref]
+		if !ok {
+			changes = append(changes, ModuleChange{
+				Kind:    ChangeObjectAdded,
+				Subject: ssautil.FmtUnstructured(n),
+				Message: "object added",
+			})
+			continue
+		}
+
+		// The objects applied only if not present are never updated.
+		if n.GetAnnotations()[apiv1.IfNotPresentAction] == apiv1.EnabledValue {
+			continue
+		}
+
+		paths := immutableFields[n.GroupVersionKind().Group+"/"+n.GetKind()]
+		if n.GroupVersionKind().Group == "" && (n.GetKind() == "ConfigMap" || n.GetKind() == "Secret") {
+			if immutable, _, _ := unstructured.NestedBool(o.Object, "immutable"); immutable {
+				paths = immutableDataFields
+			}
+		}
+
+		for _, p := range paths {
+			ov, oFound, _ := unstructured.NestedFieldNoCopy(o.Object, p...)
+			nv, nFound, _ := unstructured.NestedFieldNoCopy(n.Object, p...)
+			if oFound == nFound && equality.Semantic.DeepEqual(ov, nv) {
+				continue
+			}
+
+			change := ModuleChange{
+				Kind:     ChangeImmutableField,
+				Subject:  ssautil.FmtUnstructured(n),
+				Message:  fmt.Sprintf("immutable field '%s' changed", strings.Join(p, ".")),
+				Breaking: true,
+			}
+			if n.GetAnnotations()[apiv1.ForceAction] == apiv1.EnabledValue {
+				change.Message += ", the object will be recreated"
+				change.Breaking = false
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	for _, o := range oldObjects {
+		if newRefs[objectRef(o)] {
+			continue
+		}
+		msg := "object removed, it will be deleted on upgrade"
+		if o.GetAnnotations()[apiv1.PruneAction] == apiv1.DisabledValue {
+			msg = "object removed, it will be left on the cluster on upgrade"
+		}
+		changes = append(changes, ModuleChange{
+			Kind:    ChangeObjectRemoved,
+			Subject: ssautil.FmtUnstructured(o),
+			Message: msg,
+		})
+	}
+
+	return changes
+}
+
+// objectRef returns the group, kind, namespace and name of the object.
+func objectRef(o *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s/%s", o.GroupVersionKind().Group, o.GetKind(), o.GetNamespace(), o.GetName())
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestModuleDiff_DiffConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldConfig string
+		newConfig string
+		want      []ModuleChange
+	}{
+		{
+			name:      "no changes",
+			oldConfig: `domain: *"example.com" | string`,
+			newConfig: `domain: *"example.com" | string`,
+		},
+		{
+			name:      "field removed",
+			oldConfig: `domain: *"example.com" | string, replicas: *1 | int`,
+			newConfig: `domain: *"example.com" | string`,
+			want: []ModuleChange{
+				{Kind: ChangeFieldRemoved, Subject: "replicas:", Message: "field removed", Breaking: true},
+			},
+		},
+		{
+			name:      "field renamed",
+			oldConfig: `image: {repo: *"nginx" | string}`,
+			newConfig: `image: {repository: *"nginx" | string}`,
+			want: []ModuleChange{
+				{Kind: ChangeFieldRenamed, Subject: "image: repo:", Message: "field renamed to 'image: repository:'", Breaking: true},
+			},
+		},
+		{
+			name:      "required field added",
+			oldConfig: `domain: *"example.com" | string`,
+			newConfig: `domain: *"example.com" | string, team!: string, logLevel?: string`,
+			want: []ModuleChange{
+				{Kind: ChangeFieldRequired, Subject: "team:", Message: "required field added", Breaking: true},
+				{Kind: ChangeFieldAdded, Subject: "logLevel:", Message: "field added"},
+			},
+		},
+		{
+			name:      "struct with required field added",
+			oldConfig: `domain: *"example.com" | string`,
+			newConfig: `domain: *"example.com" | string, db: {host!: string, port: *5432 | int}, tls?: {cert!: string}`,
+			want: []ModuleChange{
+				{Kind: ChangeFieldAdded, Subject: "db:", Message: "field added"},
+				{Kind: ChangeFieldRequired, Subject: "db: host:", Message: "required field added", Breaking: true},
+				{Kind: ChangeFieldAdded, Subject: "tls:", Message: "field added"},
+			},
+		},
+		{
+			name:      "default removed",
+			oldConfig: `domain: *"example.com" | string`,
+			newConfig: `domain: string`,
+			want: []ModuleChange{
+				{Kind: ChangeFieldRequired, Subject: "domain:", Message: "field is now required", Breaking: true},
+				{Kind: ChangeDefault, Subject: "domain:", Message: `default "example.com" removed`},
+			},
+		},
+		{
+			name:      "type narrowed",
+			oldConfig: `port: *8080 | int & >0`,
+			newConfig: `port: *8080 | int & >1024`,
+			want: []ModuleChange{
+				{Kind: ChangeTypeNarrowed, Subject: "port:", Message: "type narrowed from '*8080 | int & >0' to '*8080 | int & >1024'", Breaking: true},
+			},
+		},
+		{
+			name:      "constraint narrowed",
+			oldConfig: `priority: (*1 | int) & >=0`,
+			newConfig: `priority: (*1 | int) & >=1`,
+			want: []ModuleChange{
+				{Kind: ChangeTypeNarrowed, Subject: "priority:", Message: "type narrowed from '*1 | int & >=0' to '*1 | int & >=1'", Breaking: true},
+			},
+		},
+		{
+			name:      "enum narrowed",
+			oldConfig: `logLevel?: "info" | "debug"`,
+			newConfig: `logLevel?: "info"`,
+			want: []ModuleChange{
+				{Kind: ChangeTypeNarrowed, Subject: "logLevel:", Message: `type narrowed from '"info" | "debug"' to '"info"'`, Breaking: true},
+			},
+		},
+		{
+			name:      "type widened and default changed",
+			oldConfig: `logLevel: *"info" | "debug"`,
+			newConfig: `logLevel: *"debug" | "info" | "warn"`,
+			want: []ModuleChange{
+				{Kind: ChangeTypeWidened, Subject: "logLevel:", Message: `type widened from '*"info" | "debug"' to '*"debug" | "info" | "warn"'`},
+				{Kind: ChangeDefault, Subject: "logLevel:", Message: `default changed from "info" to "debug"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+
+			oldFields := testConfigFields(g, ctx, t.TempDir(), tt.oldConfig)
+			newFields := testConfigFields(g, ctx, t.TempDir(), tt.newConfig)
+
+			changes := DiffConfig(oldFields, newFields)
+			if tt.want == nil {
+				g.Expect(changes).To(BeEmpty())
+				return
+			}
+			g.Expect(changes).To(Equal(tt.want))
+		})
+	}
+}
+
+func testConfigFields(g *WithT, ctx *cue.Context, root, config string) []ConfigField {
+	src := "#Config: {" + config + "}\nvalues: #Config\n"
+	v := ctx.CompileString(src, cue.Filename(filepath.Join(root, "config.cue")))
+	g.Expect(v.Err()).ToNot(HaveOccurred())
+
+	b := NewModuleBuilder(ctx, "test", "default", root, defaultPackage)
+	fields, err := b.GetConfigDoc(v)
+	g.Expect(err).ToNot(HaveOccurred())
+	return fields
+}
+
+func TestModuleDiff_DiffObjects(t *testing.T) {
+	deployment := func(app string, annotations map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":        "app",
+				"namespace":   "default",
+				"annotations": annotations,
+			},
+			"spec": map[string]any{
+				"replicas": int64(1),
+				"selector": map[string]any{
+					"matchLabels": map[string]any{"app": app},
+				},
+			},
+		}}
+	}
+	service := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "app", "namespace": "default"},
+	}}
+	configMap := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"immutable":  true,
+			"data":       map[string]any{"name": name},
+		}}
+	}
+
+	t.Run("selector changed", func(t *testing.T) {
+		g := NewWithT(t)
+		old := deployment("app", nil)
+		changed := deployment("app-v2", nil)
+		changed.Object["spec"].(map[string]any)["replicas"] = int64(2)
+
+		changes := DiffObjects([]*unstructured.Unstructured{old}, []*unstructured.Unstructured{changed})
+		g.Expect(changes).To(Equal([]ModuleChange{{
+			Kind:     ChangeImmutableField,
+			Subject:  "Deployment/default/app",
+			Message:  "immutable field 'spec.selector' changed",
+			Breaking: true,
+		}}))
+	})
+
+	t.Run("selector changed with force", func(t *testing.T) {
+		g := NewWithT(t)
+		old := deployment("app", nil)
+		changed := deployment("app-v2", map[string]any{"action.timoni.sh/force": "enabled"})
+
+		changes := DiffObjects([]*unstructured.Unstructured{old}, []*unstructured.Unstructured{changed})
+		g.Expect(changes).To(HaveLen(1))
+		g.Expect(changes[0].Breaking).To(BeFalse())
+		g.Expect(changes[0].Message).To(ContainSubstring("will be recreated"))
+	})
+
+	t.Run("immutable config map", func(t *testing.T) {
+		g := NewWithT(t)
+		old := configMap("app")
+		changed := configMap("app")
+		changed.Object["data"] = map[string]any{"name": "changed"}
+
+		changes := DiffObjects([]*unstructured.Unstructured{old}, []*unstructured.Unstructured{changed})
+		g.Expect(changes).To(HaveLen(1))
+		g.Expect(changes[0].Message).To(Equal("immutable field 'data' changed"))
+		g.Expect(changes[0].Breaking).To(BeTrue())
+	})
+
+	t.Run("objects added and removed", func(t *testing.T) {
+		g := NewWithT(t)
+		changes := DiffObjects(
+			[]*unstructured.Unstructured{deployment("app", nil), service},
+			[]*unstructured.Unstructured{deployment("app", nil), configMap("app")},
+		)
+		g.Expect(changes).To(Equal([]ModuleChange{
+			{Kind: ChangeObjectAdded, Subject: "ConfigMap/default/app", Message: "object added"},
+			{Kind: ChangeObjectRemoved, Subject: "Service/default/app", Message: "object removed, it will be deleted on upgrade"},
+		}))
+	})
+}
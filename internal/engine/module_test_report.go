@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the test results in the JUnit XML format,
+// with the module test cases grouped in a test suite named after the module.
+func WriteJUnitReport(w io.Writer, module string, results []ModuleTestResult) error {
+	suite := junitTestSuite{
+		Name:  module,
+		Tests: len(results),
+	}
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		tc := junitTestCase{
+			Name:      r.Name,
+			ClassName: module,
+			Time:      formatSeconds(r.Duration),
+		}
+		if !r.Passed() {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message:  fmt.Sprintf("%d failure(s)", len(r.Failures)),
+				Contents: strings.Join(r.Failures, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = formatSeconds(total)
+
+	report := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("encoding the JUnit report failed: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTAPReport writes the test results in the TAP version 13 format,
+// with the failures of each test case listed in a YAML diagnostic block.
+func WriteTAPReport(w io.Writer, results []ModuleTestResult) error {
+	var sb strings.Builder
+	sb.WriteString("TAP version 13\n")
+	sb.WriteString(fmt.Sprintf("1..%d\n", len(results)))
+
+	for i, r := range results {
+		status := "ok"
+		if !r.Passed() {
+			status = "not ok"
+		}
+		sb.WriteString(fmt.Sprintf("%s %d - %s\n", status, i+1, r.Name))
+
+		if r.Passed() {
+			continue
+		}
+		sb.WriteString("  ---\n")
+		sb.WriteString(fmt.Sprintf("  duration_ms: %d\n", r.Duration.Milliseconds()))
+		sb.WriteString("  failures:\n")
+		for _, f := range r.Failures {
+			sb.WriteString("    - |\n")
+			for _, line := range strings.Split(f, "\n") {
+				sb.WriteString("      " + line + "\n")
+			}
+		}
+		sb.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fluxcd/pkg/ssa"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stefanprodan/timoni/internal/dyff"
+)
+
+// RenderSnapshot returns the objects of all apply sets as a multi-document
+// YAML, sorted in the apply order and then by namespace and name, so that
+// the snapshot does not change unless the objects do.
+func RenderSnapshot(sets []ResourceSet) ([]byte, error) {
+	var objects []*unstructured.Unstructured
+	for _, set := range sets {
+		objects = append(objects, set.Objects...)
+	}
+	sort.Sort(ssa.SortableUnstructureds(objects))
+
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("converting objects failed: %w", err)
+		}
+		if i != 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// checkSnapshot compares the rendered objects of the test case with its
+// snapshot, and returns the differences as a failure. When updating, the
+// snapshot is written instead.
+func (t *ModuleTester) checkSnapshot(name string, sets []ResourceSet) []string {
+	rendered, err := RenderSnapshot(sets)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	snapshotFile := filepath.Join(t.snapshotDir, name+".yaml")
+	if t.updateSnapshots {
+		if err := os.MkdirAll(t.snapshotDir, 0o755); err != nil {
+			return []string{fmt.Sprintf("writing the snapshot failed: %s", err)}
+		}
+		if err := os.WriteFile(snapshotFile, rendered, 0o644); err != nil {
+			return []string{fmt.Sprintf("writing the snapshot failed: %s", err)}
+		}
+		return nil
+	}
+
+	snapshot, err := os.ReadFile(snapshotFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{fmt.Sprintf("snapshot %s not found, run with --update to create it", snapshotFile)}
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("reading the snapshot failed: %s", err)}
+	}
+
+	if bytes.Equal(snapshot, rendered) {
+		return nil
+	}
+
+	diff, err := diffSnapshot(snapshotFile, rendered)
+	if err != nil {
+		return []string{fmt.Sprintf("snapshot %s does not match: %s", snapshotFile, err)}
+	}
+	return []string{fmt.Sprintf("snapshot %s does not match, run with --update to accept the changes:\n%s",
+		snapshotFile, diff)}
+}
+
+// diffSnapshot returns the dyff report of the changes
+// between the snapshot file and the rendered objects.
+func diffSnapshot(snapshotFile string, rendered []byte) (string, error) {
+	tmpFile, err := os.CreateTemp("", "snapshot-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(rendered); err != nil {
+		tmpFile.Close()
+		return "", err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := dyff.DiffYAML(snapshotFile, tmpFile.Name(), &buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
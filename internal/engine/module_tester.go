@@ -0,0 +1,321 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// ModuleTest is a test case found in the module's tests directory.
+type ModuleTest struct {
+	// Name of the test case, derived from the file name.
+	Name string
+
+	// Path of the test case file.
+	Path string
+}
+
+// ModuleTestResult holds the outcome of a module test case.
+type ModuleTestResult struct {
+	// Name of the test case.
+	Name string
+
+	// Description of the test case, if any.
+	Description string
+
+	// Duration of the test case run.
+	Duration time.Duration
+
+	// Failures lists the assertions and expectations not met by the module.
+	Failures []string
+}
+
+// Passed returns true if the test case has no failures.
+func (r ModuleTestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// FindModuleTests returns the test cases found in the given directory
+// sorted by name, with every CUE file holding a test case. It returns
+// nil when the directory does not exist.
+func FindModuleTests(dir string) ([]ModuleTest, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading the test cases failed: %w", err)
+	}
+
+	var tests []ModuleTest
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".cue" {
+			continue
+		}
+		tests = append(tests, ModuleTest{
+			Name: strings.TrimSuffix(entry.Name(), ".cue"),
+			Path: filepath.Join(dir, entry.Name()),
+		})
+	}
+	return tests, nil
+}
+
+// ModuleTester runs the test cases of a module. Every test case is built
+// with its own CUE context and ModuleBuilder, so that test cases can run
+// concurrently.
+type ModuleTester struct {
+	name            string
+	namespace       string
+	moduleRoot      string
+	pkgName         string
+	snapshotDir     string
+	updateSnapshots bool
+}
+
+// NewModuleTester creates a ModuleTester for the given module and package.
+func NewModuleTester(name, namespace, moduleRoot, pkgName string) *ModuleTester {
+	return &ModuleTester{
+		name:       name,
+		namespace:  namespace,
+		moduleRoot: moduleRoot,
+		pkgName:    pkgName,
+	}
+}
+
+// SetSnapshots enables the comparison of the rendered objects with the
+// snapshots stored in the given directory. When update is true, the
+// snapshots are rewritten with the rendered objects instead.
+func (t *ModuleTester) SetSnapshots(dir string, update bool) {
+	t.snapshotDir = dir
+	t.updateSnapshots = update
+}
+
+// Run builds the module with the values of the test case and checks
+// the expected build error, the assertions, the health check results
+// and the snapshot, if enabled.
+// Errors in the test case itself are reported as failures.
+func (t *ModuleTester) Run(test ModuleTest) ModuleTestResult {
+	start := time.Now()
+	result := ModuleTestResult{Name: test.Name}
+	result.Failures = t.run(test, &result)
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (t *ModuleTester) run(test ModuleTest, result *ModuleTestResult) []string {
+	ctx := cuecontext.New()
+
+	tc, err := loadTestCase(ctx, test.Path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if v := tc.LookupPath(cue.ParsePath(apiv1.TestDescriptionSelector.String())); v.Exists() {
+		result.Description, _ = v.String()
+	}
+
+	var expectError string
+	if v := tc.LookupPath(cue.ParsePath(apiv1.TestExpectErrorSelector.String())); v.Exists() {
+		if expectError, err = v.String(); err != nil {
+			return []string{fmt.Sprintf("reading %s failed: %s", apiv1.TestExpectErrorSelector, err)}
+		}
+	}
+
+	builder := NewModuleBuilder(ctx, t.name, t.namespace, t.moduleRoot, t.pkgName)
+	buildResult, sets, err := t.build(builder, tc)
+	if expectError != "" {
+		switch {
+		case err == nil:
+			return []string{fmt.Sprintf("expected the build to fail with %q, but it succeeded", expectError)}
+		case !strings.Contains(err.Error(), expectError):
+			return []string{fmt.Sprintf("expected the build to fail with %q, got:\n%s", expectError, err)}
+		}
+		return nil
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	failures := checkAssertions(tc, sets)
+	failures = append(failures, checkHealthResults(builder, buildResult, tc)...)
+	if t.snapshotDir != "" {
+		failures = append(failures, t.checkSnapshot(test.Name, sets)...)
+	}
+	return failures
+}
+
+// build overlays the test values and builds the module. Build errors are
+// returned with their details, for the expected error to match any of them.
+func (t *ModuleTester) build(builder *ModuleBuilder, tc cue.Value) (cue.Value, []ResourceSet, error) {
+	var value cue.Value
+	if err := builder.OverlaySchemaFile(); err != nil {
+		return value, nil, err
+	}
+
+	if v := tc.LookupPath(cue.ParsePath(apiv1.TestValuesSelector.String())); v.Exists() {
+		overlay := fmt.Sprintf("%s: %v", apiv1.ValuesSelector, v)
+		if err := builder.OverlayValuesFile([][]byte{[]byte(overlay)}); err != nil {
+			return value, nil, t.describeErr("invalid values", err)
+		}
+	}
+
+	value, err := builder.Build()
+	if err != nil {
+		return value, nil, t.describeErr("build failed", err)
+	}
+
+	sets, err := builder.GetApplySets(value)
+	if err != nil {
+		return value, nil, t.describeErr("build failed", err)
+	}
+	return value, sets, nil
+}
+
+func (t *ModuleTester) describeErr(description string, err error) error {
+	return fmt.Errorf("%s:\n%s", description, strings.TrimSpace(cueerrors.Details(err, &cueerrors.Config{
+		Cwd: t.moduleRoot,
+	})))
+}
+
+// loadTestCase compiles the test case file and validates it against the
+// #TestCase schema. The schema and the apply field are appended to the
+// file, so that the assertions can refer to the objects filled in after
+// the build.
+func loadTestCase(ctx *cue.Context, path string) (cue.Value, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cue.Value{}, err
+	}
+
+	src := fmt.Sprintf("%s\n%s\n%s: _\n", data, apiv1.TestCaseSchema, apiv1.TestApplySelector)
+	tc := ctx.CompileString(src, cue.Filename(path))
+	tc = tc.Unify(tc.LookupPath(cue.MakePath(cue.Def("#TestCase"))))
+	if err := tc.Validate(); err != nil {
+		return tc, fmt.Errorf("invalid test case:\n%s", strings.TrimSpace(cueerrors.Details(err, nil)))
+	}
+	return tc, nil
+}
+
+// checkAssertions fills the test case with the rendered objects
+// and returns the assertions that do not evaluate to true.
+func checkAssertions(tc cue.Value, sets []ResourceSet) []string {
+	apply := make(map[string][]any, len(sets))
+	for _, set := range sets {
+		objects := make([]any, 0, len(set.Objects))
+		for _, obj := range set.Objects {
+			objects = append(objects, obj.Object)
+		}
+		apply[set.Name] = objects
+	}
+
+	tc = tc.FillPath(cue.ParsePath(apiv1.TestApplySelector.String()), apply)
+	if err := tc.Err(); err != nil {
+		return []string{fmt.Sprintf("filling %s failed: %s", apiv1.TestApplySelector, err)}
+	}
+
+	assertions := tc.LookupPath(cue.ParsePath(apiv1.TestAssertSelector.String()))
+	if !assertions.Exists() {
+		return nil
+	}
+
+	iter, err := assertions.Fields()
+	if err != nil {
+		return []string{fmt.Sprintf("reading %s failed: %s", apiv1.TestAssertSelector, err)}
+	}
+
+	var failures []string
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		ok, err := iter.Value().Bool()
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Sprintf("assertion %q failed: %s", name, err))
+		case !ok:
+			failures = append(failures, fmt.Sprintf("assertion %q failed", name))
+		}
+	}
+	return failures
+}
+
+// checkHealthResults evaluates the module health checks against
+// the objects of the test case and returns the unexpected results.
+func checkHealthResults(builder *ModuleBuilder, value cue.Value, tc cue.Value) []string {
+	expected := tc.LookupPath(cue.ParsePath(apiv1.TestHealthChecksSelector.String()))
+	if !expected.Exists() {
+		return nil
+	}
+
+	var results []apiv1.TestHealthCheck
+	if err := expected.Decode(&results); err != nil {
+		return []string{fmt.Sprintf("decoding %s failed: %s", apiv1.TestHealthChecksSelector, err)}
+	}
+
+	checks, err := builder.GetHealthChecks(value)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var failures []string
+	for i, result := range results {
+		apiVersion, _ := result.Object["apiVersion"].(string)
+		kind, _ := result.Object["kind"].(string)
+		gk := schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind()
+
+		check := findHealthCheck(checks, gk)
+		if check == nil {
+			failures = append(failures, fmt.Sprintf("%s[%d]: no health check found for %s", apiv1.TestHealthChecksSelector, i, gk))
+			continue
+		}
+
+		status, err := check.Evaluate(result.Object)
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Sprintf("%s[%d]: %s", apiv1.TestHealthChecksSelector, i, err))
+		case string(status) != result.Status:
+			failures = append(failures, fmt.Sprintf("%s[%d]: health check %q returned %s, expected %s",
+				apiv1.TestHealthChecksSelector, i, check.Name, status, result.Status))
+		}
+	}
+	return failures
+}
+
+// findHealthCheck returns the health check targeting the GroupKind,
+// falling back to the one targeting all the kinds of the group.
+func findHealthCheck(checks []*HealthCheck, gk schema.GroupKind) *HealthCheck {
+	if i := slices.IndexFunc(checks, func(hc *HealthCheck) bool { return hc.GroupKind == gk }); i >= 0 {
+		return checks[i]
+	}
+	groupKind := schema.GroupKind{Group: gk.Group}
+	if i := slices.IndexFunc(checks, func(hc *HealthCheck) bool { return hc.GroupKind == groupKind }); i >= 0 {
+		return checks[i]
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestModuleTester(t *testing.T) {
+	g := NewWithT(t)
+	moduleRoot, err := filepath.Abs("testdata/module-tests")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tests, err := FindModuleTests(filepath.Join(moduleRoot, "tests"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tests).To(HaveLen(4))
+	g.Expect(tests[0].Name).To(Equal("defaults"))
+
+	tester := NewModuleTester("test", "default", moduleRoot, "main")
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			result := tester.Run(tc)
+			g.Expect(result.Failures).To(BeEmpty())
+			g.Expect(result.Passed()).To(BeTrue())
+			g.Expect(result.Description).ToNot(BeEmpty())
+		})
+	}
+}
+
+func TestModuleTester_Failures(t *testing.T) {
+	moduleRoot, err := filepath.Abs("testdata/module-tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tester := NewModuleTester("test", "default", moduleRoot, "main")
+
+	tests := []struct {
+		name     string
+		testCase string
+		failures []string
+	}{
+		{
+			name: "false assertion",
+			testCase: `
+values: message: "hi"
+assert: "sets the message": apply.app[0].data.message == "hello"
+`,
+			failures: []string{`assertion "sets the message" failed`},
+		},
+		{
+			name: "incomplete assertion",
+			testCase: `
+assert: "has a service": apply.svc[0].kind == "Service"
+`,
+			failures: []string{`assertion "has a service" failed:`},
+		},
+		{
+			name: "build succeeds unexpectedly",
+			testCase: `
+values: replicas: 2
+expectError: "invalid value"
+`,
+			failures: []string{`expected the build to fail with "invalid value", but it succeeded`},
+		},
+		{
+			name: "build fails unexpectedly",
+			testCase: `
+values: replicas: 0
+`,
+			failures: []string{"invalid value 0"},
+		},
+		{
+			name: "unexpected health status",
+			testCase: `
+healthChecks: [{
+	object: {
+		apiVersion: "testing.timoni.sh/v1alpha1"
+		kind:       "Demo"
+		spec: replicas: 1
+		status: readyReplicas: -1
+	}
+	status: "Current"
+}]
+`,
+			failures: []string{`healthChecks[0]: health check "demo" returned Failed, expected Current`},
+		},
+		{
+			name: "missing health check",
+			testCase: `
+healthChecks: [{
+	object: {
+		apiVersion: "example.com/v1"
+		kind:       "Other"
+	}
+	status: "Current"
+}]
+`,
+			failures: []string{"healthChecks[0]: no health check found for Other.example.com"},
+		},
+		{
+			name: "unknown field",
+			testCase: `
+asserts: "typo": true
+`,
+			failures: []string{"invalid test case"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			path := filepath.Join(t.TempDir(), "case.cue")
+			g.Expect(os.WriteFile(path, []byte(tt.testCase), 0o600)).To(Succeed())
+
+			result := tester.Run(ModuleTest{Name: "case", Path: path})
+			g.Expect(result.Passed()).To(BeFalse())
+			g.Expect(result.Failures).To(HaveLen(len(tt.failures)))
+			for i, msg := range tt.failures {
+				g.Expect(result.Failures[i]).To(ContainSubstring(msg))
+			}
+		})
+	}
+}
+
+func TestModuleTestReports(t *testing.T) {
+	results := []ModuleTestResult{
+		{Name: "defaults", Duration: 1500 * time.Millisecond},
+		{Name: "replicas", Duration: 20 * time.Millisecond, Failures: []string{
+			`assertion "sets the replicas" failed`,
+			"build failed:\nvalues.replicas: invalid value 0",
+		}},
+	}
+
+	t.Run("junit", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		g.Expect(WriteJUnitReport(&buf, "podinfo", results)).To(Succeed())
+		g.Expect(buf.String()).To(ContainSubstring(`<testsuite name="podinfo" tests="2" failures="1" time="1.520">`))
+		g.Expect(buf.String()).To(ContainSubstring(`<testcase name="defaults" classname="podinfo" time="1.500"></testcase>`))
+		g.Expect(buf.String()).To(ContainSubstring(`<failure message="2 failure(s)">assertion &#34;sets the replicas&#34; failed&#xA;build failed:`))
+	})
+
+	t.Run("tap", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var buf bytes.Buffer
+		g.Expect(WriteTAPReport(&buf, results)).To(Succeed())
+		g.Expect(buf.String()).To(Equal(`TAP version 13
+1..2
+ok 1 - defaults
+not ok 2 - replicas
+  ---
+  duration_ms: 20
+  failures:
+    - |
+      assertion "sets the replicas" failed
+    - |
+      build failed:
+      values.replicas: invalid value 0
+  ...
+`))
+	})
+}
+
+func TestModuleTester_Snapshots(t *testing.T) {
+	g := NewWithT(t)
+	moduleRoot, err := filepath.Abs("testdata/module-tests")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	test := ModuleTest{Name: "replicas", Path: filepath.Join(moduleRoot, "tests", "replicas.cue")}
+	snapshotDir := filepath.Join(t.TempDir(), "__snapshots__")
+	snapshotFile := filepath.Join(snapshotDir, "replicas.yaml")
+	tester := NewModuleTester("test", "default", moduleRoot, "main")
+
+	t.Run("fails without snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+		tester.SetSnapshots(snapshotDir, false)
+		result := tester.Run(test)
+		g.Expect(result.Failures).To(ConsistOf(ContainSubstring("run with --update to create it")))
+	})
+
+	t.Run("writes the snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+		tester.SetSnapshots(snapshotDir, true)
+		result := tester.Run(test)
+		g.Expect(result.Failures).To(BeEmpty())
+
+		data, err := os.ReadFile(snapshotFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(data)).To(HavePrefix("apiVersion: v1\ndata:\n  message: hello\nkind: ConfigMap\n"))
+		g.Expect(string(data)).To(ContainSubstring("---\napiVersion: testing.timoni.sh/v1alpha1\nkind: Demo\n"))
+	})
+
+	t.Run("matches the snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+		tester.SetSnapshots(snapshotDir, false)
+		result := tester.Run(test)
+		g.Expect(result.Failures).To(BeEmpty())
+	})
+
+	t.Run("reports the snapshot changes", func(t *testing.T) {
+		g := NewWithT(t)
+		data, err := os.ReadFile(snapshotFile)
+		g.Expect(err).ToNot(HaveOccurred())
+		data = bytes.Replace(data, []byte("replicas: 3"), []byte("replicas: 2"), 1)
+		g.Expect(os.WriteFile(snapshotFile, data, 0o644)).To(Succeed())
+
+		tester.SetSnapshots(snapshotDir, false)
+		result := tester.Run(test)
+		g.Expect(result.Failures).To(HaveLen(1))
+		g.Expect(result.Failures[0]).To(ContainSubstring("does not match"))
+		g.Expect(result.Failures[0]).To(ContainSubstring("spec.replicas"))
+	})
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/parser"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// PolicyRule holds a CUE policy rule loaded from a policy pack.
+type PolicyRule struct {
+	// Name is the rule's key under 'rules:'.
+	Name string
+
+	// Pack is the name of the policy pack defining the rule.
+	Pack string
+
+	// Group of the target objects, '*' matches every group.
+	Group string
+
+	// Kind of the target objects, '*' matches every kind.
+	Kind string
+
+	// Severity is either deny or warn.
+	Severity string
+
+	// Message is reported for the objects violating the rule.
+	Message string
+
+	// AllowIncomplete is set if an allow expression that cannot be
+	// evaluated, because the referenced fields are missing from the
+	// object, counts as true instead of a violation.
+	AllowIncomplete bool
+
+	value cue.Value
+}
+
+// Matches returns true if the rule targets the object's group and kind.
+func (r *PolicyRule) Matches(object *unstructured.Unstructured) bool {
+	gvk := object.GroupVersionKind()
+	return (r.Group == apiv1.PolicyMatchAny || r.Group == gvk.Group) &&
+		(r.Kind == apiv1.PolicyMatchAny || r.Kind == gvk.Kind)
+}
+
+// PolicyViolation holds the object that violates a policy rule.
+type PolicyViolation struct {
+	// Rule is the violated policy rule.
+	Rule *PolicyRule
+
+	// Object is the rendered object violating the rule.
+	Object *unstructured.Unstructured
+
+	// Incomplete is set if the allow expression could not be evaluated,
+	// because the referenced fields are missing from the object.
+	Incomplete bool
+}
+
+// Denied returns true if the violation must fail the run.
+func (v PolicyViolation) Denied() bool {
+	return v.Rule.Severity == apiv1.PolicySeverityDeny
+}
+
+// PolicySet holds the rules of the policy packs loaded with LoadPack.
+// It is safe for concurrent use, as the rules are evaluated one at a time.
+type PolicySet struct {
+	ctx   *cue.Context
+	rules []*PolicyRule
+	mu    sync.Mutex
+}
+
+// NewPolicySet creates an empty PolicySet using the given CUE context.
+func NewPolicySet(ctx *cue.Context) *PolicySet {
+	return &PolicySet{ctx: ctx}
+}
+
+// Rules returns the loaded policy rules.
+func (p *PolicySet) Rules() []*PolicyRule {
+	return p.rules
+}
+
+// LoadPack loads the policy rules defined under 'rules:' in the CUE files
+// found in the directory. The files must belong to the same package,
+// for the rules to share definitions across files. Only the CUE standard
+// library can be imported.
+func (p *PolicySet) LoadPack(name, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading the policy pack %s failed: %w", name, err)
+	}
+
+	root := NewVirtualRoot()
+	overlay := make(map[string]load.Source)
+	pkgName := "_"
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".cue" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("reading the policy pack %s failed: %w", name, err)
+		}
+
+		file, err := parser.ParseFile(entry.Name(), content, parser.PackageClauseOnly)
+		if err != nil {
+			return fmt.Errorf("policy pack %s: failed to parse %s: %w", name, entry.Name(), err)
+		}
+		if pkg := file.PackageName(); pkg != "" {
+			pkgName = pkg
+		}
+
+		dst := filepath.Join(root, entry.Name())
+		overlay[dst] = load.FromBytes(content)
+		files = append(files, dst)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("policy pack %s: no CUE files found in %s", name, dir)
+	}
+
+	// The generated file name starts with a dot,
+	// so that it cannot collide with the pack files.
+	schemaFile := filepath.Join(root, ".policy.schema.cue")
+	schema := apiv1.PolicySchema
+	if pkgName != "_" {
+		schema = fmt.Sprintf("package %s\n%s", pkgName, schema)
+	}
+	overlay[schemaFile] = load.FromString(schema)
+	files = append(files, schemaFile)
+
+	ix := load.Instances(files, &load.Config{
+		Package: pkgName,
+		Overlay: overlay,
+		Dir:     root,
+	})
+	if len(ix) == 0 {
+		return fmt.Errorf("policy pack %s: no instances found", name)
+	}
+	if ix[0].Err != nil {
+		return fmt.Errorf("policy pack %s: %w", name, ix[0].Err)
+	}
+
+	v := p.ctx.BuildInstance(ix[0])
+	if err := v.Err(); err != nil {
+		return fmt.Errorf("policy pack %s is invalid:\n%s", name, cueerrors.Details(err, &cueerrors.Config{Cwd: root}))
+	}
+
+	rules, err := loadPolicyRules(name, v)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = append(p.rules, rules...)
+	return nil
+}
+
+func loadPolicyRules(pack string, v cue.Value) ([]*PolicyRule, error) {
+	rulesValue := v.LookupPath(cue.ParsePath(apiv1.PolicyRulesSelector.String()))
+	if !rulesValue.Exists() {
+		return nil, fmt.Errorf("policy pack %s: no rules found", pack)
+	}
+
+	iter, err := rulesValue.Fields()
+	if err != nil {
+		return nil, fmt.Errorf("policy pack %s: reading %s failed: %w", pack, apiv1.PolicyRulesSelector, err)
+	}
+
+	var rules []*PolicyRule
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		rule := iter.Value()
+
+		// Validate as final so that missing required fields
+		// e.g. the message are reported.
+		if err := rule.Validate(cue.Final()); err != nil {
+			return nil, fmt.Errorf("policy pack %s: rule %q is invalid: %w", pack, name, err)
+		}
+
+		r := &PolicyRule{Name: name, Pack: pack, value: rule}
+		if r.AllowIncomplete, err = rule.LookupPath(cue.ParsePath("allowIncomplete")).Bool(); err != nil {
+			return nil, fmt.Errorf("policy pack %s: rule %q: reading allowIncomplete failed: %w", pack, name, err)
+		}
+		for _, field := range []struct {
+			path string
+			dst  *string
+		}{
+			{"match.group", &r.Group},
+			{"match.kind", &r.Kind},
+			{"severity", &r.Severity},
+			{"message", &r.Message},
+		} {
+			if *field.dst, err = rule.LookupPath(cue.ParsePath(field.path)).String(); err != nil {
+				return nil, fmt.Errorf("policy pack %s: rule %q: reading %s failed: %w", pack, name, field.path, err)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Check evaluates the rules against the objects they match and returns the
+// violations sorted by object in the input order, then by pack and rule name.
+func (p *PolicySet) Check(objects []*unstructured.Unstructured) ([]PolicyViolation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rules := make([]*PolicyRule, len(p.rules))
+	copy(rules, p.rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Pack != rules[j].Pack {
+			return rules[i].Pack < rules[j].Pack
+		}
+		return rules[i].Name < rules[j].Name
+	})
+
+	var violations []PolicyViolation
+	for _, object := range objects {
+		for _, rule := range rules {
+			if !rule.Matches(object) {
+				continue
+			}
+			allowed, incomplete, err := rule.evaluate(object.Object)
+			if err != nil {
+				return nil, err
+			}
+			if incomplete && rule.AllowIncomplete {
+				continue
+			}
+			if !allowed {
+				violations = append(violations, PolicyViolation{Rule: rule, Object: object, Incomplete: incomplete})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// evaluate fills the rule's #object with the object content and evaluates
+// the allow expression. An expression that cannot be evaluated because the
+// referenced fields are missing from the object is reported as incomplete
+// and not allowed; any other evaluation failure returns an error.
+func (r *PolicyRule) evaluate(object map[string]any) (allowed bool, incomplete bool, err error) {
+	val := r.value.FillPath(cue.MakePath(cue.Def("#object")), object)
+	if err := val.Err(); err != nil {
+		return false, false, fmt.Errorf("policy rule %s/%s: the object does not match the #object schema: %w", r.Pack, r.Name, err)
+	}
+
+	v := val.LookupPath(cue.ParsePath("allow"))
+	allowed, err = v.Bool()
+	if err != nil {
+		if !v.IsConcrete() && v.Validate() == nil {
+			// The expression is incomplete: it refers
+			// to fields the object does not have.
+			return false, true, nil
+		}
+		return false, false, fmt.Errorf("policy rule %s/%s: evaluating the allow expression failed: %w", r.Pack, r.Name, err)
+	}
+	return allowed, false, nil
+}
+
+// String returns the violation message prefixed with the policy
+// pack and rule name e.g. '[baseline/no-privileged] message'.
+// Incomplete evaluations are marked, as the object may comply
+// with the rule once the missing fields are set.
+func (v PolicyViolation) String() string {
+	msg := fmt.Sprintf("[%s/%s] %s", v.Rule.Pack, v.Rule.Name, strings.TrimSpace(v.Rule.Message))
+	if v.Incomplete {
+		msg += " (incomplete: the object is missing the fields referenced by allow)"
+	}
+	return msg
+}
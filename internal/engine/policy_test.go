@@ -0,0 +1,167 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPolicySet(t *testing.T) {
+	g := NewWithT(t)
+
+	policies := NewPolicySet(cuecontext.New())
+	g.Expect(policies.LoadPack("baseline", "testdata/policy/baseline")).To(Succeed())
+	g.Expect(policies.Rules()).To(HaveLen(2))
+
+	deployment := func(name, image string, privileged bool) *unstructured.Unstructured {
+		container := map[string]any{"name": "app", "image": image}
+		if privileged {
+			container["securityContext"] = map[string]any{"privileged": true}
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": name},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "sidecar", "image": "ghcr.io/org/sidecar:1.0.0"},
+							container,
+						},
+					},
+				},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		object     *unstructured.Unstructured
+		violations []string
+		denied     bool
+	}{
+		{
+			name:   "allows compliant objects",
+			object: deployment("app", "ghcr.io/org/app:1.0.0", false),
+		},
+		{
+			name:       "denies privileged containers",
+			object:     deployment("app", "ghcr.io/org/app:1.0.0", true),
+			violations: []string{"[baseline/no-privileged] privileged containers are not allowed"},
+			denied:     true,
+		},
+		{
+			name:       "warns on unapproved registries",
+			object:     deployment("app", "docker.io/org/app:1.0.0", false),
+			violations: []string{"[baseline/approved-registries] images must be pulled from the approved registries"},
+		},
+		{
+			name: "skips objects not matching the rules",
+			object: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "app"},
+			}},
+		},
+		{
+			name: "allows objects without containers",
+			object: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "app"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			violations, err := policies.Check([]*unstructured.Unstructured{tt.object})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(violations).To(HaveLen(len(tt.violations)))
+			for i, msg := range tt.violations {
+				g.Expect(violations[i].String()).To(Equal(msg))
+				g.Expect(violations[i].Denied()).To(Equal(tt.denied))
+			}
+		})
+	}
+}
+
+func TestPolicySet_Incomplete(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "ports.cue"), []byte(`
+rules: "no-default-port": {
+	match: kind: "ConfigMap"
+	message: "the default port is not allowed"
+	#object: data: [string]: string
+	allow: #object.data.port != "9090"
+}
+rules: "no-debug-port": {
+	match: kind: "ConfigMap"
+	message: "the debug port is not allowed"
+	#object: data: [string]: string
+	allow:           #object.data.port != "2345"
+	allowIncomplete: true
+}
+`), 0o644)).To(Succeed())
+
+	policies := NewPolicySet(cuecontext.New())
+	g.Expect(policies.LoadPack("ports", dir)).To(Succeed())
+
+	configMap := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "app"},
+		"data":       map[string]any{"host": "example.com"},
+	}}
+
+	violations, err := policies.Check([]*unstructured.Unstructured{configMap})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(violations).To(HaveLen(1))
+	g.Expect(violations[0].Incomplete).To(BeTrue())
+	g.Expect(violations[0].Denied()).To(BeTrue())
+	g.Expect(violations[0].String()).To(Equal(
+		"[ports/no-default-port] the default port is not allowed (incomplete: the object is missing the fields referenced by allow)"))
+}
+
+func TestPolicySet_LoadPack(t *testing.T) {
+	t.Run("rejects rules without message", func(t *testing.T) {
+		g := NewWithT(t)
+		policies := NewPolicySet(cuecontext.New())
+		err := policies.LoadPack("invalid", "testdata/policy/invalid")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(`rule "missing-message" is invalid`))
+	})
+
+	t.Run("rejects empty packs", func(t *testing.T) {
+		g := NewWithT(t)
+		policies := NewPolicySet(cuecontext.New())
+		err := policies.LoadPack("empty", t.TempDir())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("no CUE files found"))
+	})
+}
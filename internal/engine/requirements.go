@@ -0,0 +1,164 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"github.com/Masterminds/semver/v3"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// GetRequirements extracts the requirements declared by the module
+// under 'timoni: requirements:'. It returns nil when the field is absent.
+func (b *ModuleBuilder) GetRequirements(value cue.Value) (*apiv1.ModuleRequirements, error) {
+	v := value.LookupPath(cue.ParsePath(apiv1.RequirementsSelector.String()))
+	if !v.Exists() {
+		return nil, nil
+	}
+	if err := v.Err(); err != nil {
+		return nil, fmt.Errorf("lookup %s failed: %w", apiv1.RequirementsSelector, err)
+	}
+
+	var req apiv1.ModuleRequirements
+	if err := v.Decode(&req); err != nil {
+		return nil, fmt.Errorf("decoding %s failed: %w", apiv1.RequirementsSelector, err)
+	}
+	return &req, nil
+}
+
+// CheckRequirements verifies that the Kubernetes version, the Timoni version
+// and the API versions served by the cluster satisfy the module requirements.
+// An empty Kubernetes version or nil API versions skip the respective checks,
+// and development builds of Timoni skip the CLI version check.
+// All the unmet requirements are returned as a joined error.
+func CheckRequirements(req *apiv1.ModuleRequirements, kubeVersion, timoniVersion string, apiVersions []string) error {
+	if req == nil {
+		return nil
+	}
+
+	var errs []error
+	if req.KubeVersion != nil && kubeVersion != "" {
+		if err := checkVersionRange("Kubernetes", kubeVersion, req.KubeVersion); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if req.TimoniVersion != nil && !isDevelVersion(timoniVersion) {
+		if err := checkVersionRange("Timoni", timoniVersion, req.TimoniVersion); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if apiVersions != nil {
+		for _, group := range req.APIGroups {
+			if !servesAPIGroup(apiVersions, group) {
+				errs = append(errs, fmt.Errorf("module requires the API %s which is not served by the cluster", group))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkVersionRange compares the version without its prerelease and build
+// metadata, such as the vendor suffixes of managed Kubernetes distributions.
+func checkVersionRange(name, version string, r *apiv1.VersionRange) error {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("parsing %s version %s failed: %w", name, version, err)
+	}
+	current := semver.New(v.Major(), v.Minor(), v.Patch(), "", "")
+
+	if r.Min != "" {
+		minVer, err := semver.NewVersion(r.Min)
+		if err != nil {
+			return fmt.Errorf("parsing the minimum %s version %s failed: %w", name, r.Min, err)
+		}
+		if current.LessThan(minVer) {
+			return fmt.Errorf("module requires %s >= %s, current version is %s", name, r.Min, version)
+		}
+	}
+
+	if r.Max != "" {
+		maxVer, err := semver.NewVersion(r.Max)
+		if err != nil {
+			return fmt.Errorf("parsing the maximum %s version %s failed: %w", name, r.Max, err)
+		}
+		cmp := current
+		if strings.Count(strings.TrimPrefix(r.Max, "v"), ".") < 2 {
+			cmp = semver.New(v.Major(), v.Minor(), 0, "", "")
+		}
+		if cmp.GreaterThan(maxVer) {
+			return fmt.Errorf("module requires %s <= %s, current version is %s", name, r.Max, version)
+		}
+	}
+
+	return nil
+}
+
+// isDevelVersion returns true for the builds of Timoni
+// made from source, which have the version 0.0.0.
+func isDevelVersion(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return true
+	}
+	return v.Major() == 0 && v.Minor() == 0 && v.Patch() == 0
+}
+
+// servesAPIGroup returns true if the group, or the group version,
+// is found in the API versions served by the cluster.
+func servesAPIGroup(apiVersions []string, group string) bool {
+	if slices.Contains(apiVersions, group) {
+		return true
+	}
+	return slices.ContainsFunc(apiVersions, func(v string) bool {
+		return strings.HasPrefix(v, group+"/")
+	})
+}
+
+// FormatRequirementsCUE formats the module requirements
+// as the 'timoni: requirements:' CUE block.
+func FormatRequirementsCUE(ctx *cue.Context, req *apiv1.ModuleRequirements) (string, error) {
+	v := ctx.Encode(map[string]any{
+		"timoni": map[string]any{
+			"requirements": req,
+		},
+	})
+	if v.Err() != nil {
+		return "", fmt.Errorf("encoding the requirements failed: %w", v.Err())
+	}
+
+	node := v.Syntax()
+	if st, ok := node.(*ast.StructLit); ok {
+		node = &ast.File{Decls: st.Elts}
+	}
+
+	out, err := format.Node(node, format.Simplify())
+	if err != nil {
+		return "", fmt.Errorf("formatting the requirements failed: %w", err)
+	}
+	return string(out), nil
+}
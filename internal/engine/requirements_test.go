@@ -0,0 +1,185 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+func TestGetRequirements(t *testing.T) {
+	ctx := cuecontext.New()
+	b := &ModuleBuilder{}
+
+	t.Run("extracts requirements", func(t *testing.T) {
+		g := NewWithT(t)
+
+		value := ctx.CompileString(apiv1.InstanceSchema + `
+timoni: {
+	apiVersion: "v1alpha1"
+	instance: {}
+	apply: {}
+	requirements: {
+		kubeVersion: {min: "1.28.0", max: "1.32"}
+		timoniVersion: min: "0.30.0"
+		apiGroups: ["monitoring.coreos.com"]
+	}
+}
+`)
+		g.Expect(value.Err()).ToNot(HaveOccurred())
+
+		req, err := b.GetRequirements(value)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(req).To(Equal(&apiv1.ModuleRequirements{
+			KubeVersion:   &apiv1.VersionRange{Min: "1.28.0", Max: "1.32"},
+			TimoniVersion: &apiv1.VersionRange{Min: "0.30.0"},
+			APIGroups:     []string{"monitoring.coreos.com"},
+		}))
+
+		out, err := FormatRequirementsCUE(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(out).To(ContainSubstring(`min: "1.28.0"`))
+		g.Expect(out).To(ContainSubstring(`apiGroups: ["monitoring.coreos.com"]`))
+	})
+
+	t.Run("returns nil without requirements", func(t *testing.T) {
+		g := NewWithT(t)
+
+		value := ctx.CompileString(apiv1.InstanceSchema + `
+timoni: {
+	apiVersion: "v1alpha1"
+	instance: {}
+	apply: {}
+}
+`)
+		req, err := b.GetRequirements(value)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(req).To(BeNil())
+	})
+
+	t.Run("rejects invalid versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		value := ctx.CompileString(apiv1.InstanceSchema + `
+timoni: {
+	apiVersion: "v1alpha1"
+	instance: {}
+	apply: {}
+	requirements: kubeVersion: min: ">=1.28"
+}
+`)
+		g.Expect(value.Validate()).To(HaveOccurred())
+	})
+}
+
+func TestCheckRequirements(t *testing.T) {
+	req := &apiv1.ModuleRequirements{
+		KubeVersion:   &apiv1.VersionRange{Min: "1.28.0", Max: "1.32"},
+		TimoniVersion: &apiv1.VersionRange{Min: "0.30.0"},
+		APIGroups:     []string{"monitoring.coreos.com", "cert-manager.io/v1"},
+	}
+	apiVersions := []string{
+		"v1",
+		"v1/ConfigMap",
+		"cert-manager.io/v1",
+		"cert-manager.io/v1/Certificate",
+		"monitoring.coreos.com/v1",
+		"monitoring.coreos.com/v1/ServiceMonitor",
+	}
+
+	tests := []struct {
+		name          string
+		kubeVersion   string
+		timoniVersion string
+		apiVersions   []string
+		wantErr       []string
+	}{
+		{
+			name:          "meets all requirements",
+			kubeVersion:   "1.30.2",
+			timoniVersion: "0.30.1",
+			apiVersions:   apiVersions,
+		},
+		{
+			name:          "includes the patch releases of the max version",
+			kubeVersion:   "1.32.7-eks-1552ad0",
+			timoniVersion: "0.30.0",
+			apiVersions:   apiVersions,
+		},
+		{
+			name:          "rejects older Kubernetes",
+			kubeVersion:   "1.27.9",
+			timoniVersion: "0.30.0",
+			apiVersions:   apiVersions,
+			wantErr:       []string{"module requires Kubernetes >= 1.28.0, current version is 1.27.9"},
+		},
+		{
+			name:          "rejects newer Kubernetes",
+			kubeVersion:   "1.33.0",
+			timoniVersion: "0.30.0",
+			apiVersions:   apiVersions,
+			wantErr:       []string{"module requires Kubernetes <= 1.32, current version is 1.33.0"},
+		},
+		{
+			name:          "rejects older Timoni",
+			kubeVersion:   "1.30.0",
+			timoniVersion: "0.29.0",
+			apiVersions:   apiVersions,
+			wantErr:       []string{"module requires Timoni >= 0.30.0, current version is 0.29.0"},
+		},
+		{
+			name:          "skips the development version of Timoni",
+			kubeVersion:   "1.30.0",
+			timoniVersion: "0.0.0-dev.0",
+			apiVersions:   apiVersions,
+		},
+		{
+			name:          "rejects missing API groups",
+			kubeVersion:   "1.30.0",
+			timoniVersion: "0.30.0",
+			apiVersions:   []string{"v1", "cert-manager.io/v1beta1"},
+			wantErr: []string{
+				"module requires the API monitoring.coreos.com which is not served by the cluster",
+				"module requires the API cert-manager.io/v1 which is not served by the cluster",
+			},
+		},
+		{
+			name:          "skips the cluster checks offline",
+			timoniVersion: "0.30.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := CheckRequirements(req, tt.kubeVersion, tt.timoniVersion, tt.apiVersions)
+			if len(tt.wantErr) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, msg := range tt.wantErr {
+				g.Expect(err.Error()).To(ContainSubstring(msg))
+			}
+		})
+	}
+}
@@ -100,15 +100,7 @@ func decodeObjects(items cue.Iterator) ([]*unstructured.Unstructured, error) {
 			continue
 		}
 
-		var (
-			objs []*unstructured.Unstructured
-			err  error
-		)
-		if needsYAMLDecoding(item) {
-			objs, err = decodeYAMLObjects(item)
-		} else {
-			objs, err = decodeJSONObjects(item)
-		}
+		objs, err := decodeItem(item)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("decoding object at path %s failed: %w", item.Path(), err))
 			continue
@@ -135,6 +127,15 @@ func decodeObjects(items cue.Iterator) ([]*unstructured.Unstructured, error) {
 	return objects, nil
 }
 
+// decodeItem converts the CUE value of a resource list item to Kubernetes
+// unstructured objects, expanding Kubernetes lists to their items.
+func decodeItem(item cue.Value) ([]*unstructured.Unstructured, error) {
+	if needsYAMLDecoding(item) {
+		return decodeYAMLObjects(item)
+	}
+	return decodeJSONObjects(item)
+}
+
 // listItems expands a Kubernetes list object to its items.
 func listItems(list *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
 	var objects []*unstructured.Unstructured
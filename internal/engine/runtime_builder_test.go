@@ -74,6 +74,13 @@ runtime: {
 				"CLUSTER_ISSUER": "obj.spec.acme.email"
 			}
 		},
+		{
+			query: "file:config/aws.yaml:account"
+			for: {
+				"ACCOUNT_ID": "obj.id"
+			}
+			secret: true
+		},
 	]
 }
 `
@@ -92,6 +99,7 @@ runtime: {
 			Name:      "cluster",
 			Namespace: "flux-system",
 		},
+		Provider: apiv1.RuntimeProviderKubernetes,
 		Expressions: map[string]string{
 			"CLUSTER_REVISION": "obj.status.artifact.revision",
 			"CLUSTER_STATUS":   "[for c in obj.status.conditions if c.type == \"Ready\" {c.status}][0]",
@@ -99,6 +107,10 @@ runtime: {
 		Optional: false,
 	}))
 	g.Expect(b.Refs[2].Namespace).To(BeEmpty())
+	g.Expect(b.Refs[3].Provider).To(Equal("file"))
+	g.Expect(b.Refs[3].Source).To(Equal("config/aws.yaml:account"))
+	g.Expect(b.Refs[3].Secret).To(BeTrue())
+	g.Expect(b.Refs[3].String()).To(Equal("file:config/aws.yaml:account"))
 }
 
 func TestRuntimeBuilder_Clusters(t *testing.T) {
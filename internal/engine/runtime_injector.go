@@ -17,6 +17,7 @@ limitations under the License.
 package engine
 
 import (
+	stdjson "encoding/json"
 	"fmt"
 	"strconv"
 
@@ -26,6 +27,7 @@ import (
 	"cuelang.org/go/cue/format"
 	"cuelang.org/go/cue/literal"
 	"cuelang.org/go/cue/token"
+	"cuelang.org/go/encoding/json"
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 )
@@ -121,9 +123,9 @@ func (in *RuntimeInjector) inject(node ast.Node, vars map[string]string) (ast.No
 }
 
 // valueExpr converts a runtime value to the CUE expression matching the
-// attribute type. Number and bool values must be validated: unlike strings
-// they are emitted unquoted, so an unchecked value is read back as CUE source
-// instead of as data.
+// attribute type. Number, bool, struct and list values must be validated:
+// unlike strings they are emitted unquoted, so an unchecked value is read
+// back as CUE source instead of as data.
 func (in *RuntimeInjector) valueExpr(valType, val string) (ast.Expr, error) {
 	switch valType {
 	case "string":
@@ -148,11 +150,48 @@ func (in *RuntimeInjector) valueExpr(valType, val string) (ast.Expr, error) {
 			return nil, fmt.Errorf("value must be 'true' or 'false'")
 		}
 		return ast.NewBool(val == "true"), nil
+	case "struct", "list":
+		return in.jsonExpr(valType, val)
 	default:
-		return nil, fmt.Errorf("unknown type '%s' must be string, number or bool", valType)
+		return nil, fmt.Errorf("unknown type '%s' must be string, number, bool, struct or list", valType)
 	}
 }
 
+// jsonExpr parses a JSON object or array into a CUE literal. The value is
+// decoded as JSON data, never as CUE source, so references, comprehensions
+// and comments can't be smuggled in. As for numbers, the parser errors
+// quote the value and are discarded.
+func (in *RuntimeInjector) jsonExpr(valType, val string) (ast.Expr, error) {
+	errInvalid := fmt.Errorf("value must be a JSON object")
+	if valType == "list" {
+		errInvalid = fmt.Errorf("value must be a JSON array")
+	}
+
+	if !stdjson.Valid([]byte(val)) {
+		return nil, errInvalid
+	}
+
+	expr, err := json.Extract("", []byte(val))
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	switch expr.(type) {
+	case *ast.StructLit:
+		if valType != "struct" {
+			return nil, errInvalid
+		}
+	case *ast.ListLit:
+		if valType != "list" {
+			return nil, errInvalid
+		}
+	default:
+		return nil, errInvalid
+	}
+
+	return expr, nil
+}
+
 func (in *RuntimeInjector) quoteString(s string) string {
 	lines := []string{}
 	last := 0
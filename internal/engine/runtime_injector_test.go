@@ -240,3 +240,97 @@ secrets: {
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(string(result)).To(BeIdenticalTo(output))
 }
+
+func TestInjector_StructuredValues(t *testing.T) {
+	input := `package test
+
+values: {
+	cidrs:  [...string]         @timoni(runtime:list:CIDRS)
+	labels: {[string]: string}  @timoni(runtime:struct:LABELS)
+}
+`
+
+	t.Run("injects JSON values", func(t *testing.T) {
+		g := NewWithT(t)
+		ctx := cuecontext.New()
+
+		f, err := parser.ParseFile("", []byte(input), parser.ParseComments)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := NewRuntimeInjector(ctx).Inject(f, map[string]string{
+			"CIDRS":  `["10.0.0.0/16", "10.1.0.0/16"]`,
+			"LABELS": `{"team": "platform", "app.kubernetes.io/part-of": "fleet"}`,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		v := ctx.CompileBytes(result)
+		g.Expect(v.Err()).ToNot(HaveOccurred())
+
+		var cidrs []string
+		g.Expect(v.LookupPath(cue.ParsePath("values.cidrs")).Decode(&cidrs)).To(Succeed())
+		g.Expect(cidrs).To(Equal([]string{"10.0.0.0/16", "10.1.0.0/16"}))
+
+		var labels map[string]string
+		g.Expect(v.LookupPath(cue.ParsePath("values.labels")).Decode(&labels)).To(Succeed())
+		g.Expect(labels).To(Equal(map[string]string{
+			"team":                      "platform",
+			"app.kubernetes.io/part-of": "fleet",
+		}))
+	})
+
+	tests := []struct {
+		name string
+		vars map[string]string
+		err  string
+	}{
+		{
+			name: "list with object",
+			vars: map[string]string{"CIDRS": `{"a": "b"}`},
+			err:  "value must be a JSON array",
+		},
+		{
+			name: "list with CUE comprehension",
+			vars: map[string]string{"CIDRS": `[for x in values.labels {x}]`},
+			err:  "value must be a JSON array",
+		},
+		{
+			name: "list with trailing field",
+			vars: map[string]string{"CIDRS": "[]\nfoo: 1"},
+			err:  "value must be a JSON array",
+		},
+		{
+			name: "struct with array",
+			vars: map[string]string{"LABELS": `["a"]`},
+			err:  "value must be a JSON object",
+		},
+		{
+			name: "struct with reference",
+			vars: map[string]string{"LABELS": `{"team": values.cidrs}`},
+			err:  "value must be a JSON object",
+		},
+		{
+			name: "struct with braces",
+			vars: map[string]string{"LABELS": "{}}\nfoo: {"},
+			err:  "value must be a JSON object",
+		},
+		{
+			name: "struct empty",
+			vars: map[string]string{"LABELS": ""},
+			err:  "value must be a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+
+			f, err := parser.ParseFile("", []byte(input), parser.ParseComments)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			result, err := NewRuntimeInjector(ctx).Inject(f, tt.vars)
+			g.Expect(err).To(MatchError(ContainSubstring(tt.err)))
+			g.Expect(result).To(BeNil())
+		})
+	}
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	ssautil "github.com/fluxcd/pkg/ssa/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// KubeSpecLoader returns the Kubernetes OpenAPI v3 document which defines
+// the kinds of the given group version, or nil if the group version is not
+// served by the Kubernetes version.
+type KubeSpecLoader func(gv schema.GroupVersion) ([]byte, error)
+
+// KubeSpecFile returns the file name of the group version's OpenAPI v3
+// document, as published in the api/openapi-spec/v3 directory of the
+// Kubernetes repository e.g. 'apis__apps__v1_openapi.json'.
+func KubeSpecFile(gv schema.GroupVersion) string {
+	if gv.Group == "" {
+		return fmt.Sprintf("api__%s_openapi.json", gv.Version)
+	}
+	return fmt.Sprintf("apis__%s__%s_openapi.json", gv.Group, gv.Version)
+}
+
+// KubeSpecDirLoader returns a KubeSpecLoader which reads
+// the OpenAPI v3 documents from the directory.
+func KubeSpecDirLoader(dir string) KubeSpecLoader {
+	return func(gv schema.GroupVersion) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(dir, KubeSpecFile(gv)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return data, err
+	}
+}
+
+// SchemaValidator validates Kubernetes objects against the OpenAPI v3
+// schemas of the Kubernetes APIs and of the CustomResourceDefinitions.
+// Unknown fields are rejected, unless the schema preserves them.
+type SchemaValidator struct {
+	kubeVersion string
+	kubeSpec    KubeSpecLoader
+	kinds       map[schema.GroupVersionKind]*openapi3.Schema
+	loaded      map[schema.GroupVersion]bool
+}
+
+// NewSchemaValidator creates a SchemaValidator for the Kubernetes version,
+// loading the schemas of the Kubernetes APIs with the given loader.
+// A nil loader restricts the validation to the custom resources.
+func NewSchemaValidator(kubeVersion string, kubeSpec KubeSpecLoader) *SchemaValidator {
+	return &SchemaValidator{
+		kubeVersion: kubeVersion,
+		kubeSpec:    kubeSpec,
+		kinds:       make(map[schema.GroupVersionKind]*openapi3.Schema),
+		loaded:      make(map[schema.GroupVersion]bool),
+	}
+}
+
+// LoadCRDs adds the schemas of the CustomResourceDefinitions found
+// in the YAML and JSON files of the directory and its subdirectories.
+func (v *SchemaValidator) LoadCRDs(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		objects, err := ssautil.ReadObjects(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("reading %s failed: %w", path, err)
+		}
+		for _, object := range objects {
+			if err := v.AddCRD(object); err != nil {
+				return fmt.Errorf("loading %s failed: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// AddCRD adds the openAPIV3Schema of every version of the
+// CustomResourceDefinition. Other objects are ignored.
+func (v *SchemaValidator) AddCRD(object *unstructured.Unstructured) error {
+	if object.GetKind() != "CustomResourceDefinition" ||
+		object.GroupVersionKind().Group != "apiextensions.k8s.io" {
+		return nil
+	}
+
+	group, _, _ := unstructured.NestedString(object.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(object.Object, "spec", "names", "kind")
+	versions, _, err := unstructured.NestedSlice(object.Object, "spec", "versions")
+	if err != nil {
+		return fmt.Errorf("CRD %s: %w", object.GetName(), err)
+	}
+
+	for _, item := range versions {
+		version, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := version["name"].(string)
+		raw, found, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if !found {
+			continue
+		}
+
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("CRD %s version %s: %w", object.GetName(), name, err)
+		}
+		s := &openapi3.Schema{}
+		if err := s.UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("CRD %s version %s: invalid openAPIV3Schema: %w", object.GetName(), name, err)
+		}
+		// The API server ignores the status of custom resources on create.
+		delete(s.Properties, "status")
+		closeSchema(s, make(map[*openapi3.Schema]bool))
+
+		v.kinds[schema.GroupVersionKind{Group: group, Version: name, Kind: kind}] = s
+	}
+	return nil
+}
+
+// ErrSchemaNotFound is returned by Validate for the objects
+// whose kind has no schema.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// Validate checks the object against the schema of its kind, returning one
+// error per violation, prefixed with the path of the offending field.
+// It fails with ErrSchemaNotFound if no schema is found for the object's
+// kind, or with the error of loading the schemas.
+func (v *SchemaValidator) Validate(object *unstructured.Unstructured) ([]error, error) {
+	gvk := object.GroupVersionKind()
+	if v.kubeSpec != nil && !v.loaded[gvk.GroupVersion()] {
+		served, err := v.loadKubeSpec(gvk.GroupVersion())
+		if err != nil {
+			return nil, err
+		}
+		if !served && isBuiltinGroup(gvk.Group) {
+			return []error{fmt.Errorf("apiVersion %s is not served by Kubernetes %s",
+				gvk.GroupVersion(), v.kubeVersion)}, nil
+		}
+	}
+
+	s, ok := v.kinds[gvk]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+
+	// Validate a JSON copy of the object, as the validator
+	// mutates the value and expects the JSON number type.
+	data, err := json.Marshal(object.Object)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	removeNulls(value)
+
+	if err := s.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return flattenSchemaErrors(err), nil
+	}
+	return nil, nil
+}
+
+// loadKubeSpec adds the schemas of the group version's kinds,
+// returning false if the group version is not served.
+func (v *SchemaValidator) loadKubeSpec(gv schema.GroupVersion) (bool, error) {
+	data, err := v.kubeSpec(gv)
+	if err != nil {
+		return false, err
+	}
+	v.loaded[gv] = true
+	if data == nil {
+		return false, nil
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return false, fmt.Errorf("loading the OpenAPI schema of %s failed: %w", gv, err)
+	}
+	if doc.Components == nil {
+		return true, nil
+	}
+
+	visited := make(map[*openapi3.Schema]bool)
+	for name, ref := range doc.Components.Schemas {
+		if ref.Value == nil {
+			continue
+		}
+		if name == "io.k8s.apimachinery.pkg.api.resource.Quantity" {
+			// Quantities are accepted as strings and numbers.
+			ref.Value.Type = nil
+			ref.Value.OneOf = nil
+		}
+		closeSchema(ref.Value, visited)
+		for _, k := range schemaGroupVersionKinds(ref.Value) {
+			// The CRDs take precedence, as they may be
+			// newer versions of the Kubernetes APIs.
+			if _, ok := v.kinds[k]; k.GroupVersion() == gv && !ok {
+				v.kinds[k] = ref.Value
+			}
+		}
+	}
+	return true, nil
+}
+
+// isBuiltinGroup returns true for the core group and the groups of the
+// Kubernetes APIs which are not qualified with a domain e.g. apps, batch.
+func isBuiltinGroup(group string) bool {
+	return !strings.Contains(group, ".")
+}
+
+// schemaGroupVersionKinds returns the kinds listed
+// in the schema's x-kubernetes-group-version-kind extension.
+func schemaGroupVersionKinds(s *openapi3.Schema) []schema.GroupVersionKind {
+	list, _ := s.Extensions["x-kubernetes-group-version-kind"].([]any)
+	var gvks []schema.GroupVersionKind
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		group, _ := m["group"].(string)
+		version, _ := m["version"].(string)
+		kind, _ := m["kind"].(string)
+		gvks = append(gvks, schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+	}
+	return gvks
+}
+
+// closeSchema rejects the unknown properties of the object schemas, as the
+// API server does with strict field validation. Schemas which preserve the
+// unknown fields or define additional properties are left open, and the
+// int-or-string schemas accept any value.
+func closeSchema(s *openapi3.Schema, visited map[*openapi3.Schema]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+
+	if s.Extensions["x-kubernetes-int-or-string"] == true || s.Format == "int-or-string" {
+		s.Type = nil
+		s.Format = ""
+		s.AnyOf = nil
+		s.OneOf = nil
+	}
+
+	preserve := s.Extensions["x-kubernetes-preserve-unknown-fields"] == true
+	if len(s.Properties) > 0 && !preserve &&
+		s.AdditionalProperties.Has == nil && s.AdditionalProperties.Schema == nil {
+		s.AdditionalProperties.Has = openapi3.Ptr(false)
+	}
+
+	for _, p := range s.Properties {
+		closeSchema(p.Value, visited)
+	}
+	if s.Items != nil {
+		closeSchema(s.Items.Value, visited)
+	}
+	if s.AdditionalProperties.Schema != nil {
+		closeSchema(s.AdditionalProperties.Schema.Value, visited)
+	}
+	for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, r := range refs {
+			closeSchema(r.Value, visited)
+		}
+	}
+}
+
+// removeNulls deletes the null fields, which the API server treats as unset.
+func removeNulls(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if item == nil {
+				delete(v, k)
+				continue
+			}
+			removeNulls(item)
+		}
+	case []any:
+		for _, item := range v {
+			removeNulls(item)
+		}
+	}
+}
+
+// flattenSchemaErrors returns the schema errors formatted
+// as '<field path>: <reason>'.
+func flattenSchemaErrors(err error) []error {
+	var errs []error
+	var me openapi3.MultiError
+	if errors.As(err, &me) {
+		for _, e := range me {
+			errs = append(errs, flattenSchemaErrors(e)...)
+		}
+		return errs
+	}
+
+	var se *openapi3.SchemaError
+	if !errors.As(err, &se) {
+		return []error{err}
+	}
+
+	reason := se.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("does not match the schema %q", se.SchemaField)
+	}
+	pointer := se.JSONPointer()
+	if len(pointer) == 0 {
+		return []error{errors.New(reason)}
+	}
+	return []error{fmt.Errorf("%s: %s", fieldPath(pointer), reason)}
+}
+
+// fieldPath formats the JSON pointer segments as a CUE path
+// e.g. 'spec.containers[0].name'.
+func fieldPath(pointer []string) string {
+	selectors := make([]cue.Selector, 0, len(pointer))
+	for _, segment := range pointer {
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 {
+			selectors = append(selectors, cue.Index(i))
+			continue
+		}
+		selectors = append(selectors, cue.Str(segment))
+	}
+	return cue.MakePath(selectors...).String()
+}
+
+// ValidateSchemas validates the objects found under 'timoni: apply:' with
+// the SchemaValidator. Violations yield an error naming the CUE path of the
+// object, one per violation, aggregated with errors.Join. The objects
+// without a schema are returned.
+func (b *ModuleBuilder) ValidateSchemas(value cue.Value, validator *SchemaValidator) ([]*unstructured.Unstructured, error) {
+	steps := value.LookupPath(cue.ParsePath(apiv1.ApplySelector.String()))
+	if steps.Err() != nil {
+		return nil, fmt.Errorf("lookup %s failed: %w", apiv1.ApplySelector, steps.Err())
+	}
+
+	type objectItem struct {
+		path   cue.Path
+		object *unstructured.Unstructured
+	}
+
+	// Collect the objects first, for the CRDs rendered by the
+	// module to validate their custom resources.
+	var objects []objectItem
+	iter, err := steps.Fields(cue.Concrete(true), cue.Final())
+	if err != nil {
+		return nil, fmt.Errorf("getting resources failed: %w", err)
+	}
+	for iter.Next() {
+		items, err := iter.Value().List()
+		if err != nil {
+			return nil, fmt.Errorf("listing objects in resource list %q failed: %w", iter.Selector(), err)
+		}
+		for items.Next() {
+			item := items.Value()
+			if item.Kind() == cue.NullKind {
+				continue
+			}
+			objs, err := decodeItem(item)
+			if err != nil {
+				return nil, fmt.Errorf("decoding object at path %s failed: %w", item.Path(), err)
+			}
+			for _, obj := range objs {
+				if ssautil.IsKustomization(obj) {
+					continue
+				}
+				if err := validator.AddCRD(obj); err != nil {
+					return nil, err
+				}
+				objects = append(objects, objectItem{path: item.Path(), object: obj})
+			}
+		}
+	}
+
+	var skipped []*unstructured.Unstructured
+	var errs []error
+	for _, item := range objects {
+		verrs, err := validator.Validate(item.object)
+		if errors.Is(err, ErrSchemaNotFound) {
+			skipped = append(skipped, item.object)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, verr := range verrs {
+			errs = append(errs, fmt.Errorf("invalid object at path %s: %w", item.path, verr))
+		}
+	}
+	return skipped, errors.Join(errs...)
+}
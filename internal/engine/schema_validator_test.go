@@ -0,0 +1,166 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSchemaValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects string
+		skipped []string
+		wantErr []string
+	}{
+		{
+			name: "validates Kubernetes objects",
+			objects: `
+app: [{
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: {name: "app", namespace: "default", labels: app: "app"}
+	spec: {
+		replicas: 2
+		selector: matchLabels: app: "app"
+		template: {
+			metadata: labels: app: "app"
+			spec: containers: [{
+				name:  "app"
+				image: "nginx:1.27"
+				resources: limits: {cpu: 1, memory: "128Mi"}
+			}]
+		}
+	}
+}, {
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: {name: "app", namespace: "default"}
+	spec: {
+		selector: app: "app"
+		ports: [{port: 80, targetPort: "http"}]
+	}
+}]
+`,
+		},
+		{
+			name: "reports invalid Kubernetes objects",
+			objects: `
+app: [{
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	metadata: {name: "app", namespace: "default"}
+	data: enabled: true
+	metadata: labels: "app.kubernetes.io/name": 1
+}, {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: {name: "app", namespace: "default"}
+	spec: {
+		replica: 2
+		selector: matchLabels: app: "app"
+		template: spec: containers: [{image: "nginx:1.27"}]
+	}
+}]
+`,
+			wantErr: []string{
+				`invalid object at path timoni.apply.app[0]: data.enabled: value must be a string`,
+				`invalid object at path timoni.apply.app[0]: metadata.labels."app.kubernetes.io/name": value must be a string`,
+				`invalid object at path timoni.apply.app[1]: spec: property "replica" is unsupported`,
+				`invalid object at path timoni.apply.app[1]: spec.template.spec.containers[0].name: property "name" is missing`,
+			},
+		},
+		{
+			name: "rejects the removed Kubernetes APIs",
+			objects: `
+app: [{
+	apiVersion: "extensions/v1beta1"
+	kind:       "Ingress"
+	metadata: {name: "app", namespace: "default"}
+}]
+`,
+			wantErr: []string{
+				`invalid object at path timoni.apply.app[0]: apiVersion extensions/v1beta1 is not served by Kubernetes 1.30`,
+			},
+		},
+		{
+			name: "validates custom resources",
+			objects: `
+app: [{
+	apiVersion: "example.com/v1"
+	kind:       "PodInfo"
+	metadata: {name: "app", namespace: "default"}
+	spec: {
+		url:      "https://example.com"
+		interval: "1x"
+		values: replicas: 2
+		timeout: "1m"
+	}
+}, {
+	apiVersion: "monitoring.coreos.com/v1"
+	kind:       "ServiceMonitor"
+	metadata: {name: "app", namespace: "default"}
+}]
+`,
+			skipped: []string{"ServiceMonitor/default/app"},
+			wantErr: []string{
+				`invalid object at path timoni.apply.app[0]: spec.interval: string doesn't match the regular expression "^([0-9]+(s|m|h))+$"`,
+				`invalid object at path timoni.apply.app[0]: spec: property "timeout" is unsupported`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			validator := NewSchemaValidator("1.30", KubeSpecDirLoader("testdata/openapi"))
+			g.Expect(validator.LoadCRDs("testdata/openapi/crds")).To(Succeed())
+
+			value := cuecontext.New().CompileString("timoni: apply: {" + tt.objects + "}")
+			g.Expect(value.Err()).ToNot(HaveOccurred())
+
+			skipped, err := (&ModuleBuilder{}).ValidateSchemas(value, validator)
+
+			var got []string
+			for _, obj := range skipped {
+				got = append(got, obj.GetKind()+"/"+obj.GetNamespace()+"/"+obj.GetName())
+			}
+			g.Expect(got).To(Equal(tt.skipped))
+
+			if len(tt.wantErr) == 0 {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			for _, msg := range tt.wantErr {
+				g.Expect(err.Error()).To(ContainSubstring(msg))
+			}
+		})
+	}
+}
+
+func TestKubeSpecFile(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(KubeSpecFile(schema.GroupVersion{Version: "v1"})).To(Equal("api__v1_openapi.json"))
+	g.Expect(KubeSpecFile(schema.GroupVersion{Group: "networking.k8s.io", Version: "v1"})).
+		To(Equal("apis__networking.k8s.io__v1_openapi.json"))
+}
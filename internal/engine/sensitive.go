@@ -0,0 +1,241 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/parser"
+	cuejson "cuelang.org/go/encoding/json"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+// encryptedValuePrefix is the header of the age armored ciphertext
+// that replaces the sensitive values encrypted before storage.
+const encryptedValuePrefix = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+// GetSensitivePaths returns the paths, relative to the values, of the
+// fields marked as sensitive with @timoni(sensitive) in the module's #Config.
+func (b *ModuleBuilder) GetSensitivePaths(value cue.Value) ([]cue.Path, error) {
+	values := value.LookupPath(cue.ParsePath(apiv1.ValuesSelector.String()))
+	if values.Err() != nil {
+		return nil, fmt.Errorf("lookup %s failed: %w", apiv1.ValuesSelector, values.Err())
+	}
+
+	offset := len(values.Path().Selectors())
+	var paths []cue.Path
+	values.Walk(func(v cue.Value) bool {
+		if isSensitive(v) {
+			paths = append(paths, cue.MakePath(v.Path().Selectors()[offset:]...))
+			return false
+		}
+		return true
+	}, nil)
+	return paths, nil
+}
+
+// GetSensitiveValues returns the concrete string values found under the
+// sensitive fields, so that they can be masked in the diff output.
+func (b *ModuleBuilder) GetSensitiveValues(value cue.Value, paths []cue.Path) []string {
+	values := value.LookupPath(cue.ParsePath(apiv1.ValuesSelector.String()))
+
+	var result []string
+	for _, p := range paths {
+		values.LookupPath(p).Walk(func(v cue.Value) bool {
+			if s, err := v.String(); err == nil && s != "" {
+				result = append(result, s)
+			}
+			return true
+		}, nil)
+	}
+	return result
+}
+
+func isSensitive(v cue.Value) bool {
+	attr := v.Attribute(apiv1.FieldManager)
+	if attr.Err() != nil {
+		return false
+	}
+	found, err := attr.Flag(0, apiv1.SensitiveAttribute)
+	return err == nil && found
+}
+
+// ProtectValues replaces the sensitive fields found in the values with the
+// age ciphertext of their JSON encoding, when recipients are specified,
+// or with the redacted value otherwise. The paths that are not set
+// in the values are ignored, while the paths that are set but can't be
+// located in the values syntax result in an error.
+func ProtectValues(values string, paths []cue.Path, recipients []string) (string, error) {
+	if len(paths) == 0 {
+		return values, nil
+	}
+
+	expr, err := parser.ParseExpr(apiv1.ValuesSelector.String(), values)
+	if err != nil {
+		return "", fmt.Errorf("parsing values failed: %w", err)
+	}
+	evaluated := cuecontext.New().BuildExpr(expr)
+
+	for _, p := range paths {
+		field := lookupField(expr, p.Selectors())
+		if field == nil {
+			if evaluated.LookupPath(p).Exists() {
+				return "", fmt.Errorf("sensitive value %s can't be located in the values", p)
+			}
+			continue
+		}
+
+		if len(recipients) == 0 {
+			field.Value = ast.NewString(sops.RedactedValue)
+			continue
+		}
+
+		data, err := exprToJSON(field.Value)
+		if err != nil {
+			return "", fmt.Errorf("encoding %s failed: %w", p, err)
+		}
+		enc, err := sops.EncryptValue(string(data), recipients)
+		if err != nil {
+			return "", fmt.Errorf("encrypting %s failed: %w", p, err)
+		}
+		field.Value = ast.NewString(enc)
+	}
+
+	return formatValues(expr)
+}
+
+// RevealValues replaces the encrypted values with the result of the
+// decrypt function. When decrypt is nil, the encrypted values are
+// replaced with the redacted value. The values are returned
+// unchanged if they contain no encrypted values.
+func RevealValues(values string, decrypt func(string) (string, error)) (string, error) {
+	expr, err := parser.ParseExpr(apiv1.ValuesSelector.String(), values)
+	if err != nil {
+		return "", fmt.Errorf("parsing values failed: %w", err)
+	}
+
+	var found bool
+	var walkErr error
+	ast.Walk(expr, nil, func(n ast.Node) {
+		field, ok := n.(*ast.Field)
+		if !ok || walkErr != nil {
+			return
+		}
+		enc, ok := encryptedValue(field.Value)
+		if !ok {
+			return
+		}
+		found = true
+		if decrypt == nil {
+			field.Value = ast.NewString(sops.RedactedValue)
+			return
+		}
+		plain, err := decrypt(enc)
+		if err != nil {
+			walkErr = fmt.Errorf("decrypting %s failed: %w", labelName(field.Label), err)
+			return
+		}
+		v, err := cuejson.Extract(labelName(field.Label), []byte(plain))
+		if err != nil {
+			walkErr = fmt.Errorf("decoding %s failed: %w", labelName(field.Label), err)
+			return
+		}
+		field.Value = v
+	})
+	if walkErr != nil {
+		return "", walkErr
+	}
+	if !found {
+		return values, nil
+	}
+
+	return formatValues(expr)
+}
+
+// lookupField returns the field found at the given path in the struct
+// literal, following the list indexes, or nil if the path is not set.
+func lookupField(expr ast.Expr, sels []cue.Selector) *ast.Field {
+	var field *ast.Field
+	for _, sel := range sels {
+		field = nil
+		switch x := expr.(type) {
+		case *ast.StructLit:
+			if sel.LabelType() != cue.StringLabel {
+				return nil
+			}
+			for _, elt := range x.Elts {
+				f, ok := elt.(*ast.Field)
+				if ok && labelName(f.Label) == sel.Unquoted() {
+					field = f
+				}
+			}
+			if field == nil {
+				return nil
+			}
+			expr = field.Value
+		case *ast.ListLit:
+			if sel.LabelType() != cue.IndexLabel || sel.Index() >= len(x.Elts) {
+				return nil
+			}
+			expr = x.Elts[sel.Index()]
+		default:
+			return nil
+		}
+	}
+	return field
+}
+
+func labelName(l ast.Label) string {
+	name, _, _ := ast.LabelName(l)
+	return name
+}
+
+func encryptedValue(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	s, err := literal.Unquote(lit.Value)
+	if err != nil || !strings.HasPrefix(s, encryptedValuePrefix) {
+		return "", false
+	}
+	return s, true
+}
+
+func exprToJSON(expr ast.Expr) ([]byte, error) {
+	v := cuecontext.New().BuildExpr(expr)
+	if v.Err() != nil {
+		return nil, v.Err()
+	}
+	return v.MarshalJSON()
+}
+
+func formatValues(expr ast.Expr) (string, error) {
+	out, err := format.Node(expr)
+	if err != nil {
+		return "", fmt.Errorf("formatting values failed: %w", err)
+	}
+	return string(out), nil
+}
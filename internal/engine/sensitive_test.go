@@ -0,0 +1,169 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"os"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+const sensitiveTestModule = `
+#Config: {
+	user: string
+	password: string @timoni(sensitive)
+	db: {
+		port: int
+		token?: string @timoni(sensitive)
+	}
+	creds: {[string]: string} @timoni(sensitive)
+	users: [...{
+		name:     string
+		password: string @timoni(sensitive)
+	}]
+	clusters: [string]: {
+		url: string
+		key: string @timoni(sensitive)
+	}
+}
+values: #Config
+values: {
+	user: "admin"
+	password: "secret-password"
+	db: {
+		port: 5432
+		token: "secret-token"
+	}
+	creds: {
+		key: "secret-key"
+	}
+	users: [{name: "alice", password: "secret-one"}, {name: "bob", password: "secret-two"}]
+	clusters: prod: {url: "https://prod.internal", key: "secret-prod"}
+}
+`
+
+const sensitiveTestValues = `{
+	user:     "admin"
+	password: "secret-password"
+	db: {
+		port:  5432
+		token: "secret-token"
+	}
+	creds: key: "secret-key"
+	users: [{
+		name:     "alice"
+		password: "secret-one"
+	}, {
+		name:     "bob"
+		password: "secret-two"
+	}]
+	clusters: prod: {
+		url: "https://prod.internal"
+		key: "secret-prod"
+	}
+}`
+
+func TestGetSensitivePaths(t *testing.T) {
+	g := NewWithT(t)
+	b := &ModuleBuilder{}
+
+	value := cuecontext.New().CompileString(sensitiveTestModule)
+	g.Expect(value.Err()).ToNot(HaveOccurred())
+
+	paths, err := b.GetSensitivePaths(value)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var names []string
+	for _, p := range paths {
+		names = append(names, p.String())
+	}
+	g.Expect(names).To(Equal([]string{"password", "db.token", "creds",
+		"users[0].password", "users[1].password", "clusters.prod.key"}))
+
+	g.Expect(b.GetSensitiveValues(value, paths)).To(ConsistOf(
+		"secret-password", "secret-token", "secret-key",
+		"secret-one", "secret-two", "secret-prod"))
+}
+
+func TestProtectValues(t *testing.T) {
+	paths := []cue.Path{
+		cue.ParsePath("password"),
+		cue.ParsePath("db.token"),
+		cue.ParsePath("creds"),
+		cue.ParsePath("users[0].password"),
+		cue.ParsePath("users[1].password"),
+		cue.ParsePath("clusters.prod.key"),
+		cue.ParsePath("missing.field"),
+		cue.ParsePath("users[2].password"),
+	}
+
+	t.Run("redacts the sensitive values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := ProtectValues(sensitiveTestValues, paths, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(out).ToNot(ContainSubstring("secret"))
+		g.Expect(out).To(ContainSubstring(`password: "***"`))
+		g.Expect(out).To(ContainSubstring(`user:     "admin"`))
+		g.Expect(out).To(ContainSubstring(`port:  5432`))
+		g.Expect(out).To(ContainSubstring(`name:     "alice"`))
+		g.Expect(out).To(ContainSubstring(`url: "https://prod.internal"`))
+	})
+
+	t.Run("encrypts and restores the sensitive values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out, err := ProtectValues(sensitiveTestValues, paths,
+			[]string{"age1eyxrzed9wzyp99tkmqwmqq8hdh60ak62pvadpwymg2jxgl2lvsgq7s0vfs"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(out).ToNot(ContainSubstring("secret"))
+		g.Expect(out).To(ContainSubstring(encryptedValuePrefix))
+
+		masked, err := RevealValues(out, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(masked).ToNot(ContainSubstring(encryptedValuePrefix))
+		g.Expect(masked).To(ContainSubstring(`password: "***"`))
+
+		keyFile := "../sops/testdata/age.txt"
+		_, err = os.Stat(keyFile)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		decrypter := sops.NewDecrypter(keyFile)
+		restored, err := RevealValues(out, decrypter.DecryptValue)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx := cuecontext.New()
+		want := ctx.CompileString(sensitiveTestValues)
+		got := ctx.CompileString(restored)
+		g.Expect(got.Err()).ToNot(HaveOccurred())
+		g.Expect(got.Equals(want)).To(BeTrue(), restored)
+	})
+
+	t.Run("fails for the sensitive values it can't locate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ProtectValues(`{
+	{password: "secret-password"}
+}`, paths, nil)
+		g.Expect(err).To(MatchError(ContainSubstring("sensitive value password can't be located")))
+	})
+}
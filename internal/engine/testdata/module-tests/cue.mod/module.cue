@@ -0,0 +1,2 @@
+module: "timoni.sh/test-tests"
+language: version: "v0.17.1"
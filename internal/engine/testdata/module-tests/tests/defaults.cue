@@ -0,0 +1,8 @@
+package tests
+
+description: "renders the objects with the default values"
+
+assert: {
+	"renders two objects": len(apply.app) == 2
+	"sets the message":    apply.app[0].data.message == "hello"
+}
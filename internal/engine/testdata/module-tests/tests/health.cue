@@ -0,0 +1,24 @@
+package tests
+
+description: "evaluates the Demo health check"
+
+healthChecks: [
+	{
+		object: {
+			apiVersion: "testing.timoni.sh/v1alpha1"
+			kind:       "Demo"
+			spec: replicas: 1
+			status: readyReplicas: 1
+		}
+		status: "Current"
+	},
+	{
+		object: {
+			apiVersion: "testing.timoni.sh/v1alpha1"
+			kind:       "Demo"
+			spec: replicas: 2
+			status: readyReplicas: 1
+		}
+		status: "InProgress"
+	},
+]
@@ -0,0 +1,7 @@
+package tests
+
+description: "rejects replicas over the limit"
+
+values: replicas: 20
+
+expectError: "invalid value 20"
@@ -0,0 +1,11 @@
+package tests
+
+import "list"
+
+description: "renders the replicas set in values"
+
+values: replicas: 3
+
+assert: {
+	"sets the replicas": list.Contains([for o in apply.app if o.kind == "Demo" {o.spec.replicas}], 3)
+}
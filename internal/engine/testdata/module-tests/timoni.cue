@@ -0,0 +1,54 @@
+package main
+
+// Define the schema for the user-supplied values.
+values: {
+	replicas: *1 | int & >0 & <=10
+	message:  *"hello" | string
+}
+
+timoni: {
+	apiVersion: "v1alpha1"
+
+	instance: {
+		config: {
+			metadata: {
+				name:      string @tag(name)
+				namespace: string @tag(namespace)
+			}
+			replicas: values.replicas
+			message:  values.message
+		}
+
+		objects: {
+			cm: {
+				apiVersion: "v1"
+				kind:       "ConfigMap"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				data: message: config.message
+			}
+
+			demo: {
+				apiVersion: "testing.timoni.sh/v1alpha1"
+				kind:       "Demo"
+				metadata: {
+					name:      config.metadata.name
+					namespace: config.metadata.namespace
+				}
+				spec: replicas: config.replicas
+			}
+		}
+	}
+
+	apply: app: [for obj in instance.objects {obj}]
+
+	healthChecks: demo: {
+		group: "testing.timoni.sh"
+		kind:  "Demo"
+		#object: status?: readyReplicas?: int
+		current: #object.status.readyReplicas == #object.spec.replicas
+		failed:  #object.status.readyReplicas < 0
+	}
+}
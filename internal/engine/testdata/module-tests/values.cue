@@ -0,0 +1,7 @@
+// Note that this file must have no imports and all values must be concrete.
+
+package main
+
+values: {
+	replicas: 1
+}
@@ -8,6 +8,7 @@ package templates
 	hostname:      *"default.internal" | string
 	moduleVersion: string
 	kubeVersion:   string
+	apiVersions: [...string]
 }
 
 #Instance: {
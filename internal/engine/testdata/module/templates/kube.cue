@@ -1,6 +1,9 @@
 package templates
 
-import "strings"
+import (
+	"list"
+	"strings"
+)
 
 #KubeConfig: {
 	_config:    #Config
@@ -13,5 +16,8 @@ import "strings"
 		if strings.HasPrefix(_config.kubeVersion, "1.25") {
 			kubeVersion: _config.kubeVersion
 		}
+		if list.Contains(_config.apiVersions, "monitoring.coreos.com/v1/ServiceMonitor") {
+			monitoring: "enabled"
+		}
 	}
 }
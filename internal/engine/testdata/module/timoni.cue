@@ -34,6 +34,7 @@ timoni: {
 		config: {
 			moduleVersion: string @tag(mv, var=moduleVersion)
 			kubeVersion:   string @tag(kv, var=kubeVersion)
+			apiVersions: [...string] @tag(av, var=apiVersions)
 		}
 	}
 
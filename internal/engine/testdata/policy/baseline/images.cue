@@ -0,0 +1,14 @@
+package baseline
+
+import "strings"
+
+#registries: ["ghcr.io/", "registry.k8s.io/"]
+
+rules: "approved-registries": {
+	match: kind: "Deployment"
+	severity: "warn"
+	message:  "images must be pulled from the approved registries"
+	#object:  #PodController
+	allow: len([for c in #object.spec.template.spec.containers
+		if len([for r in #registries if strings.HasPrefix(c.image, r) {r}]) == 0 {c}]) == 0
+}
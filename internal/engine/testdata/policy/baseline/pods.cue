@@ -0,0 +1,21 @@
+package baseline
+
+#PodController: {
+	spec: template: spec: containers: [...{
+		image: string
+		securityContext: privileged: *false | bool
+		...
+	}]
+	...
+}
+
+rules: "no-privileged": {
+	match: {
+		group: "apps"
+		kind:  "Deployment"
+	}
+	message: "privileged containers are not allowed"
+	#object: #PodController
+	allow: len([for c in #object.spec.template.spec.containers
+		if c.securityContext.privileged {c}]) == 0
+}
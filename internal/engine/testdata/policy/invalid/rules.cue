@@ -0,0 +1,3 @@
+rules: "missing-message": {
+	allow: true
+}
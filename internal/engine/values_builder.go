@@ -42,6 +42,15 @@ func (b *ValuesBuilder) MergeValues(overlays [][]byte, base string) (cue.Value,
 			fmt.Errorf("loading values from %s failed: %w", base, err)
 	}
 
+	return b.mergeOverlays(overlays, baseVal)
+}
+
+// MergeOverlays merges the given overlays in order, without the module defaults.
+func (b *ValuesBuilder) MergeOverlays(overlays [][]byte) (cue.Value, error) {
+	return b.mergeOverlays(overlays, b.ctx.CompileString("{}"))
+}
+
+func (b *ValuesBuilder) mergeOverlays(overlays [][]byte, baseVal cue.Value) (cue.Value, error) {
 	for _, overlay := range overlays {
 		overlayVal, err := ExtractValueFromBytes(b.ctx, overlay, apiv1.ValuesSelector.String())
 		if err != nil {
@@ -0,0 +1,202 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"cuelang.org/go/cue"
+	"github.com/Masterminds/semver/v3"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// ValuesMigration transforms the values written for previous versions of
+// a module into values for the current version, as declared by the module
+// under 'timoni: migrations:'.
+type ValuesMigration struct {
+	// From is the semver constraint matching the module versions
+	// the values were written for.
+	From string `json:"from"`
+
+	// Description of the changes made by the migration.
+	Description string `json:"description,omitempty"`
+
+	// Rename maps the old paths of the moved fields to the new ones.
+	Rename map[string]string `json:"rename,omitempty"`
+
+	// value is the migration CUE value holding the input and output fields.
+	value cue.Value
+}
+
+// String returns the migration description, or its version constraint
+// when the description is not set.
+func (m ValuesMigration) String() string {
+	if m.Description != "" {
+		return m.Description
+	}
+	return fmt.Sprintf("migration from %s", m.From)
+}
+
+// GetValuesMigrations extracts the migrations declared by the module under
+// 'timoni: migrations:' that match the version of the module the values were
+// written for. The value must be built with BuildConfig before the user values
+// are overlaid. The version is compared without its prerelease and build
+// metadata, and an empty version matches no migrations.
+func (b *ModuleBuilder) GetValuesMigrations(value cue.Value, version string) ([]ValuesMigration, error) {
+	v := value.LookupPath(cue.ParsePath(apiv1.MigrationsSelector.String()))
+	if !v.Exists() || version == "" {
+		return nil, nil
+	}
+	if err := v.Err(); err != nil {
+		return nil, fmt.Errorf("lookup %s failed: %w", apiv1.MigrationsSelector, err)
+	}
+
+	sv, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("parsing version %s failed: %w", version, err)
+	}
+	current := semver.New(sv.Major(), sv.Minor(), sv.Patch(), "", "")
+
+	iter, err := v.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing %s failed: %w", apiv1.MigrationsSelector, err)
+	}
+
+	var migrations []ValuesMigration
+	for iter.Next() {
+		var m ValuesMigration
+		if err := iter.Value().Decode(&m); err != nil {
+			return nil, fmt.Errorf("decoding %s failed: %w", apiv1.MigrationsSelector, err)
+		}
+		m.value = iter.Value()
+
+		constraint, err := semver.NewConstraint(m.From)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the migration constraint %s failed: %w", m.From, err)
+		}
+		if constraint.Check(current) {
+			migrations = append(migrations, m)
+		}
+	}
+	return migrations, nil
+}
+
+// MigrateValues runs the migrations in order on the given values and returns
+// the migrated values. For each migration, the fields listed in rename are
+// moved first, then the values are set as the migration input and replaced
+// with its output. The values must be concrete and can come from another
+// CUE context.
+func (b *ModuleBuilder) MigrateValues(values cue.Value, migrations []ValuesMigration) (cue.Value, error) {
+	out := b.ctx.CompileString(fmt.Sprintf("%v", values))
+	if err := out.Validate(cue.Concrete(true)); err != nil {
+		return out, fmt.Errorf("values must be concrete to be migrated: %w", err)
+	}
+
+	for _, m := range migrations {
+		if len(m.Rename) > 0 {
+			var data map[string]any
+			if err := out.Decode(&data); err != nil {
+				return out, fmt.Errorf("%s: decoding values failed: %w", m, err)
+			}
+			for _, from := range slices.Sorted(maps.Keys(m.Rename)) {
+				if err := renameValue(data, from, m.Rename[from]); err != nil {
+					return out, fmt.Errorf("%s: %w", m, err)
+				}
+			}
+			out = b.ctx.Encode(data)
+		}
+
+		output := m.value.FillPath(cue.ParsePath("input"), out).LookupPath(cue.ParsePath("output"))
+		if !output.Exists() {
+			continue
+		}
+		if err := output.Validate(cue.Concrete(true)); err != nil {
+			return out, fmt.Errorf("%s: %w", m, err)
+		}
+		out = output
+	}
+	return out, nil
+}
+
+// renameValue moves the field at the from path to the to path, creating
+// the parent structs as needed. A missing field is skipped.
+func renameValue(data map[string]any, from, to string) error {
+	fromLabels, err := pathLabels(from)
+	if err != nil {
+		return err
+	}
+	toLabels, err := pathLabels(to)
+	if err != nil {
+		return err
+	}
+
+	parent := data
+	for _, label := range fromLabels[:len(fromLabels)-1] {
+		next, ok := parent[label].(map[string]any)
+		if !ok {
+			return nil
+		}
+		parent = next
+	}
+	last := fromLabels[len(fromLabels)-1]
+	value, ok := parent[last]
+	if !ok {
+		return nil
+	}
+	delete(parent, last)
+
+	parent = data
+	for _, label := range toLabels[:len(toLabels)-1] {
+		next, ok := parent[label].(map[string]any)
+		if !ok {
+			if _, exists := parent[label]; exists {
+				return fmt.Errorf("renaming %s to %s failed: %s is not a struct", from, to, label)
+			}
+			next = make(map[string]any)
+			parent[label] = next
+		}
+		parent = next
+	}
+	parent[toLabels[len(toLabels)-1]] = value
+	return nil
+}
+
+// pathLabels returns the unquoted labels of a CUE path
+// e.g. 'metadata.annotations."example.com/team"'.
+func pathLabels(p string) ([]string, error) {
+	path := cue.ParsePath(p)
+	if err := path.Err(); err != nil {
+		return nil, fmt.Errorf("invalid path %s: %w", p, err)
+	}
+
+	selectors := path.Selectors()
+	if len(selectors) == 0 {
+		return nil, fmt.Errorf("invalid path %s: empty", p)
+	}
+
+	labels := make([]string, 0, len(selectors))
+	for _, sel := range selectors {
+		if sel.LabelType() != cue.StringLabel {
+			return nil, fmt.Errorf("invalid path %s: %s is not a field name", p, sel)
+		}
+		labels = append(labels, sel.Unquoted())
+	}
+	return labels, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+)
+
+const testMigrations = `
+timoni: migrations: [
+	{
+		from:        "<2.0.0"
+		description: "image.repo renamed to image.repository"
+		rename: "image.repo": "image.repository"
+		input: {...}
+	},
+	{
+		from: "<3.0.0"
+		input: {...}
+		output: {
+			for k, v in input if k != "replicas" {(k): v}
+			if input.replicas != _|_ {
+				autoscaling: minReplicas: input.replicas
+			}
+		}
+	},
+]
+`
+
+func TestValuesMigration_GetValuesMigrations(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{version: "1.5.0", want: []string{"<2.0.0", "<3.0.0"}},
+		{version: "2.0.0-rc.1", want: []string{"<3.0.0"}},
+		{version: "2.1.0", want: []string{"<3.0.0"}},
+		{version: "3.0.0", want: nil},
+		{version: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+			v := ctx.CompileString(testMigrations)
+			g.Expect(v.Err()).ToNot(HaveOccurred())
+
+			b := NewModuleBuilder(ctx, "test", "default", t.TempDir(), defaultPackage)
+			migrations, err := b.GetValuesMigrations(v, tt.version)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var got []string
+			for _, m := range migrations {
+				got = append(got, m.From)
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestValuesMigration_MigrateValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		values  string
+		want    string
+	}{
+		{
+			name:    "renames and transforms",
+			version: "1.0.0",
+			values:  `{image: {repo: "nginx", tag: "1.27"}, replicas: 2}`,
+			want:    `{"image":{"repository":"nginx","tag":"1.27"},"autoscaling":{"minReplicas":2}}`,
+		},
+		{
+			name:    "transforms only",
+			version: "2.0.0",
+			values:  `{image: {repo: "nginx"}, replicas: 2}`,
+			want:    `{"image":{"repo":"nginx"},"autoscaling":{"minReplicas":2}}`,
+		},
+		{
+			name:    "skips missing fields",
+			version: "1.0.0",
+			values:  `{domain: "example.com"}`,
+			want:    `{"domain":"example.com"}`,
+		},
+		{
+			name:    "no migrations",
+			version: "3.0.0",
+			values:  `{replicas: 2}`,
+			want:    `{"replicas":2}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+			b := NewModuleBuilder(ctx, "test", "default", t.TempDir(), defaultPackage)
+
+			migrations, err := b.GetValuesMigrations(ctx.CompileString(testMigrations), tt.version)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// The values come from another CUE context, like the bundle values.
+			values := cuecontext.New().CompileString(tt.values)
+			out, err := b.MigrateValues(values, migrations)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			data, err := out.MarshalJSON()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(data)).To(MatchJSON(tt.want))
+		})
+	}
+}
+
+func TestValuesMigration_MigrateValuesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations string
+		values     string
+		wantErr    string
+	}{
+		{
+			name:       "invalid constraint",
+			migrations: `timoni: migrations: [{from: "not a version", input: {...}}]`,
+			values:     `{}`,
+			wantErr:    "parsing the migration constraint",
+		},
+		{
+			name:       "rename to a non-struct",
+			migrations: `timoni: migrations: [{from: "<2.0.0", rename: "a": "b.c", input: {...}}]`,
+			values:     `{a: 1, b: "test"}`,
+			wantErr:    "b is not a struct",
+		},
+		{
+			name:       "incomplete output",
+			migrations: `timoni: migrations: [{from: "<2.0.0", input: {...}, output: {name: string}}]`,
+			values:     `{}`,
+			wantErr:    "incomplete value",
+		},
+		{
+			name:       "values not concrete",
+			migrations: `timoni: migrations: [{from: "<2.0.0", input: {...}}]`,
+			values:     `{name: string}`,
+			wantErr:    "values must be concrete",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := cuecontext.New()
+			b := NewModuleBuilder(ctx, "test", "default", t.TempDir(), defaultPackage)
+
+			migrations, err := b.GetValuesMigrations(ctx.CompileString(tt.migrations), "1.0.0")
+			if err == nil {
+				_, err = b.MigrateValues(ctx.CompileString(tt.values), migrations)
+			}
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(fmt.Sprint(err)).To(ContainSubstring(tt.wantErr))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+	cuejson "cuelang.org/go/encoding/json"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+// ParseSetValue splits a command line override in the format
+// 'path.to.field=value' into the field path and the raw value.
+func ParseSetValue(s string) (string, string, error) {
+	path, value, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(path) == "" {
+		return "", "", fmt.Errorf("invalid value override %q, must be in the format 'path.to.field=value'", s)
+	}
+	return strings.TrimSpace(path), value, nil
+}
+
+// SetValueOverlay generates a values overlay that sets the field at the
+// given path to the value expression. The path uses the CUE syntax, with
+// the labels that are not valid identifiers in double quotes,
+// e.g. 'metadata.annotations."app.kubernetes.io/name"'.
+func SetValueOverlay(path string, value ast.Expr) ([]byte, error) {
+	p := cue.ParsePath(path)
+	if err := p.Err(); err != nil {
+		return nil, fmt.Errorf("invalid field path %s: %w", path, err)
+	}
+
+	sels := p.Selectors()
+	if len(sels) == 0 {
+		return nil, fmt.Errorf("invalid field path %s: empty path", path)
+	}
+
+	expr := value
+	for i := len(sels) - 1; i >= 0; i-- {
+		sel := sels[i]
+		if sel.LabelType() != cue.StringLabel {
+			return nil, fmt.Errorf("invalid field path %s: %s is not a regular field", path, sel)
+		}
+		expr = ast.NewStruct(&ast.Field{
+			Label: ast.NewStringLabel(sel.Unquoted()),
+			Value: expr,
+		})
+	}
+
+	f := &ast.File{Decls: []ast.Decl{
+		&ast.Field{
+			Label: ast.NewIdent(apiv1.ValuesSelector.String()),
+			Value: expr,
+		},
+	}}
+
+	data, err := format.Node(f, format.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("formatting the value of %s failed: %w", path, err)
+	}
+	return data, nil
+}
+
+// TypedValueExpr converts a raw command line value to a CUE expression.
+// The booleans, null, numbers in their canonical form and the JSON objects
+// and arrays keep their type, any other value is quoted as a string.
+// The value is never evaluated as CUE source.
+func TypedValueExpr(value string) (ast.Expr, error) {
+	switch value {
+	case "true", "false":
+		return ast.NewBool(value == "true"), nil
+	case "null":
+		return ast.NewNull(), nil
+	}
+
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(i, 10) == value {
+		return ast.NewLit(token.INT, value), nil
+	}
+
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) &&
+		strings.Contains(value, ".") && strconv.FormatFloat(f, 'f', -1, 64) == value {
+		return ast.NewLit(token.FLOAT, value), nil
+	}
+
+	if (strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[")) && json.Valid([]byte(value)) {
+		expr, err := cuejson.Extract("", []byte(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON value %s: %w", value, err)
+		}
+		return expr, nil
+	}
+
+	return ast.NewString(value), nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	. "github.com/onsi/gomega"
+)
+
+func TestSetValueOverlay(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      string
+		asString bool
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "sets an integer",
+			set:  "replicas=3",
+			want: "values: replicas: 3\n",
+		},
+		{
+			name: "sets a nested boolean",
+			set:  "monitoring.enabled=true",
+			want: "values: monitoring: enabled: true\n",
+		},
+		{
+			name: "sets a float",
+			set:  "resources.cpu=0.5",
+			want: "values: resources: cpu: 0.5\n",
+		},
+		{
+			name: "keeps non-canonical numbers as strings",
+			set:  "image.tag=1.20",
+			want: "values: image: tag: \"1.20\"\n",
+		},
+		{
+			name:     "quotes the values set as strings",
+			set:      "image.tag=3",
+			asString: true,
+			want:     "values: image: tag: \"3\"\n",
+		},
+		{
+			name: "does not evaluate CUE expressions",
+			set:  `message=hello" | *"world`,
+			want: "values: message: \"hello\\\" | *\\\"world\"\n",
+		},
+		{
+			name: "quotes the labels that are not identifiers",
+			set:  `metadata.annotations."app.kubernetes.io/name"=podinfo`,
+			want: "values: metadata: annotations: \"app.kubernetes.io/name\": \"podinfo\"\n",
+		},
+		{
+			name: "sets JSON arrays",
+			set:  `ports=[80,443]`,
+			want: "values: ports: [80, 443]\n",
+		},
+		{
+			name:    "rejects overrides without a value",
+			set:     "replicas",
+			wantErr: "must be in the format 'path.to.field=value'",
+		},
+		{
+			name:    "rejects definitions",
+			set:     "#Config.replicas=3",
+			wantErr: "is not a regular field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			overlay, err := func() ([]byte, error) {
+				path, raw, err := ParseSetValue(tt.set)
+				if err != nil {
+					return nil, err
+				}
+				var value ast.Expr = ast.NewString(raw)
+				if !tt.asString {
+					value, err = TypedValueExpr(raw)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return SetValueOverlay(path, value)
+			}()
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(overlay)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSetValueOverlay_Merge(t *testing.T) {
+	g := NewWithT(t)
+	ctx := cuecontext.New()
+
+	first, err := SetValueOverlay("image.tag", ast.NewString("1.0.0"))
+	g.Expect(err).ToNot(HaveOccurred())
+	second, err := SetValueOverlay("image.tag", ast.NewString("2.0.0"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	vb := NewValuesBuilder(ctx)
+	val, err := vb.MergeValues([][]byte{first, second}, "testdata/module/values.cue")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tag, err := val.LookupPath(cue.ParsePath("image.tag")).String()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tag).To(Equal("2.0.0"))
+}
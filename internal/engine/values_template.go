@@ -0,0 +1,393 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+)
+
+// ValuesTemplateFormats are the formats supported by FormatValuesTemplate.
+var ValuesTemplateFormats = []string{"cue", "yaml", "json"}
+
+// yamlPlainKeyRegex matches the map keys that YAML parses as plain strings.
+var yamlPlainKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+
+// templateNode is a field of the values template.
+type templateNode struct {
+	label     string
+	doc       string
+	value     string
+	typed     bool
+	required  bool
+	commented bool
+	children  []*templateNode
+}
+
+// FormatValuesTemplate renders the config fields as a values file skeleton
+// in the cue, yaml or json format. The required fields are set to a
+// placeholder that fails validation until it is replaced, the fields with
+// a default are set to it, and the optional fields are commented out with
+// their documentation. JSON has no comments, so the optional fields are
+// left out. With onlyRequired, the template contains only the required
+// fields. The fields marked with +nodoc and the fields computed from the
+// values injected at apply time are left out, unless they are required.
+func FormatValuesTemplate(fields []ConfigField, format string, onlyRequired bool) (string, error) {
+	nodes := templateNodes(newConfigTree(fields), onlyRequired, false)
+	switch format {
+	case "cue":
+		return formatTemplateCUE(nodes)
+	case "yaml":
+		return formatTemplateYAML(nodes)
+	case "json":
+		return formatTemplateJSON(nodes)
+	}
+	return "", fmt.Errorf("unsupported values format %q, can be %s",
+		format, strings.Join(ValuesTemplateFormats, ", "))
+}
+
+// templateNodes selects the fields of the values template from the
+// config tree, commenting out the optional fields and their children.
+func templateNodes(n *configNode, onlyRequired, commented bool) []*templateNode {
+	var nodes []*templateNode
+	for _, label := range n.order {
+		child := n.children[label]
+		f := child.field
+		optional := f != nil && f.Optional
+		tn := &templateNode{
+			label:     label,
+			commented: commented || optional,
+		}
+		if f != nil && !f.NoDoc {
+			tn.doc = f.Doc
+		}
+
+		if len(child.order) > 0 {
+			if onlyRequired && optional {
+				continue
+			}
+			tn.children = templateNodes(child, onlyRequired, tn.commented)
+			if len(tn.children) > 0 {
+				nodes = append(nodes, tn)
+			}
+			continue
+		}
+
+		tn.required = f.MustSet()
+		switch {
+		case onlyRequired && (!tn.required || tn.commented):
+			continue
+		case !f.Required && (f.NoDoc || f.Unresolved):
+			continue
+		case f.Default != "" && !tn.required:
+			tn.value = f.Default
+		default:
+			// A struct or list without a default is shown as its type.
+			tn.value = f.Type
+			tn.typed = true
+			tn.commented = tn.commented || !tn.required
+		}
+		nodes = append(nodes, tn)
+	}
+	return nodes
+}
+
+// templateWriter writes the template lines, prefixing the commented out
+// fields with the comment marker after the indentation of the outermost
+// commented field.
+type templateWriter struct {
+	sb      strings.Builder
+	indent  string
+	comment string
+}
+
+func (w *templateWriter) line(depth, commentDepth int, text string) {
+	if commentDepth < 0 {
+		w.sb.WriteString(strings.Repeat(w.indent, depth) + text + "\n")
+		return
+	}
+	w.sb.WriteString(strings.Repeat(w.indent, commentDepth) + w.comment + " " +
+		strings.Repeat(w.indent, depth-commentDepth) + text + "\n")
+}
+
+func (w *templateWriter) doc(depth int, doc string) {
+	for _, line := range strings.Split(doc, "\n") {
+		if line != "" {
+			w.sb.WriteString(strings.Repeat(w.indent, depth) + w.comment + " " + line + "\n")
+		}
+	}
+}
+
+// formatTemplateCUE renders the template as a CUE values file, using the
+// field type as the placeholder of the required fields when it refers
+// only to the CUE builtin types, and top otherwise.
+func formatTemplateCUE(nodes []*templateNode) (string, error) {
+	w := &templateWriter{indent: "\t", comment: "//"}
+	w.sb.WriteString("values: {\n")
+	var write func(nodes []*templateNode, depth, commentDepth int)
+	write = func(nodes []*templateNode, depth, commentDepth int) {
+		for _, n := range nodes {
+			cd := commentDepth
+			if n.commented && cd < 0 {
+				cd = depth
+			}
+			w.doc(depth, n.doc)
+			switch {
+			case len(n.children) > 0:
+				w.line(depth, cd, n.label+": {")
+				write(n.children, depth+1, cd)
+				w.line(depth, cd, "}")
+			case n.required:
+				placeholder := "_"
+				if isBuiltinType(n.value) {
+					placeholder = n.value
+				}
+				w.line(depth, cd, n.label+": "+placeholder+" // required")
+			default:
+				w.line(depth, cd, n.label+": "+n.value)
+			}
+		}
+	}
+	write(nodes, 1, -1)
+	w.sb.WriteString("}\n")
+
+	out, err := format.Source([]byte(w.sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting the values failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// formatTemplateYAML renders the template as a YAML values file, with the
+// defaults in the JSON flow style, null as the placeholder of the
+// required fields and the empty value of the type as the placeholder of
+// the fields without a default.
+func formatTemplateYAML(nodes []*templateNode) (string, error) {
+	if len(nodes) == 0 {
+		return "values: {}\n", nil
+	}
+
+	w := &templateWriter{indent: "  ", comment: "#"}
+	w.sb.WriteString("values:\n")
+	var write func(nodes []*templateNode, depth, commentDepth int) error
+	write = func(nodes []*templateNode, depth, commentDepth int) error {
+		for _, n := range nodes {
+			cd := commentDepth
+			if n.commented && cd < 0 {
+				cd = depth
+			}
+			key := yamlKey(n.label)
+			w.doc(depth, n.doc)
+			switch {
+			case len(n.children) > 0:
+				w.line(depth, cd, key+":")
+				if err := write(n.children, depth+1, cd); err != nil {
+					return err
+				}
+			case n.required:
+				w.line(depth, cd, key+": null # required, "+n.value)
+			case n.typed:
+				w.line(depth, cd, key+": "+yamlPlaceholder(n.value)+" # "+n.value)
+			default:
+				value, err := cueLiteralJSON(n.value)
+				if err != nil {
+					return fmt.Errorf("converting %s to JSON failed: %w", n.label, err)
+				}
+				w.line(depth, cd, key+": "+value)
+			}
+		}
+		return nil
+	}
+	if err := write(nodes, 1, -1); err != nil {
+		return "", err
+	}
+	return w.sb.String(), nil
+}
+
+// formatTemplateJSON renders the template as a JSON values file without
+// the commented out fields, with null as the placeholder of the required
+// fields.
+func formatTemplateJSON(nodes []*templateNode) (string, error) {
+	var buf bytes.Buffer
+	var write func(nodes []*templateNode) error
+	write = func(nodes []*templateNode) error {
+		buf.WriteString("{")
+		first := true
+		for _, n := range nodes {
+			if n.commented {
+				continue
+			}
+			if !first {
+				buf.WriteString(",")
+			}
+			first = false
+			key, err := json.Marshal(unquoteLabel(n.label))
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteString(":")
+			switch {
+			case len(n.children) > 0:
+				if err := write(n.children); err != nil {
+					return err
+				}
+			case n.required:
+				buf.WriteString("null")
+			default:
+				value, err := cueLiteralJSON(n.value)
+				if err != nil {
+					return fmt.Errorf("converting %s to JSON failed: %w", n.label, err)
+				}
+				buf.WriteString(value)
+			}
+		}
+		buf.WriteString("}")
+		return nil
+	}
+
+	buf.WriteString(`{"values":`)
+	if err := write(nodes); err != nil {
+		return "", err
+	}
+	buf.WriteString("}")
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return "", err
+	}
+	out.WriteString("\n")
+	return out.String(), nil
+}
+
+// cueLiteralJSON converts a concrete CUE literal to compact JSON.
+func cueLiteralJSON(lit string) (string, error) {
+	v := cuecontext.New().CompileString(lit)
+	if v.Err() != nil {
+		return "", v.Err()
+	}
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// unquoteLabel returns the field name of a CUE label.
+func unquoteLabel(label string) string {
+	if s, err := strconv.Unquote(label); err == nil {
+		return s
+	}
+	return label
+}
+
+// yamlKey renders a CUE label as a YAML map key, quoting the keys
+// that YAML would not parse as plain strings.
+func yamlKey(label string) string {
+	name := unquoteLabel(label)
+	if yamlPlainKeyRegex.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// yamlPlaceholder returns the empty YAML value of the CUE type, taking
+// the first operand of disjunctions and unifications, or null when the
+// type has no obvious empty value.
+func yamlPlaceholder(typ string) string {
+	e, err := parser.ParseExpr("type", typ)
+	if err != nil {
+		return "null"
+	}
+	for {
+		switch x := e.(type) {
+		case *ast.StructLit:
+			return "{}"
+		case *ast.ListLit:
+			return "[]"
+		case *ast.BasicLit:
+			switch x.Kind {
+			case token.STRING:
+				return `""`
+			case token.INT, token.FLOAT:
+				return "0"
+			case token.TRUE, token.FALSE:
+				return "false"
+			}
+			return "null"
+		case *ast.Ident:
+			switch x.Name {
+			case "struct":
+				return "{}"
+			case "list":
+				return "[]"
+			case "string", "bytes":
+				return `""`
+			case "bool":
+				return "false"
+			case "int", "float", "number",
+				"uint", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+				return "0"
+			}
+			return "null"
+		case *ast.BinaryExpr:
+			e = x.X
+		case *ast.UnaryExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		default:
+			return "null"
+		}
+	}
+}
+
+// isBuiltinType reports whether the CUE expression refers only to the
+// builtin types and literals, so that it can be used in a values file
+// without the definitions and imports of the module.
+func isBuiltinType(expr string) bool {
+	e, err := parser.ParseExpr("type", expr)
+	if err != nil {
+		return false
+	}
+	builtin := true
+	ast.Walk(e, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.Ident:
+			switch x.Name {
+			case "string", "bytes", "bool", "int", "float", "number", "null", "_",
+				"uint", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+			default:
+				builtin = false
+			}
+		case *ast.SelectorExpr, *ast.CallExpr:
+			builtin = false
+		}
+		return builtin
+	}, nil)
+	return builtin
+}
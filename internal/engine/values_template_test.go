@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"cuelang.org/go/cue"
+	. "github.com/onsi/gomega"
+)
+
+func TestValuesTemplate(t *testing.T) {
+	fields := []ConfigField{
+		{Path: cue.ParsePath("image"), Type: "struct", Doc: "Container image"},
+		{Path: cue.ParsePath("image.repository"), Type: "string", Required: true},
+		{Path: cue.ParsePath("image.tag"), Type: "string", Default: `"latest"`},
+		{Path: cue.ParsePath("team"), Type: "string", Doc: "Owner team", Required: true},
+		{Path: cue.ParsePath("size"), Type: "#Size", Required: true},
+		{Path: cue.ParsePath("replicas"), Type: "int & >0", Default: "1", Doc: "Number of pods\nper zone"},
+		{Path: cue.ParsePath("logLevel"), Type: `"debug" | "info"`, Default: `"info"`, Optional: true, Doc: "Log level"},
+		{Path: cue.ParsePath("resources"), Type: "struct", Optional: true},
+		{Path: cue.ParsePath("resources.cpu"), Type: "string", Default: `"100m"`},
+		{Path: cue.ParsePath("podLabels"), Type: "{[string]: string}"},
+		{Path: cue.ParsePath("tolerations"), Type: "[...string]"},
+		{Path: cue.ParsePath("priority"), Type: "int & >=0", Optional: true},
+		{Path: cue.ParsePath("affinity"), Type: "#Affinity", Optional: true},
+		{Path: cue.ParsePath(`labels."app.kubernetes.io/part-of"`), Type: "string", Default: `"app"`},
+		{Path: cue.ParsePath(`labels."app.kubernetes.io/version"`), Type: "string", Unresolved: true},
+		{Path: cue.ParsePath("internal"), Type: "bool", Default: "false", NoDoc: true},
+	}
+
+	tests := []struct {
+		name         string
+		format       string
+		onlyRequired bool
+		want         string
+	}{
+		{
+			name:   "cue",
+			format: "cue",
+			want: `values: {
+	// Container image
+	image: {
+		repository: string // required
+		tag:        "latest"
+	}
+	// Owner team
+	team: string // required
+	size: _      // required
+	// Number of pods
+	// per zone
+	replicas: 1
+	// Log level
+	// logLevel: "info"
+	// resources: {
+	// 	cpu: "100m"
+	// }
+	// podLabels: {[string]: string}
+	// tolerations: [...string]
+	// priority: int & >=0
+	// affinity: #Affinity
+	labels: {
+		"app.kubernetes.io/part-of": "app"
+	}
+}
+`,
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			want: `values:
+  # Container image
+  image:
+    repository: null # required, string
+    tag: "latest"
+  # Owner team
+  team: null # required, string
+  size: null # required, #Size
+  # Number of pods
+  # per zone
+  replicas: 1
+  # Log level
+  # logLevel: "info"
+  # resources:
+  #   cpu: "100m"
+  # podLabels: {} # {[string]: string}
+  # tolerations: [] # [...string]
+  # priority: 0 # int & >=0
+  # affinity: null # #Affinity
+  labels:
+    app.kubernetes.io/part-of: "app"
+`,
+		},
+		{
+			name:   "json",
+			format: "json",
+			want: `{
+  "values": {
+    "image": {
+      "repository": null,
+      "tag": "latest"
+    },
+    "team": null,
+    "size": null,
+    "replicas": 1,
+    "labels": {
+      "app.kubernetes.io/part-of": "app"
+    }
+  }
+}
+`,
+		},
+		{
+			name:         "only required",
+			format:       "yaml",
+			onlyRequired: true,
+			want: `values:
+  # Container image
+  image:
+    repository: null # required, string
+  # Owner team
+  team: null # required, string
+  size: null # required, #Size
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			out, err := FormatValuesTemplate(fields, tt.format, tt.onlyRequired)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(out).To(Equal(tt.want))
+		})
+	}
+
+	g := NewWithT(t)
+	_, err := FormatValuesTemplate(fields, "toml", false)
+	g.Expect(err).To(MatchError(ContainSubstring("unsupported values format")))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/stefanprodan/timoni/internal/dyff"
 	"github.com/stefanprodan/timoni/internal/engine"
 	"github.com/stefanprodan/timoni/internal/logger"
+	"github.com/stefanprodan/timoni/internal/sops"
 )
 
 func NewInteractiveReconciler(log logr.Logger, copts *CommonOptions, iopts *InteractiveOptions, timeout time.Duration) *InteractiveReconciler {
@@ -110,10 +111,20 @@ func (r *InteractiveReconciler) DryRunDiff(ctx context.Context, namespaceExists
 		namespaceExists,
 		r.opts.Dir,
 		r.Diff,
+		r.redact,
 		r.DiffOutput,
 	)
 }
 
+// redact masks the sensitive values of the module and
+// the data redacted by the Redact option.
+func (r *InteractiveReconciler) redact(data []byte) []byte {
+	if r.Redact != nil {
+		data = r.Redact(data)
+	}
+	return sops.RedactValues(data, r.sensitiveValues)
+}
+
 func (r *InteractiveReconciler) Wait(ctx context.Context, log logr.Logger, cs *ssa.ChangeSet, rs *engine.ResourceSet) error {
 	for _, change := range cs.Entries {
 		log.Info(logger.ColorizeJoin(change))
@@ -76,6 +76,17 @@ func (r *Reconciler) Init(ctx context.Context, builder *engine.ModuleBuilder, bu
 		return fmt.Errorf("failed to extract values: %w", err)
 	}
 
+	sensitivePaths, err := builder.GetSensitivePaths(buildResult)
+	if err != nil {
+		return fmt.Errorf("failed to extract sensitive values: %w", err)
+	}
+	r.sensitiveValues = builder.GetSensitiveValues(buildResult, sensitivePaths)
+
+	finalValues, err = engine.ProtectValues(finalValues, sensitivePaths, r.opts.AgeRecipients)
+	if err != nil {
+		return fmt.Errorf("failed to protect sensitive values: %w", err)
+	}
+
 	r.sets, err = builder.GetApplySets(buildResult)
 	if err != nil {
 		return fmt.Errorf("failed to extract objects: %w", err)
@@ -127,6 +138,13 @@ func (r *Reconciler) Init(ctx context.Context, builder *engine.ModuleBuilder, bu
 			r.instanceManager.Instance.Labels = make(map[string]string)
 		}
 		r.instanceManager.Instance.Labels[apiv1.BundleNameLabelKey] = instance.Bundle
+		if instance.ArtifactDigest != "" {
+			if r.instanceManager.Instance.Annotations == nil {
+				r.instanceManager.Instance.Annotations = make(map[string]string)
+			}
+			r.instanceManager.Instance.Labels[apiv1.BundleDigestLabelKey] = apiv1.BundleDigestLabelValue(instance.ArtifactDigest)
+			r.instanceManager.Instance.Annotations[apiv1.BundleDigestAnnotationKey] = instance.ArtifactDigest
+		}
 	}
 
 	for _, obj := range r.currentObjects {
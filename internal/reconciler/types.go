@@ -37,6 +37,10 @@ type CommonOptions struct {
 	Wait               bool
 	Force              bool
 	OverwriteOwnership bool
+
+	// AgeRecipients are the age public keys used to encrypt the sensitive
+	// values before storage. The sensitive values are redacted if empty.
+	AgeRecipients []string
 }
 
 type InteractiveOptions struct {
@@ -44,6 +48,10 @@ type InteractiveOptions struct {
 	Diff       bool
 	DiffOutput io.Writer
 
+	// Redact masks sensitive data in the diff output, such as the
+	// values decrypted from SOPS files.
+	Redact func([]byte) []byte
+
 	ProgressStart func(string) interface{ Stop() }
 }
 
@@ -73,6 +81,10 @@ type Reconciler struct {
 
 	// predecessorInventory is the inventory stored before the current run.
 	predecessorInventory *apiv1.ResourceInventory
+
+	// sensitiveValues are the values of the fields marked as sensitive
+	// in the module's #Config, which are masked in the diff output.
+	sensitiveValues []string
 }
 
 type InteractiveReconciler struct {
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+var (
+	bundleRunPrefix    = fmt.Sprintf("%s.bundle.", apiv1.FieldManager)
+	bundleRunDataKey   = "run"
+	bundleRunComponent = "bundle-run"
+)
+
+// ApplyBundleRun creates or updates the run record of a bundle. The record
+// is stored in a Secret labelled with the bundle name, in the namespace
+// specified by the run, which is created if it doesn't exist.
+func (s *StorageManager) ApplyBundleRun(ctx context.Context, run *apiv1.BundleRun) error {
+	run.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+	runData, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	if err := s.createNamespace(ctx, run.Namespace); err != nil {
+		return err
+	}
+
+	secret := s.newBundleRunSecret(run.Bundle, run.Namespace)
+	secret.Data = map[string][]byte{
+		bundleRunDataKey: runData,
+	}
+
+	opts := []client.PatchOption{
+		client.ForceOwnership,
+		client.FieldOwner(ownerRef.Field),
+	}
+	if err := s.resManager.Client().Patch(ctx, secret, client.Apply, opts...); err != nil {
+		return fmt.Errorf("saving bundle run failed: %w", err)
+	}
+	return nil
+}
+
+// GetBundleRun returns the last run record of the given bundle, searching
+// all namespaces, or nil if the bundle has no run record.
+func (s *StorageManager) GetBundleRun(ctx context.Context, bundle string) (*apiv1.BundleRun, error) {
+	secretList := &corev1.SecretList{}
+	err := s.resManager.Client().List(ctx, secretList, s.getBundleRunLabels(bundle))
+	if err != nil {
+		return nil, err
+	}
+
+	var result *apiv1.BundleRun
+	for _, secret := range secretList.Items {
+		data, ok := secret.Data[bundleRunDataKey]
+		if !ok {
+			return nil, fmt.Errorf("bundle run data not found in Secret/%s/%s",
+				secret.GetNamespace(), secret.GetName())
+		}
+
+		var run apiv1.BundleRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("invalid bundle run found in Secret/%s/%s: %w",
+				secret.GetNamespace(), secret.GetName(), err)
+		}
+		run.Namespace = secret.GetNamespace()
+
+		// RFC3339 timestamps in UTC sort lexically.
+		if result == nil || run.LastTransitionTime > result.LastTransitionTime {
+			result = &run
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteBundleRun removes the run records of the given bundle from all namespaces.
+func (s *StorageManager) DeleteBundleRun(ctx context.Context, bundle string) error {
+	secretList := &corev1.SecretList{}
+	err := s.resManager.Client().List(ctx, secretList, s.getBundleRunLabels(bundle))
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secretList.Items {
+		if err := s.resManager.Client().Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Secret/%s/%s: %w", secret.GetNamespace(), secret.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// getBundleRunLabels returns a label selector matching the run records of a bundle.
+func (s *StorageManager) getBundleRunLabels(bundle string) client.MatchingLabels {
+	return client.MatchingLabels{
+		componentLabelKey:        bundleRunComponent,
+		createdByLabelKey:        ownerRef.Field,
+		apiv1.BundleNameLabelKey: bundle,
+	}
+}
+
+func (s *StorageManager) newBundleRunSecret(bundle, namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bundleRunPrefix + bundle,
+			Namespace: namespace,
+			Labels: map[string]string{
+				nameLabelKey:             bundle,
+				componentLabelKey:        bundleRunComponent,
+				createdByLabelKey:        ownerRef.Field,
+				apiv1.BundleNameLabelKey: bundle,
+			},
+		},
+		Type: corev1.SecretType(apiv1.BundleRunStorageType),
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+)
+
+func TestBundleRunLifecycle(t *testing.T) {
+	g := NewWithT(t)
+	sm := newTestStorageManager()
+	ctx := context.Background()
+
+	run, err := sm.GetBundleRun(ctx, "my-bundle")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(run).To(BeNil())
+
+	run = &apiv1.BundleRun{
+		Bundle:    "my-bundle",
+		Namespace: "default",
+		Digest:    "sha256:abc",
+		Instances: []apiv1.BundleRunInstance{
+			{Name: "frontend", Namespace: "apps", Status: apiv1.BundleRunPending},
+			{Name: "backend", Namespace: "apps", Status: apiv1.BundleRunPending},
+		},
+	}
+	g.Expect(sm.ApplyBundleRun(ctx, run)).ToNot(HaveOccurred())
+
+	run.GetInstance("frontend", "apps").Status = apiv1.BundleRunApplied
+	run.GetInstance("frontend", "apps").Digest = "sha256:def"
+	g.Expect(sm.ApplyBundleRun(ctx, run)).ToNot(HaveOccurred())
+
+	got, err := sm.GetBundleRun(ctx, "my-bundle")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.Namespace).To(Equal("default"))
+	g.Expect(got.Digest).To(Equal("sha256:abc"))
+	g.Expect(got.LastTransitionTime).ToNot(BeEmpty())
+	g.Expect(got.CountInstances(apiv1.BundleRunApplied)).To(Equal(1))
+	g.Expect(got.GetInstance("frontend", "apps").Digest).To(Equal("sha256:def"))
+	g.Expect(got.GetInstance("backend", "apps").Status).To(Equal(apiv1.BundleRunPending))
+	g.Expect(got.GetInstance("backend", "other")).To(BeNil())
+
+	// The run record is not listed as an instance of the bundle.
+	instances, err := sm.List(ctx, "", "my-bundle")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(instances).To(BeEmpty())
+
+	other, err := sm.GetBundleRun(ctx, "other-bundle")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(other).To(BeNil())
+
+	g.Expect(sm.DeleteBundleRun(ctx, "my-bundle")).ToNot(HaveOccurred())
+	got, err = sm.GetBundleRun(ctx, "my-bundle")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(BeNil())
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/encoding/json"
+	"cuelang.org/go/encoding/yaml"
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/mattn/go-shellwords"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+const (
+	// EnvProvider is the name of the provider that reads environment variables.
+	EnvProvider = "env"
+
+	// FileProvider is the name of the provider that reads local files.
+	FileProvider = "file"
+
+	// ExecProvider is the name of the provider that runs commands.
+	ExecProvider = "exec"
+
+	// SOPSProvider is the name of the provider that reads SOPS encrypted files.
+	SOPSProvider = "sops"
+)
+
+// ErrValueNotFound is returned by the value providers
+// when the data referenced by a query doesn't exist.
+var ErrValueNotFound = errors.New("not found")
+
+// ValueProvider fetches the data from which the runtime values
+// are extracted with the CUE expressions of a query.
+type ValueProvider interface {
+	// Fetch returns the data referenced by the given query as a CUE value.
+	// If the data doesn't exist, the returned error must wrap ErrValueNotFound.
+	Fetch(ctx context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error)
+}
+
+// secretValueProvider is implemented by the providers
+// whose values are always hidden from the output.
+type secretValueProvider interface {
+	secret() bool
+}
+
+// DefaultValueProviders returns the built-in providers that don't
+// need access to a cluster, indexed by the query prefix. The sops
+// provider reads the age keys from the SOPS env vars.
+//
+// The env, file and exec providers are not included, as they can read
+// any environment variable and local file, and run any command. These
+// must be registered explicitly with NewEnvProvider, NewFileProvider
+// and NewExecProvider.
+func DefaultValueProviders() map[string]ValueProvider {
+	return map[string]ValueProvider{
+		SOPSProvider: NewSOPSProvider(sops.NewDecrypter("")),
+	}
+}
+
+// NewEnvProvider returns a provider that reads environment variables.
+func NewEnvProvider() ValueProvider {
+	return &envProvider{}
+}
+
+// NewFileProvider returns a provider that reads local files.
+func NewFileProvider() ValueProvider {
+	return &fileProvider{}
+}
+
+// NewExecProvider returns a provider that runs local commands.
+func NewExecProvider() ValueProvider {
+	return &execProvider{}
+}
+
+// kubernetesProvider fetches a Kubernetes resource, the query
+// is in the format 'k8s:<apiVersion>:<kind>:<namespace>:<name>'.
+// List queries return the matching resources as a list.
+type kubernetesProvider struct {
+	rm *ssa.ResourceManager
+}
+
+func (p *kubernetesProvider) Fetch(ctx context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	if p.rm == nil {
+		return v, errors.New("no cluster connection")
+	}
+
+	if ref.List {
+		return p.list(ctx, cuectx, ref)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	obj.SetName(ref.Name)
+	obj.SetNamespace(ref.Namespace)
+
+	objKey := client.ObjectKeyFromObject(obj)
+	if err := p.rm.Client().Get(ctx, objKey, obj); err != nil {
+		return v, err
+	}
+
+	v = cuectx.Encode(obj)
+	if v.Err() != nil {
+		return v, fmt.Errorf("encoding error: %w", v.Err())
+	}
+	return v, nil
+}
+
+// list fetches the resources matching the label and field selectors,
+// from all namespaces if the query has no namespace.
+func (p *kubernetesProvider) list(ctx context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	opts := []client.ListOption{client.InNamespace(ref.Namespace)}
+
+	if ref.LabelSelector != "" {
+		selector, err := labels.Parse(ref.LabelSelector)
+		if err != nil {
+			return v, fmt.Errorf("invalid label selector: %w", err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	if ref.FieldSelector != "" {
+		selector, err := fields.ParseSelector(ref.FieldSelector)
+		if err != nil {
+			return v, fmt.Errorf("invalid field selector: %w", err)
+		}
+		opts = append(opts, client.MatchingFieldsSelector{Selector: selector})
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(ref.APIVersion)
+	list.SetKind(ref.Kind + "List")
+	if err := p.rm.Client().List(ctx, list, opts...); err != nil {
+		return v, err
+	}
+
+	items := make([]map[string]any, 0, len(list.Items))
+	for _, item := range list.Items {
+		items = append(items, item.Object)
+	}
+
+	v = cuectx.Encode(items)
+	if v.Err() != nil {
+		return v, fmt.Errorf("encoding error: %w", v.Err())
+	}
+	return v, nil
+}
+
+// envProvider reads an environment variable as a string,
+// the query is in the format 'env:<name>'.
+type envProvider struct{}
+
+func (p *envProvider) Fetch(_ context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	value, ok := os.LookupEnv(ref.Source)
+	if !ok {
+		return v, fmt.Errorf("env var %s %w", ref.Source, ErrValueNotFound)
+	}
+	return cuectx.Encode(value), nil
+}
+
+// fileProvider reads a local file, the query is in the format
+// 'file:<path>[:<field path>]'. JSON and YAML files are decoded,
+// and the field path selects a value inside them. Any other
+// file is read as a string.
+type fileProvider struct{}
+
+func (p *fileProvider) Fetch(_ context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	file, field, _ := strings.Cut(ref.Source, apiv1.RuntimeDelimiter)
+
+	data, err := readFile(file)
+	if err != nil {
+		return v, err
+	}
+
+	switch ext := filepath.Ext(file); ext {
+	case ".json", ".yaml", ".yml":
+		return decodeFile(cuectx, file, data, ext == ".json", field)
+	default:
+		if field != "" {
+			return v, fmt.Errorf("field path not supported for %s, the file must be JSON or YAML", file)
+		}
+		return cuectx.Encode(string(data)), nil
+	}
+}
+
+// sopsProvider reads a SOPS encrypted JSON or YAML file, the query
+// is in the format 'sops:<path>[:<field path>]'. The decrypted data
+// is kept in memory and the values are hidden from the output.
+type sopsProvider struct {
+	decrypter *sops.Decrypter
+}
+
+// NewSOPSProvider returns a provider that decrypts
+// files with the given SOPS decrypter.
+func NewSOPSProvider(decrypter *sops.Decrypter) ValueProvider {
+	return &sopsProvider{decrypter: decrypter}
+}
+
+func (p *sopsProvider) Fetch(_ context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	file, field, _ := strings.Cut(ref.Source, apiv1.RuntimeDelimiter)
+
+	data, err := readFile(file)
+	if err != nil {
+		return v, err
+	}
+
+	plain, err := p.decrypter.Decrypt(data)
+	if err != nil {
+		return v, fmt.Errorf("failed to decrypt %s: %w", file, err)
+	}
+
+	// the decrypted document is always YAML
+	return decodeFile(cuectx, file, plain, false, field)
+}
+
+func (p *sopsProvider) secret() bool {
+	return true
+}
+
+// readFile returns the file content, or an error wrapping
+// ErrValueNotFound if the file doesn't exist.
+func readFile(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %s %w", file, ErrValueNotFound)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeFile decodes the JSON or YAML data and returns the value
+// at the given field path, or the whole document if the path is empty.
+func decodeFile(cuectx *cue.Context, file string, data []byte, isJSON bool, field string) (cue.Value, error) {
+	var v cue.Value
+	if isJSON {
+		expr, err := json.Extract(file, data)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		v = cuectx.BuildExpr(expr)
+	} else {
+		f, err := yaml.Extract(file, data)
+		if err != nil {
+			return v, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		v = cuectx.BuildFile(f)
+	}
+	if v.Err() != nil {
+		return v, fmt.Errorf("failed to decode %s: %w", file, v.Err())
+	}
+
+	if field != "" {
+		v = v.LookupPath(cue.ParsePath(field))
+		if !v.Exists() {
+			return v, fmt.Errorf("field %s in file %s %w", field, file, ErrValueNotFound)
+		}
+	}
+	return v, nil
+}
+
+// execProvider runs a command and decodes the JSON written to stdout,
+// the query is in the format 'exec:<command> [args...]'. The arguments
+// are split with the shell quoting rules, without running a shell.
+type execProvider struct{}
+
+func (p *execProvider) Fetch(ctx context.Context, cuectx *cue.Context, ref apiv1.RuntimeResourceRef) (cue.Value, error) {
+	var v cue.Value
+	args, err := shellwords.Parse(ref.Source)
+	if err != nil {
+		return v, fmt.Errorf("invalid command: %w", err)
+	}
+	if len(args) == 0 {
+		return v, errors.New("no command specified")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return v, fmt.Errorf("command %s %w", args[0], ErrValueNotFound)
+		}
+		return v, fmt.Errorf("command %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return v, fmt.Errorf("output of command %s %w", args[0], ErrValueNotFound)
+	}
+
+	expr, err := json.Extract(args[0], stdout.Bytes())
+	if err != nil {
+		return v, fmt.Errorf("failed to parse the output of command %s: %w", args[0], err)
+	}
+	v = cuectx.BuildExpr(expr)
+	if v.Err() != nil {
+		return v, fmt.Errorf("failed to decode the output of command %s: %w", args[0], v.Err())
+	}
+	return v, nil
+}
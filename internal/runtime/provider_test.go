@@ -0,0 +1,345 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+func TestReadValues_Providers(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "config.yaml")
+	err := os.WriteFile(yamlFile, []byte("spec:\n  host: example.com\n  port: 8080\n"), 0o644)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+	textFile := filepath.Join(dir, "token")
+	err = os.WriteFile(textFile, []byte("s3cr3t"), 0o644)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	t.Setenv("TIMONI_TEST_REGION", "eu-west-1")
+
+	tests := []struct {
+		name    string
+		value   apiv1.RuntimeValue
+		want    []Value
+		wantErr string
+	}{
+		{
+			name: "reads env var",
+			value: apiv1.RuntimeValue{
+				Query: "env:TIMONI_TEST_REGION",
+				For:   map[string]string{"REGION": "obj"},
+			},
+			want: []Value{{Name: "REGION", Value: "eu-west-1", Provider: "env", Query: "env:TIMONI_TEST_REGION"}},
+		},
+		{
+			name: "skips optional env var",
+			value: apiv1.RuntimeValue{
+				Query:    "env:TIMONI_TEST_MISSING",
+				For:      map[string]string{"MISSING": "obj"},
+				Optional: true,
+			},
+		},
+		{
+			name: "fails for missing env var",
+			value: apiv1.RuntimeValue{
+				Query: "env:TIMONI_TEST_MISSING",
+				For:   map[string]string{"MISSING": "obj"},
+			},
+			wantErr: "env var TIMONI_TEST_MISSING not found",
+		},
+		{
+			name: "reads field from YAML file",
+			value: apiv1.RuntimeValue{
+				Query: "file:" + yamlFile + ":spec",
+				For:   map[string]string{"HOST": "obj.host", "PORT": "obj.port"},
+			},
+			want: []Value{
+				{Name: "HOST", Value: "example.com", Provider: "file", Query: "file:" + yamlFile + ":spec"},
+				{Name: "PORT", Value: "8080", Provider: "file", Query: "file:" + yamlFile + ":spec"},
+			},
+		},
+		{
+			name: "skips optional field path",
+			value: apiv1.RuntimeValue{
+				Query:    "file:" + yamlFile + ":status",
+				For:      map[string]string{"HOST": "obj.host"},
+				Optional: true,
+			},
+		},
+		{
+			name: "reads text file",
+			value: apiv1.RuntimeValue{
+				Query:  "file:" + textFile,
+				For:    map[string]string{"TOKEN": "obj"},
+				Secret: true,
+			},
+			want: []Value{{Name: "TOKEN", Value: "s3cr3t", Provider: "file", Query: "file:" + textFile, Secret: true}},
+		},
+		{
+			name: "reads command output",
+			value: apiv1.RuntimeValue{
+				Query: `exec:echo '{"account":{"id":"1234"}}'`,
+				For:   map[string]string{"ACCOUNT_ID": "obj.account.id"},
+			},
+			want: []Value{{Name: "ACCOUNT_ID", Value: "1234", Provider: "exec", Query: `exec:echo '{"account":{"id":"1234"}}'`}},
+		},
+		{
+			name: "passes quoted arguments with spaces",
+			value: apiv1.RuntimeValue{
+				Query: `exec:printf '{"name":"%s"}' "my app"`,
+				For:   map[string]string{"NAME": "obj.name"},
+			},
+			want: []Value{{Name: "NAME", Value: "my app", Provider: "exec", Query: `exec:printf '{"name":"%s"}' "my app"`}},
+		},
+		{
+			name: "fails for failed command",
+			value: apiv1.RuntimeValue{
+				Query:    "exec:false",
+				For:      map[string]string{"ACCOUNT_ID": "obj.id"},
+				Optional: true,
+			},
+			wantErr: "command false failed",
+		},
+		{
+			name: "fails for unknown provider",
+			value: apiv1.RuntimeValue{
+				Query: "vault:secret/data/app",
+				For:   map[string]string{"TOKEN": "obj.token"},
+			},
+			wantErr: "unknown provider 'vault'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ref, err := tt.value.ToResourceRef()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			reader := NewResourceReader(nil)
+			reader.RegisterProvider(EnvProvider, NewEnvProvider())
+			reader.RegisterProvider(FileProvider, NewFileProvider())
+			reader.RegisterProvider(ExecProvider, NewExecProvider())
+			values, err := reader.ReadValues(context.Background(), []apiv1.RuntimeResourceRef{*ref})
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(values).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReadValues_LocalProvidersDisabled(t *testing.T) {
+	for _, query := range []string{"env:HOSTNAME", "file:/etc/hostname", "exec:hostname"} {
+		t.Run(query, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rv := apiv1.RuntimeValue{
+				Query: query,
+				For:   map[string]string{"HOST": "obj"},
+			}
+			ref, err := rv.ToResourceRef()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = NewResourceReader(nil).ReadValues(context.Background(), []apiv1.RuntimeResourceRef{*ref})
+			g.Expect(err).To(MatchError(ContainSubstring("is not enabled")))
+		})
+	}
+}
+
+func TestReadValues_SOPS(t *testing.T) {
+	g := NewWithT(t)
+
+	rv := apiv1.RuntimeValue{
+		Query: "sops:../sops/testdata/values.enc.yaml:redis",
+		For: map[string]string{
+			"REDIS_PASS": "obj.password",
+			"REDIS_PORT": "obj.port",
+		},
+	}
+	ref, err := rv.ToResourceRef()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reader := NewResourceReader(nil)
+	reader.RegisterProvider(SOPSProvider, NewSOPSProvider(sops.NewDecrypter("../sops/testdata/age.txt")))
+
+	values, err := reader.ReadValues(context.Background(), []apiv1.RuntimeResourceRef{*ref})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(HaveLen(2))
+	g.Expect(values[0]).To(Equal(Value{
+		Name:     "REDIS_PASS",
+		Value:    "s3cr3t-pass",
+		Provider: SOPSProvider,
+		Query:    rv.Query,
+		Secret:   true,
+	}))
+	g.Expect(values[1].Value).To(Equal("6379"))
+}
+
+func TestReadValues_KubernetesSecret(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	rm := newTestStorageManager().resManager
+	sc := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "redis-auth",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	g.Expect(rm.Client().Create(ctx, sc)).To(Succeed())
+
+	rv := apiv1.RuntimeValue{
+		Query: "k8s:v1:Secret:default:redis-auth",
+		For:   map[string]string{"REDIS_PASS": "obj.data.password"},
+	}
+	ref, err := rv.ToResourceRef()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	values, err := NewResourceReader(rm).ReadValues(ctx, []apiv1.RuntimeResourceRef{*ref})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(Equal([]Value{{
+		Name:     "REDIS_PASS",
+		Value:    "s3cr3t",
+		Provider: apiv1.RuntimeProviderKubernetes,
+		Query:    rv.Query,
+		Secret:   true,
+	}}))
+}
+
+func TestReadValues_KubernetesList(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	rm := newTestStorageManager().resManager
+	for i, ns := range []string{"apps", "apps", "infra"} {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("cm-%d", i),
+				Namespace: ns,
+				Labels:    map[string]string{"tier": ns},
+			},
+			Data: map[string]string{"host": fmt.Sprintf("host-%d.example.com", i)},
+		}
+		g.Expect(rm.Client().Create(ctx, cm)).To(Succeed())
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "lists all namespaces",
+			query: "k8s:v1:ConfigMap:*",
+			want:  "3",
+		},
+		{
+			name:  "lists namespace",
+			query: "k8s:v1:ConfigMap:infra:*",
+			want:  "1",
+		},
+		{
+			name:  "lists by label selector",
+			query: "k8s:v1:ConfigMap:selector=tier in (apps)",
+			want:  "2",
+		},
+		{
+			name:  "lists nothing",
+			query: "k8s:v1:ConfigMap:infra:selector=tier=apps",
+			want:  "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rv := apiv1.RuntimeValue{
+				Query: tt.query,
+				For:   map[string]string{"COUNT": "len(obj)"},
+			}
+			ref, err := rv.ToResourceRef()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ref.String()).To(Equal(tt.query))
+
+			values, err := NewResourceReader(rm).Read(ctx, []apiv1.RuntimeResourceRef{*ref})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(values).To(HaveKeyWithValue("COUNT", tt.want))
+		})
+	}
+
+	t.Run("extracts fields from the list", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rv := apiv1.RuntimeValue{
+			Query: "k8s:v1:ConfigMap:infra:*",
+			For:   map[string]string{"HOST": "obj[0].data.host"},
+		}
+		ref, err := rv.ToResourceRef()
+		g.Expect(err).ToNot(HaveOccurred())
+
+		values, err := NewResourceReader(rm).Read(ctx, []apiv1.RuntimeResourceRef{*ref})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(values).To(HaveKeyWithValue("HOST", "host-2.example.com"))
+	})
+
+	t.Run("extracts structs and lists as JSON", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rv := apiv1.RuntimeValue{
+			Query: "k8s:v1:ConfigMap:infra:*",
+			For: map[string]string{
+				"HOSTS":  "[for x in obj {x.data.host}]",
+				"LABELS": "obj[0].metadata.labels",
+			},
+		}
+		ref, err := rv.ToResourceRef()
+		g.Expect(err).ToNot(HaveOccurred())
+
+		values, err := NewResourceReader(rm).Read(ctx, []apiv1.RuntimeResourceRef{*ref})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(values).To(HaveKeyWithValue("HOSTS", `["host-2.example.com"]`))
+		g.Expect(values).To(HaveKeyWithValue("LABELS", `{"tier":"infra"}`))
+	})
+
+	t.Run("fails for invalid selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rv := apiv1.RuntimeValue{
+			Query: "k8s:v1:ConfigMap:infra:selector=tier=apps;labels=tier",
+			For:   map[string]string{"COUNT": "len(obj)"},
+		}
+		_, err := rv.ToResourceRef()
+		g.Expect(err).To(MatchError(ContainSubstring("invalid selector")))
+	})
+}
@@ -19,110 +19,163 @@ package runtime
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"github.com/fluxcd/pkg/ssa"
-	ssautil "github.com/fluxcd/pkg/ssa/utils"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
 )
 
-// ResourceReader fetches resources from the cluster and extract field values.
+// ResourceReader fetches resources from the cluster, or data from
+// the registered value providers, and extract field values.
 type ResourceReader struct {
-	rm *ssa.ResourceManager
+	rm        *ssa.ResourceManager
+	providers map[string]ValueProvider
 }
 
-// NewResourceReader creates a resource reader for the given cluster.
+// Value holds a runtime value along with the query it was extracted from.
+type Value struct {
+	// Name of the value.
+	Name string
+
+	// Value is the extracted value.
+	Value string
+
+	// Provider is the name of the provider that fetched the data.
+	Provider string
+
+	// Query is the query the value was extracted from.
+	Query string
+
+	// Secret is set if the value must not be printed.
+	Secret bool
+}
+
+// NewResourceReader creates a resource reader for the given cluster,
+// with the k8s provider and the default value providers registered.
 func NewResourceReader(resManager *ssa.ResourceManager) *ResourceReader {
-	return &ResourceReader{
-		rm: resManager,
+	r := &ResourceReader{
+		rm:        resManager,
+		providers: DefaultValueProviders(),
 	}
+	r.providers[apiv1.RuntimeProviderKubernetes] = &kubernetesProvider{rm: resManager}
+	return r
+}
+
+// RegisterProvider adds a value provider for the queries
+// with the given prefix, replacing any existing provider.
+func (r *ResourceReader) RegisterProvider(name string, provider ValueProvider) {
+	r.providers[name] = provider
 }
 
 // Read fetches the resources from the cluster and runs the CUE expressions
-// to select the desired values.
+// to select the desired values. When multiple queries define the same value,
+// the last one takes precedence.
 func (r *ResourceReader) Read(ctx context.Context, refs []apiv1.RuntimeResourceRef) (map[string]string, error) {
 	result := make(map[string]string)
 
+	values, err := r.ReadValues(ctx, refs)
+	for _, v := range values {
+		result[v.Name] = v.Value
+	}
+
+	return result, err
+}
+
+// ReadValues fetches the data of each query from its provider and runs
+// the CUE expressions to select the desired values. The values are
+// returned in the order of the queries.
+func (r *ResourceReader) ReadValues(ctx context.Context, refs []apiv1.RuntimeResourceRef) ([]Value, error) {
+	var result []Value
+
 	for _, ref := range refs {
-		obj, err := r.getObject(ctx, ref)
+		provider, ok := r.providers[ref.Provider]
+		if !ok && (ref.Provider == EnvProvider || ref.Provider == FileProvider || ref.Provider == ExecProvider) {
+			return result, fmt.Errorf("query error for %s: provider '%s' is not enabled", ref.String(), ref.Provider)
+		}
+		if !ok {
+			return result, fmt.Errorf("query error for %s: unknown provider '%s'", ref.String(), ref.Provider)
+		}
+
+		ct := cuecontext.New()
+		data, err := provider.Fetch(ctx, ct, ref)
 		if err != nil {
-			if ref.Optional && apierrors.IsNotFound(err) {
+			if ref.Optional && (apierrors.IsNotFound(err) || errors.Is(err, ErrValueNotFound)) {
 				continue
 			}
 
-			return result, fmt.Errorf("query error for %s: %w", ref.Name, err)
+			return result, fmt.Errorf("query error for %s: %w", ref.String(), err)
 		}
 
-		ct := cuecontext.New()
-		m, err := r.getValues(ct, obj, ref.Expressions)
+		m, err := r.getValues(ct, data, ref)
 		if err != nil {
-			return result, fmt.Errorf("can't extract values from %s: %w", ssautil.FmtUnstructured(obj), err)
+			return result, fmt.Errorf("can't extract values from %s: %w", ref.String(), err)
 		}
 
-		maps.Copy(result, m)
-	}
-
-	return result, nil
-}
+		secret := ref.Secret || isSecretRef(ref)
+		if sp, ok := provider.(secretValueProvider); ok && sp.secret() {
+			secret = true
+		}
 
-func (r *ResourceReader) getObject(ctx context.Context, in apiv1.RuntimeResourceRef) (*unstructured.Unstructured, error) {
-	obj := &unstructured.Unstructured{}
-	obj.SetAPIVersion(in.APIVersion)
-	obj.SetKind(in.Kind)
-	obj.SetName(in.Name)
-	obj.SetNamespace(in.Namespace)
-
-	objKey := client.ObjectKeyFromObject(obj)
-	err := r.rm.Client().Get(ctx, objKey, obj)
-	if err != nil {
-		return nil, err
+		keys := slices.Sorted(maps.Keys(m))
+		for _, key := range keys {
+			result = append(result, Value{
+				Name:     key,
+				Value:    m[key],
+				Provider: ref.Provider,
+				Query:    ref.String(),
+				Secret:   secret,
+			})
+		}
 	}
 
-	return obj, nil
+	return result, nil
 }
 
-func (r *ResourceReader) getValues(ctx *cue.Context, obj *unstructured.Unstructured, selectors map[string]string) (map[string]string, error) {
-	v := ctx.Encode(obj)
-	if v.Err() != nil {
-		return selectors, fmt.Errorf("encoding error: %w", v.Err())
-	}
+func (r *ResourceReader) getValues(ctx *cue.Context, v cue.Value, ref apiv1.RuntimeResourceRef) (map[string]string, error) {
+	// Secret data is base64 encoded
+	isSecret := isSecretRef(ref)
 
 	result := make(map[string]string)
-	for key, exp := range selectors {
+	for key, exp := range ref.Expressions {
 		shell := ctx.CompileString(fmt.Sprintf(`
 		obj: %v
 		out: %s
 	`, v, exp))
 		if shell.Err() != nil {
-			return selectors, fmt.Errorf("%s compile error: %w", key, shell.Err())
+			return result, fmt.Errorf("%s compile error: %w", key, shell.Err())
 		}
 
 		res := shell.LookupPath(cue.ParsePath("out"))
 		if res.Err() != nil {
-			return selectors, fmt.Errorf("%s lookup path error: %w", key, res.Err())
+			return result, fmt.Errorf("%s lookup path error: %w", key, res.Err())
 		}
 
 		switch res.IncompleteKind() {
 		case cue.StringKind:
 			s, _ := res.String()
 			result[key] = s
-		case cue.StructKind:
-			return result, fmt.Errorf("unsupported type retuned by '%s'", exp)
-		case cue.ListKind:
-			return result, fmt.Errorf("unsupported type retuned by '%s'", exp)
+		case cue.StructKind, cue.ListKind:
+			// Structs and lists are passed on as JSON, to be
+			// injected with the struct and list attribute types.
+			data, err := res.MarshalJSON()
+			if err != nil {
+				return result, fmt.Errorf("%s encoding error: %w", key, err)
+			}
+			result[key] = string(data)
+			continue
 		default:
 			result[key] = fmt.Sprintf("%v", res)
 		}
 
 		// Decode Secret data from base64
-		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		if isSecret {
 			if data, err := base64.StdEncoding.DecodeString(result[key]); err == nil {
 				result[key] = string(data)
 			}
@@ -131,3 +184,9 @@ func (r *ResourceReader) getValues(ctx *cue.Context, obj *unstructured.Unstructu
 
 	return result, nil
 }
+
+// isSecretRef returns true if the query selects Kubernetes Secrets,
+// whose values are always hidden from the output.
+func isSecretRef(ref apiv1.RuntimeResourceRef) bool {
+	return ref.Provider == apiv1.RuntimeProviderKubernetes && ref.APIVersion == "v1" && ref.Kind == "Secret"
+}
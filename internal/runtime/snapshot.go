@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+// NewSnapshot returns an empty snapshot of the given runtime.
+func NewSnapshot(name string) *apiv1.RuntimeSnapshot {
+	return &apiv1.RuntimeSnapshot{
+		APIVersion: apiv1.GroupVersion.String(),
+		Kind:       apiv1.RuntimeSnapshotKind,
+		Name:       name,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Clusters:   []apiv1.RuntimeSnapshotCluster{},
+	}
+}
+
+// NewSnapshotCluster returns the snapshot of the values read from a cluster,
+// where the last value with a given name takes precedence. The secret values
+// are encrypted for the age recipients, if any are specified.
+func NewSnapshotCluster(cluster apiv1.RuntimeCluster, values []Value, recipients []string) (*apiv1.RuntimeSnapshotCluster, error) {
+	sc := &apiv1.RuntimeSnapshotCluster{
+		Name:      cluster.Name,
+		Group:     cluster.Group,
+		Values:    make(map[string]string),
+		Secrets:   make(map[string]string),
+		Encrypted: len(recipients) > 0,
+	}
+
+	for _, v := range values {
+		delete(sc.Values, v.Name)
+		delete(sc.Secrets, v.Name)
+		if v.Secret {
+			sc.Secrets[v.Name] = v.Value
+		} else {
+			sc.Values[v.Name] = v.Value
+		}
+	}
+
+	if sc.Encrypted {
+		for k, v := range sc.Secrets {
+			enc, err := sops.EncryptValue(v, recipients)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s: %w", k, err)
+			}
+			sc.Secrets[k] = enc
+		}
+	}
+
+	return sc, nil
+}
+
+// SnapshotValues returns the values of a snapshot cluster,
+// decrypting the secret values with the given decrypter.
+func SnapshotValues(sc *apiv1.RuntimeSnapshotCluster, decrypter *sops.Decrypter) (map[string]string, error) {
+	result := make(map[string]string, len(sc.Values)+len(sc.Secrets))
+	maps.Copy(result, sc.Values)
+
+	for k, v := range sc.Secrets {
+		if sc.Encrypted {
+			plain, err := decrypter.DecryptValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s: %w", k, err)
+			}
+			v = plain
+		}
+		result[k] = v
+	}
+
+	return result, nil
+}
+
+// ReadSnapshot loads a runtime snapshot from the given JSON file.
+func ReadSnapshot(path string) (*apiv1.RuntimeSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runtime snapshot: %w", err)
+	}
+
+	var snapshot apiv1.RuntimeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse runtime snapshot %s: %w", path, err)
+	}
+
+	if snapshot.Kind != apiv1.RuntimeSnapshotKind || snapshot.APIVersion != apiv1.GroupVersion.String() {
+		return nil, fmt.Errorf("invalid runtime snapshot %s: expected %s %s",
+			path, apiv1.GroupVersion.String(), apiv1.RuntimeSnapshotKind)
+	}
+
+	return &snapshot, nil
+}
+
+// WriteSnapshot saves the runtime snapshot to the given JSON file.
+// The file is only readable by the current user, as it may
+// hold the secret values in plain text.
+func WriteSnapshot(path string, snapshot *apiv1.RuntimeSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write runtime snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/stefanprodan/timoni/api/v1alpha1"
+	"github.com/stefanprodan/timoni/internal/sops"
+)
+
+func TestSnapshot(t *testing.T) {
+	cluster := apiv1.RuntimeCluster{Name: "staging", Group: "non-prod", KubeContext: "kind-staging"}
+	values := []Value{
+		{Name: "REGION", Value: "eu-west-1"},
+		{Name: "REDIS_PASS", Value: "old-pass"},
+		{Name: "REDIS_PASS", Value: "s3cr3t-pass", Secret: true},
+	}
+
+	t.Run("writes secrets in plain text", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sc, err := NewSnapshotCluster(cluster, values, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sc.Encrypted).To(BeFalse())
+		g.Expect(sc.Values).To(Equal(map[string]string{"REGION": "eu-west-1"}))
+		g.Expect(sc.Secrets).To(Equal(map[string]string{"REDIS_PASS": "s3cr3t-pass"}))
+	})
+
+	t.Run("round-trips encrypted secrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sc, err := NewSnapshotCluster(cluster, values,
+			[]string{"age1eyxrzed9wzyp99tkmqwmqq8hdh60ak62pvadpwymg2jxgl2lvsgq7s0vfs"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sc.Encrypted).To(BeTrue())
+		g.Expect(sc.Secrets["REDIS_PASS"]).ToNot(ContainSubstring("s3cr3t-pass"))
+
+		snapshot := NewSnapshot("fleet")
+		snapshot.Clusters = append(snapshot.Clusters, *sc)
+
+		file := filepath.Join(t.TempDir(), "snapshot.json")
+		g.Expect(WriteSnapshot(file, snapshot)).To(Succeed())
+
+		fi, err := os.Stat(file)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		loaded, err := ReadSnapshot(file)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(loaded.Name).To(Equal("fleet"))
+		g.Expect(loaded.Runtime().SelectClusters("*", "non-prod")).To(HaveLen(1))
+
+		got := loaded.GetCluster("staging")
+		g.Expect(got).ToNot(BeNil())
+
+		result, err := SnapshotValues(got, sops.NewDecrypter("../sops/testdata/age.txt"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(map[string]string{
+			"REGION":     "eu-west-1",
+			"REDIS_PASS": "s3cr3t-pass",
+		}))
+	})
+
+	t.Run("rejects other files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		file := filepath.Join(t.TempDir(), "values.json")
+		g.Expect(os.WriteFile(file, []byte(`{"kind":"Bundle"}`), 0o644)).To(Succeed())
+
+		_, err := ReadSnapshot(file)
+		g.Expect(err).To(MatchError(ContainSubstring("invalid runtime snapshot")))
+	})
+}
@@ -77,6 +77,9 @@ func (s *StorageManager) Apply(ctx context.Context, instance *apiv1.Instance, cr
 	}
 
 	maps.Copy(secret.Labels, instance.Labels)
+	if len(instance.Annotations) > 0 {
+		secret.Annotations = maps.Clone(instance.Annotations)
+	}
 
 	opts := []client.PatchOption{
 		client.ForceOwnership,
@@ -218,9 +221,13 @@ func (s *StorageManager) SavePending(ctx context.Context, instance *apiv1.Instan
 		return fmt.Errorf("instance data not found in Secret/%s/%s", existing.GetNamespace(), existing.GetName())
 	}
 
-	// Keep the stored bytes and the secret labels untouched, so the bundle
-	// ownership label and the predecessor record survive the pending write.
+	// Keep the stored bytes, the secret labels and the bundle digest
+	// annotation untouched, so the bundle ownership and the predecessor
+	// record survive the pending write.
 	maps.Copy(secret.Labels, existing.Labels)
+	if digest, ok := existing.Annotations[apiv1.BundleDigestAnnotationKey]; ok {
+		secret.Annotations = map[string]string{apiv1.BundleDigestAnnotationKey: digest}
+	}
 
 	secret.Data = map[string][]byte{
 		storageDataKey: storedData,
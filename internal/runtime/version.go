@@ -18,10 +18,14 @@ package runtime
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -51,3 +55,49 @@ func ServerVersion(rcg genericclioptions.RESTClientGetter) (string, error) {
 
 	return ver.String(), nil
 }
+
+// ServerAPIVersions retrieves the API versions served by the Kubernetes server,
+// in the format '<group>/<version>' and '<group>/<version>/<kind>'. The
+// core API group is listed as 'v1'. Groups that fail discovery, such as
+// aggregated APIs with no healthy backend, are left out.
+func ServerAPIVersions(rcg genericclioptions.RESTClientGetter) ([]string, error) {
+	cfg, err := rcg.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig failed: %w", err)
+	}
+
+	cfg.Timeout = 5 * time.Second
+
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("initialising client failed: %w", err)
+	}
+
+	_, resources, err := kubeClient.Discovery().ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("reading server API versions failed: %w", err)
+	}
+
+	return apiVersionsFromResources(resources), nil
+}
+
+// apiVersionsFromResources returns the sorted list of group versions
+// and kinds of the given API resources, without subresources.
+func apiVersionsFromResources(resources []*metav1.APIResourceList) []string {
+	var versions []string
+	for _, list := range resources {
+		if list == nil {
+			continue
+		}
+		versions = append(versions, list.GroupVersion)
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			versions = append(versions, list.GroupVersion+"/"+r.Kind)
+		}
+	}
+
+	slices.Sort(versions)
+	return slices.Compact(versions)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtime
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAPIVersionsFromResources(t *testing.T) {
+	g := NewWithT(t)
+
+	resources := []*metav1.APIResourceList{
+		{
+			GroupVersion: "monitoring.coreos.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "servicemonitors", Kind: "ServiceMonitor"},
+				{Name: "servicemonitors/status", Kind: "ServiceMonitor"},
+			},
+		},
+		nil,
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap"},
+				{Name: "pods", Kind: "Pod"},
+				{Name: "pods/log", Kind: "Pod"},
+			},
+		},
+	}
+
+	g.Expect(apiVersionsFromResources(resources)).To(Equal([]string{
+		"monitoring.coreos.com/v1",
+		"monitoring.coreos.com/v1/ServiceMonitor",
+		"v1",
+		"v1/ConfigMap",
+		"v1/Pod",
+	}))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// EncryptValue encrypts the value for the given age recipients
+// and returns the ciphertext in the armored format.
+func EncryptValue(value string, recipients []string) (string, error) {
+	var rcpts []age.Recipient
+	for _, r := range recipients {
+		rcpt, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return "", fmt.Errorf("invalid age recipient %s: %w", r, err)
+		}
+		rcpts = append(rcpts, rcpt)
+	}
+
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, rcpts...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := aw.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// DecryptValue decrypts an armored age ciphertext with the age keys of
+// the Decrypter. The plain value is masked by Redact from then on.
+func (d *Decrypter) DecryptValue(value string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.identities == nil {
+		identities, err := loadIdentities(d.keyFile)
+		if err != nil {
+			return "", err
+		}
+		d.identities = identities
+	}
+
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(value)), d.identities...)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	if len(data) >= minRedactLength {
+		d.secrets[string(data)] = struct{}{}
+	}
+	return string(data), nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testRecipient = "age1eyxrzed9wzyp99tkmqwmqq8hdh60ak62pvadpwymg2jxgl2lvsgq7s0vfs"
+
+func TestEncryptValue(t *testing.T) {
+	g := NewWithT(t)
+
+	enc, err := EncryptValue("s3cr3t-pass", []string{testRecipient})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enc).To(HavePrefix("-----BEGIN AGE ENCRYPTED FILE-----"))
+	g.Expect(enc).ToNot(ContainSubstring("s3cr3t-pass"))
+
+	d := NewDecrypter("testdata/age.txt")
+	plain, err := d.DecryptValue(enc)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(plain).To(Equal("s3cr3t-pass"))
+	g.Expect(string(d.Redact([]byte("pass: s3cr3t-pass")))).To(Equal("pass: ***"))
+
+	_, err = EncryptValue("s3cr3t-pass", []string{"age1invalid"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid age recipient")))
+}
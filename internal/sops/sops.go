@@ -0,0 +1,433 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sops decrypts YAML and JSON documents encrypted by SOPS
+// with age keys, without depending on the sops binary. It also
+// encrypts and decrypts single values with age keys.
+package sops
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// AgeKeyFileEnv is the env var holding the path to the age keys file.
+	AgeKeyFileEnv = "SOPS_AGE_KEY_FILE"
+
+	// AgeKeyEnv is the env var holding the age keys.
+	AgeKeyEnv = "SOPS_AGE_KEY"
+
+	// metadataKey is the top-level field holding the SOPS metadata.
+	metadataKey = "sops"
+
+	// minRedactLength is the minimum length of the decrypted
+	// values that are masked by Redact.
+	minRedactLength = 4
+
+	// RedactedValue replaces the decrypted values in the redacted output.
+	RedactedValue = "***"
+)
+
+// encryptedValue matches the values encrypted by SOPS.
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// metadata holds the SOPS fields needed for decryption.
+type metadata struct {
+	Age []struct {
+		Recipient string `yaml:"recipient"`
+		Enc       string `yaml:"enc"`
+	} `yaml:"age"`
+	LastModified     string `yaml:"lastmodified"`
+	MAC              string `yaml:"mac"`
+	MACOnlyEncrypted bool   `yaml:"mac_only_encrypted"`
+}
+
+// IsEncrypted returns true if the given YAML or JSON
+// document contains the SOPS metadata.
+func IsEncrypted(data []byte) bool {
+	if !bytes.Contains(data, []byte(metadataKey)) {
+		return false
+	}
+
+	var doc struct {
+		Sops *metadata `yaml:"sops"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false
+	}
+	return doc.Sops != nil && doc.Sops.MAC != ""
+}
+
+// Decrypter decrypts SOPS documents and keeps track of the decrypted values,
+// so that they can be masked in the output of commands. It is safe for
+// concurrent use.
+type Decrypter struct {
+	keyFile string
+
+	mu         sync.Mutex
+	identities []age.Identity
+	secrets    map[string]struct{}
+}
+
+// NewDecrypter returns a Decrypter that reads the age keys from the given
+// file. If the file is not specified, the keys are read from the
+// SOPS_AGE_KEY env var, the file set with SOPS_AGE_KEY_FILE or, as the
+// sops CLI does, from 'sops/age/keys.txt' in the user config dir.
+// The keys are loaded when the first document is decrypted.
+func NewDecrypter(keyFile string) *Decrypter {
+	return &Decrypter{
+		keyFile: keyFile,
+		secrets: make(map[string]struct{}),
+	}
+}
+
+// Decrypt verifies and decrypts the given SOPS document, and returns
+// the plain document, as YAML, without the SOPS metadata.
+func (d *Decrypter) Decrypt(data []byte) ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("invalid document: expected a map")
+	}
+	root := doc.Content[0]
+
+	var meta *metadata
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value == metadataKey {
+			if err := root.Content[i+1].Decode(&meta); err != nil {
+				return nil, fmt.Errorf("invalid sops metadata: %w", err)
+			}
+			root.Content = slices.Delete(root.Content, i, i+2)
+			break
+		}
+	}
+	if meta == nil || meta.MAC == "" {
+		return nil, errors.New("sops metadata not found")
+	}
+
+	key, err := d.dataKey(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha512.New()
+	w := &walker{key: key, hash: hash, macOnlyEncrypted: meta.MACOnlyEncrypted}
+	if err := w.walk(root, nil); err != nil {
+		return nil, err
+	}
+
+	if err := verifyMAC(meta, key, hash.Sum(nil)); err != nil {
+		return nil, err
+	}
+
+	for _, s := range w.secrets {
+		if len(s) >= minRedactLength {
+			d.secrets[s] = struct{}{}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Redact replaces the values decrypted so far with a mask. Values
+// shorter than four characters are not masked.
+func (d *Decrypter) Redact(data []byte) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	secrets := make([]string, 0, len(d.secrets))
+	for s := range d.secrets {
+		secrets = append(secrets, s)
+	}
+	return RedactValues(data, secrets)
+}
+
+// RedactValues replaces the given values with a mask. Values
+// shorter than four characters are not masked.
+func RedactValues(data []byte, values []string) []byte {
+	secrets := make([]string, 0, len(values))
+	for _, s := range values {
+		if len(s) >= minRedactLength {
+			secrets = append(secrets, s)
+		}
+	}
+	// replace the longest values first, in case they contain the shorter ones
+	slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+
+	for _, s := range secrets {
+		data = bytes.ReplaceAll(data, []byte(s), []byte(RedactedValue))
+	}
+	return data
+}
+
+// dataKey decrypts the document data key with the age keys.
+func (d *Decrypter) dataKey(meta *metadata) ([]byte, error) {
+	if len(meta.Age) == 0 {
+		return nil, errors.New("no age recipients found in the sops metadata")
+	}
+
+	if d.identities == nil {
+		identities, err := loadIdentities(d.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		d.identities = identities
+	}
+
+	var errs []error
+	for _, stanza := range meta.Age {
+		r, err := age.Decrypt(armor.NewReader(strings.NewReader(stanza.Enc)), d.identities...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stanza.Recipient, err))
+			continue
+		}
+		key, err := io.ReadAll(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", stanza.Recipient, err))
+			continue
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("failed to decrypt the sops data key: %w", errors.Join(errs...))
+}
+
+// loadIdentities reads the age keys from the given file or from
+// the locations used by the sops CLI.
+func loadIdentities(keyFile string) ([]age.Identity, error) {
+	var data []byte
+	switch {
+	case keyFile != "":
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read age keys: %w", err)
+		}
+		data = b
+	case os.Getenv(AgeKeyEnv) != "":
+		data = []byte(os.Getenv(AgeKeyEnv))
+	case os.Getenv(AgeKeyFileEnv) != "":
+		b, err := os.ReadFile(os.Getenv(AgeKeyFileEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read age keys: %w", err)
+		}
+		data = b
+	default:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("no age keys found, set %s or the key file flag", AgeKeyFileEnv)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "sops", "age", "keys.txt"))
+		if err != nil {
+			return nil, fmt.Errorf("no age keys found, set %s or the key file flag", AgeKeyFileEnv)
+		}
+		data = b
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age keys: %w", err)
+	}
+	return identities, nil
+}
+
+// verifyMAC checks the document digest against the MAC stored in the metadata.
+func verifyMAC(meta *metadata, key, digest []byte) error {
+	lastModified, err := time.Parse(time.RFC3339, meta.LastModified)
+	if err != nil {
+		return fmt.Errorf("invalid sops lastmodified: %w", err)
+	}
+
+	mac, _, err := decryptValue(meta.MAC, key, lastModified.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the sops MAC: %w", err)
+	}
+
+	if mac != fmt.Sprintf("%X", digest) {
+		return errors.New("failed to verify the sops MAC, the document has been tampered with")
+	}
+	return nil
+}
+
+// walker decrypts the document values in place, in document order,
+// and computes the digest of the values the same way the sops CLI does.
+type walker struct {
+	key              []byte
+	hash             io.Writer
+	macOnlyEncrypted bool
+	secrets          []string
+}
+
+func (w *walker) walk(node *yaml.Node, path []string) error {
+	// comments are encrypted too, and they are not part of the MAC
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			k := node.Content[i]
+			k.HeadComment, k.LineComment, k.FootComment = "", "", ""
+			if err := w.walk(node.Content[i+1], append(path, k.Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if err := w.walk(item, path); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return w.decrypt(node, path)
+	}
+	return nil
+}
+
+func (w *walker) decrypt(node *yaml.Node, path []string) error {
+	if node.Tag == "!!null" {
+		return nil
+	}
+
+	if !encryptedValue.MatchString(node.Value) {
+		if w.macOnlyEncrypted {
+			return nil
+		}
+		var v any
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		_, err := w.hash.Write(toBytes(v))
+		return err
+	}
+
+	value, typ, err := decryptValue(node.Value, w.key, strings.Join(path, ":")+":")
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", strings.Join(path, "."), err)
+	}
+
+	node.Value = value
+	node.Style = 0
+	switch typ {
+	case "str", "bytes":
+		// quote the strings so that YAML 1.1 parsers don't read 'y' or 'no' as bool
+		node.Tag = "!!str"
+		node.Style = yaml.DoubleQuotedStyle
+		w.secrets = append(w.secrets, value)
+		_, err = w.hash.Write([]byte(value))
+	case "int":
+		node.Tag = "!!int"
+		_, err = w.hash.Write([]byte(value))
+	case "float":
+		node.Tag = "!!float"
+		f, perr := strconv.ParseFloat(value, 64)
+		if perr != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", strings.Join(path, "."), perr)
+		}
+		_, err = w.hash.Write(toBytes(f))
+	case "bool":
+		node.Tag = "!!bool"
+		b, perr := strconv.ParseBool(value)
+		if perr != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", strings.Join(path, "."), perr)
+		}
+		node.Value = strconv.FormatBool(b)
+		_, err = w.hash.Write(toBytes(b))
+	default:
+		return fmt.Errorf("failed to decrypt %s: unsupported type %s", strings.Join(path, "."), typ)
+	}
+	return err
+}
+
+// decryptValue decrypts a value encrypted with AES256_GCM
+// and returns its plain text and type.
+func decryptValue(value string, key []byte, additionalData string) (string, string, error) {
+	matches := encryptedValue.FindStringSubmatch(value)
+	if matches == nil {
+		return "", "", errors.New("invalid encrypted value")
+	}
+
+	parts := make([][]byte, 3)
+	for i := range parts {
+		b, err := base64.StdEncoding.DecodeString(matches[i+1])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid encrypted value: %w", err)
+		}
+		parts[i] = b
+	}
+	data, iv, tag := parts[0], parts[1], parts[2]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", "", err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return "", "", err
+	}
+
+	plain, err := gcm.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return "", "", fmt.Errorf("authentication failed: %w", err)
+	}
+	return string(plain), matches[4], nil
+}
+
+// toBytes returns the representation of a value used by sops to compute the MAC.
+func toBytes(v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return []byte(v)
+	case int:
+		return []byte(strconv.Itoa(v))
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		if v {
+			return []byte("True")
+		}
+		return []byte("False")
+	default:
+		return []byte(fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 Stefan Prodan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+func TestDecrypt(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want map[string]any
+	}{
+		{
+			name: "decrypts YAML",
+			file: "testdata/values.enc.yaml",
+			want: map[string]any{
+				"redis": map[string]any{
+					"password":         "s3cr3t-pass",
+					"port":             float64(6379),
+					"tls":              true,
+					"ratio":            0.5,
+					"hosts":            []any{"a.example.com", "b.example.com"},
+					"user_unencrypted": "admin",
+				},
+			},
+		},
+		{
+			name: "decrypts JSON",
+			file: "testdata/values.enc.json",
+			want: map[string]any{
+				"app": map[string]any{
+					"token":    "json-t0ken",
+					"replicas": float64(2),
+					"debug":    false,
+					"tags":     []any{"x", "y"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			data, err := os.ReadFile(tt.file)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(IsEncrypted(data)).To(BeTrue())
+
+			d := NewDecrypter("testdata/age.txt")
+			plain, err := d.Decrypt(data)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(IsEncrypted(plain)).To(BeFalse())
+			g.Expect(string(plain)).ToNot(ContainSubstring("ENC["))
+
+			var got map[string]any
+			g.Expect(yaml.Unmarshal(plain, &got)).To(Succeed())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := os.ReadFile("testdata/values.enc.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	t.Run("fails with the wrong key", func(t *testing.T) {
+		g := NewWithT(t)
+		keyFile := t.TempDir() + "/keys.txt"
+		err := os.WriteFile(keyFile, []byte("AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX\n"), 0o600)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = NewDecrypter(keyFile).Decrypt(data)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to decrypt the sops data key")))
+	})
+
+	t.Run("fails when values are tampered with", func(t *testing.T) {
+		g := NewWithT(t)
+		tampered := strings.Replace(string(data), "user_unencrypted: admin", "user_unencrypted: root", 1)
+
+		_, err := NewDecrypter("testdata/age.txt").Decrypt([]byte(tampered))
+		g.Expect(err).To(MatchError(ContainSubstring("failed to verify the sops MAC")))
+	})
+
+	t.Run("reads the key from env", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv(AgeKeyFileEnv, "testdata/age.txt")
+
+		_, err := NewDecrypter("").Decrypt(data)
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
+
+func TestRedact(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := os.ReadFile("testdata/values.enc.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	d := NewDecrypter("testdata/age.txt")
+	_, err = d.Decrypt(data)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	out := d.Redact([]byte("password: s3cr3t-pass\nhost: a.example.com\nuser: admin\n"))
+	g.Expect(string(out)).To(Equal("password: ***\nhost: ***\nuser: admin\n"))
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Stefan Prodan
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// #Rule defines a policy rule that Timoni evaluates against every
+// rendered Kubernetes object matching its target, before the objects
+// are applied. Timoni fills #object with the object and evaluates the
+// allow expression: when it evaluates to false, the object violates
+// the rule and the message is reported with the rule severity. An
+// expression that cannot be evaluated because the referenced fields
+// are missing from the object is a violation, unless allowIncomplete
+// is set.
+#Rule: {
+	// Target of the rule, matched against the object's API group and
+	// kind. The default '*' matches every group or kind, and the core
+	// API group is matched with an empty group.
+	match: {
+		group: *"*" | string
+		kind:  *"*" | string
+	}
+
+	// Severity of the violations: 'deny' fails the run before any
+	// object is applied, while 'warn' is only reported.
+	severity: *"deny" | "warn"
+
+	// Message reported for the objects violating the rule.
+	message!: string
+
+	// #object is filled by Timoni with the rendered object.
+	// Constrain it here to type the allow expression.
+	#object: {...}
+
+	// allow must evaluate to true when the object complies with the rule.
+	allow!: bool
+
+	// allowIncomplete makes an allow expression that cannot be evaluated,
+	// because the referenced fields are missing from the object, count
+	// as true i.e. the rule does not apply to the object.
+	allowIncomplete: *false | bool
+
+	...
+}
@@ -5,12 +5,13 @@ package v1alpha1
 
 import "strings"
 
-// RuntimeValue defines the schema for a Timoni runtime value
-// fetched from the in-cluster Kubernetes resources.
+// RuntimeValue defines the schema for a Timoni runtime value fetched
+// from the in-cluster Kubernetes resources or another value provider.
 #RuntimeValue: {
 	query: string
 	for: {[string & =~"^(([A-Za-z0-9][-A-Za-z0-9_]*)?[A-Za-z0-9])?$" & strings.MaxRunes(63) & strings.MinRunes(1)]: string}
 	optional: *false | bool
+	secret:   *false | bool
 }
 
 // Runtime defines the schema for a Timoni runtime that describes
@@ -0,0 +1,47 @@
+// Copyright 2026 Stefan Prodan
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// #TestCase defines a module test case run by 'timoni mod test'.
+// Each CUE file in the module's tests directory holds one test case,
+// named after the file. Timoni builds the module with the test values,
+// fills apply with the rendered Kubernetes objects, then checks that
+// every assertion evaluates to true.
+#TestCase: {
+	// Description of the test case, printed in the test reports.
+	description?: string
+
+	// Values overlay merged with the module's default values.
+	values?: {...}
+
+	// apply is filled by Timoni with the objects of each apply set
+	// e.g. 'apply: app: [...]', for the assertions to refer to.
+	apply: [string]: [...{...}]
+
+	// Assertions over the rendered objects, keyed by description.
+	// Every assertion must evaluate to true for the test to pass.
+	assert?: [string]: bool
+
+	// Substring of the error expected when building the module
+	// with the test values e.g. to test the values validation.
+	// When set, the build must fail for the test to pass.
+	expectError?: string
+
+	// Expected results of the module health checks for the given
+	// objects, which typically mock the live object status.
+	healthChecks?: [...#TestHealthCheck]
+}
+
+// #TestHealthCheck defines the expected health check result of an object.
+#TestHealthCheck: {
+	// Object evaluated by the health check matching its group and kind.
+	object: {
+		apiVersion: string
+		kind:       string
+		...
+	}
+
+	// Status returned by the health check.
+	status: "Current" | "InProgress" | "Failed"
+}
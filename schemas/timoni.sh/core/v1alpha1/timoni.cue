@@ -10,5 +10,59 @@ package v1alpha1
 	instance: {...}
 	apply: [string]: [...]
 	healthChecks?: [string]: #HealthCheck
+	requirements?: #ModuleRequirements
+	migrations?: [...#ValuesMigration]
+
+	// Minor version of the target cluster, computed by the module from
+	// instance.config.kubeVersion and constrained in CUE e.g. 'int & >=28'.
+	// The constraint is evaluated when the instance is built, prefer
+	// requirements.kubeVersion which Timoni checks against the cluster.
 	kubeMinorVersion?: int
 }
+
+// ModuleRequirements defines the Kubernetes and Timoni versions
+// supported by a module and the API groups it depends on.
+// Timoni checks the requirements against the target cluster
+// before applying an instance.
+#ModuleRequirements: {
+	// Range of supported Kubernetes versions e.g. min: "1.28.0".
+	// A max version without a patch number e.g. "1.32" includes
+	// all the patch releases of that minor version.
+	kubeVersion?: {
+		min?: string & =~"^v?\\d+\\.\\d+(\\.\\d+)?$"
+		max?: string & =~"^v?\\d+\\.\\d+(\\.\\d+)?$"
+	}
+
+	// Minimum version of the Timoni CLI e.g. min: "0.30.0".
+	timoniVersion?: {
+		min?: string & =~"^v?\\d+\\.\\d+(\\.\\d+)?$"
+	}
+
+	// API groups that must be served by the cluster,
+	// in the format '<group>' or '<group>/<version>'.
+	apiGroups?: [...string]
+}
+
+// ValuesMigration defines how the values written for previous versions
+// of a module are transformed into values for the current version.
+// Timoni runs the migrations matching the version of the installed
+// module, in order, on the values supplied to apply.
+#ValuesMigration: {
+	// Semver constraint matching the module versions the values
+	// were written for e.g. "<2.0.0" or ">=1.0.0 <2.0.0".
+	from: string
+
+	// Description of the changes, printed when the migration runs.
+	description?: string
+
+	// Fields to move, from the old path to the new path,
+	// e.g. "image.repo": "image.repository".
+	rename?: [string]: string
+
+	// The values to migrate, set by Timoni after the renames.
+	input: {...}
+
+	// The migrated values computed from the input.
+	// When not set, the input is returned as is.
+	output?: {...}
+}